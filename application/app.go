@@ -8,6 +8,7 @@ import (
 
 	"github.com/wiormiw/GrowBaks/config"
 	"github.com/wiormiw/GrowBaks/helper"
+	"github.com/wiormiw/GrowBaks/util"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -26,7 +27,9 @@ type App struct {
 
 	Logger     *logrus.Logger
 	DB         *pgx.Conn
+	JobDB      *pgx.Conn
 	HTTPClient *http.Client
+	Mailer     util.Mailer
 }
 
 // SetupApplication is a function to create application instance
@@ -49,16 +52,26 @@ func SetupApplication(ctx context.Context) (*App, error) {
 	app.Application = setupFiber(fiber.New())
 
 	// Local
-	app.DB, err = pgx.Connect(context.Background(), fmt.Sprintf("postgres://%s:%s@%s:%d/%s", app.Config.Database.DBUser, app.Config.Database.DBPassword, app.Config.Database.DBHost, app.Config.Database.DBPort, app.Config.Database.DBName))
+	dsn := fmt.Sprintf("postgres://%s:%s@%s:%d/%s", app.Config.Database.DBUser, app.Config.Database.DBPassword, app.Config.Database.DBHost, app.Config.Database.DBPort, app.Config.Database.DBName)
+	app.DB, err = pgx.Connect(context.Background(), dsn)
 
 	if err != nil {
 		app.Logger.Error("Failed connecting to databases, reason ", err)
 		return app, err
 	}
 
+	// pgx.Conn is not safe for concurrent use, background job gets its own connection
+	app.JobDB, err = pgx.Connect(context.Background(), dsn)
+
+	if err != nil {
+		app.Logger.Error("Failed connecting job to databases, reason ", err)
+		return app, err
+	}
+
 	app.Logger.Info("Success connecting to database...")
 
 	app.HTTPClient = &http.Client{}
+	app.Mailer = util.NewMailer(app.Config, app.Logger)
 
 	return app, nil
 }
@@ -103,6 +116,14 @@ func (app *App) Close() {
 		}
 	}
 
+	if app.JobDB != nil {
+		err := app.JobDB.Close(context.Background())
+		if err != nil {
+			app.Logger.Error("Failed close job database connection ", err)
+			panic(err)
+		}
+	}
+
 	if app.HTTPClient != nil {
 		app.HTTPClient.CloseIdleConnections()
 	}
@@ -2,8 +2,10 @@ package application
 
 import (
 	"github.com/wiormiw/GrowBaks/controller"
+	"github.com/wiormiw/GrowBaks/middleware"
 	"github.com/wiormiw/GrowBaks/repository"
 	"github.com/wiormiw/GrowBaks/service"
+	"github.com/wiormiw/GrowBaks/util"
 )
 
 // Dependency can contain anything that will provide data for controller layer
@@ -14,6 +16,19 @@ type Dependency struct {
 	UserController        controller.IUserController
 	ProductController     controller.IProductController
 	PemesananController   controller.IPemesananController
+	CartController        controller.ICartController
+	BlacklistController   controller.IBlacklistController
+	MFAController         controller.IMFAController
+	RoleController        controller.IRoleController
+	LocationController    controller.ILocationController
+	ScheduleController    controller.IScheduleController
+	JWTMiddleware         *middleware.JWTMiddleware
+}
+
+// JobDependency contains anything that will be run periodically by job runner
+type JobDependency struct {
+	PurgeSvc    service.IPurgeService
+	ScheduleSvc service.IScheduleService
 }
 
 // SetupDependencyInjection is a function to set up dependencies
@@ -25,6 +40,13 @@ func SetupDependencyInjection(app *App) *Dependency {
 		UserController:        setupUserDependency(app),
 		ProductController:     setupProductDependency(app),
 		PemesananController:   setupPemesananDependency(app),
+		CartController:        setupCartDependency(app),
+		BlacklistController:   setupBlacklistDependency(app),
+		MFAController:         setupMFADependency(app),
+		RoleController:        setupRoleDependency(app),
+		LocationController:    setupLocationDependency(app),
+		ScheduleController:    setupScheduleDependency(app),
+		JWTMiddleware:         setupJWTMiddlewareDependency(app),
 	}
 }
 
@@ -86,14 +108,41 @@ func setupAuthDependency(app *App) *controller.AuthController {
 		DB:      app.DB,
 	}
 
+	blacklistRepo := &repository.BlacklistRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	mfaRepo := &repository.MFARepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
 	authSvc := &service.AuthService{
-		Context:      app.Context,
-		Config:       app.Config,
-		Logger:       app.Logger,
-		AuthRepo:     authRepo,
-		RoleRepo:     roleRepo,
-		LocationRepo: locationRepo,
-		LapakRepo:    lapakRepo,
+		Context:       app.Context,
+		Config:        app.Config,
+		Logger:        app.Logger,
+		AuthRepo:      authRepo,
+		RoleRepo:      roleRepo,
+		LocationRepo:  locationRepo,
+		LapakRepo:     lapakRepo,
+		BlacklistRepo: blacklistRepo,
+		MFARepo:       mfaRepo,
+		Mailer:        app.Mailer,
+		GoogleOAuth: &util.OAuthProvider{
+			ClientID:     app.Config.OAuth.GoogleClientID,
+			ClientSecret: app.Config.OAuth.GoogleClientSecret,
+			RedirectURL:  app.Config.OAuth.GoogleRedirectURL,
+			AuthURL:      app.Config.OAuth.GoogleAuthURL,
+			TokenURL:     app.Config.OAuth.GoogleTokenURL,
+			UserInfoURL:  app.Config.OAuth.GoogleUserInfoURL,
+			Scopes:       []string{"openid", "email", "profile"},
+			HTTPClient:   app.HTTPClient,
+		},
 	}
 
 	authCtrl := &controller.AuthController{
@@ -121,12 +170,37 @@ func setupLapakDependency(app *App) *controller.LapakController {
 		DB:      app.DB,
 	}
 
+	blacklistRepo := &repository.BlacklistRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	locationRepo := &repository.LocationRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	roleRepo := &repository.RoleRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
 	lapakSvc := &service.LapakService{
-		Context:   app.Context,
-		Config:    app.Config,
-		Logger:    app.Logger,
-		LapakRepo: lapakRepo,
-		UserRepo:  userRepo,
+		Context:       app.Context,
+		Config:        app.Config,
+		Logger:        app.Logger,
+		LapakRepo:     lapakRepo,
+		UserRepo:      userRepo,
+		BlacklistRepo: blacklistRepo,
+		LocationRepo:  locationRepo,
+		RoleRepo:      roleRepo,
+		Mailer:        app.Mailer,
 	}
 
 	lapakCtrl := &controller.LapakController{
@@ -148,11 +222,28 @@ func setupUserDependency(app *App) *controller.UserController {
 		DB:      app.DB,
 	}
 
+	locationRepo := &repository.LocationRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	authRepo := &repository.AuthRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
 	userSvc := &service.UserService{
-		Context:  app.Context,
-		Config:   app.Config,
-		Logger:   app.Logger,
-		UserRepo: userRepo,
+		Context:      app.Context,
+		Config:       app.Config,
+		Logger:       app.Logger,
+		UserRepo:     userRepo,
+		LocationRepo: locationRepo,
+		AuthRepo:     authRepo,
+		Mailer:       app.Mailer,
 	}
 
 	userCtrl := &controller.UserController{
@@ -181,12 +272,28 @@ func setupProductDependency(app *App) *controller.ProductController {
 		DB:      app.DB,
 	}
 
+	lapakRepo := &repository.LapakRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	scheduleRepo := &repository.ScheduleRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
 	productSvc := &service.ProductService{
-		Context:     app.Context,
-		Config:      app.Config,
-		Logger:      app.Logger,
-		ProductRepo: productRepo,
-		UserRepo:    userRepo,
+		Context:      app.Context,
+		Config:       app.Config,
+		Logger:       app.Logger,
+		ProductRepo:  productRepo,
+		UserRepo:     userRepo,
+		LapakRepo:    lapakRepo,
+		ScheduleRepo: scheduleRepo,
 	}
 
 	productCtrl := &controller.ProductController{
@@ -222,6 +329,13 @@ func setupPemesananDependency(app *App) *controller.PemesananController {
 		DB:      app.DB,
 	}
 
+	lapakRepo := &repository.LapakRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
 	pemesananSvc := &service.PemesananService{
 		Context:       app.Context,
 		Config:        app.Config,
@@ -229,6 +343,7 @@ func setupPemesananDependency(app *App) *controller.PemesananController {
 		PemesananRepo: pemesananRepo,
 		UserRepo:      userRepo,
 		ProductRepo:   productRepo,
+		LapakRepo:     lapakRepo,
 	}
 
 	pemesananCtrl := &controller.PemesananController{
@@ -240,3 +355,318 @@ func setupPemesananDependency(app *App) *controller.PemesananController {
 
 	return pemesananCtrl
 }
+
+// setupCartDependency is a function to set up dependencies to be used inside cart controller layer
+func setupCartDependency(app *App) *controller.CartController {
+	cartRepo := &repository.CartRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	productRepo := &repository.ProductRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	pemesananRepo := &repository.PemesananRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	userRepo := &repository.UserRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	cartSvc := &service.CartService{
+		Context:       app.Context,
+		Config:        app.Config,
+		Logger:        app.Logger,
+		CartRepo:      cartRepo,
+		ProductRepo:   productRepo,
+		PemesananRepo: pemesananRepo,
+		UserRepo:      userRepo,
+	}
+
+	cartCtrl := &controller.CartController{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		CartSvc: cartSvc,
+	}
+
+	return cartCtrl
+}
+
+// setupBlacklistDependency is a function to set up dependencies to be used inside blacklist controller layer
+func setupBlacklistDependency(app *App) *controller.BlacklistController {
+	blacklistRepo := &repository.BlacklistRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	userRepo := &repository.UserRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	blacklistSvc := &service.BlacklistService{
+		Context:       app.Context,
+		Config:        app.Config,
+		Logger:        app.Logger,
+		BlacklistRepo: blacklistRepo,
+		UserRepo:      userRepo,
+	}
+
+	blacklistCtrl := &controller.BlacklistController{
+		Context:      app.Context,
+		Config:       app.Config,
+		Logger:       app.Logger,
+		BlacklistSvc: blacklistSvc,
+	}
+
+	return blacklistCtrl
+}
+
+// setupMFADependency is a function to set up dependencies to be used inside mfa controller layer
+func setupMFADependency(app *App) *controller.MFAController {
+	mfaRepo := &repository.MFARepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	mfaSvc := &service.MFAService{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		MFARepo: mfaRepo,
+	}
+
+	mfaCtrl := &controller.MFAController{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		MFASvc:  mfaSvc,
+	}
+
+	return mfaCtrl
+}
+
+// setupRoleDependency is a function to set up dependencies to be used inside role controller layer
+func setupRoleDependency(app *App) *controller.RoleController {
+	roleRepo := &repository.RoleRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	permissionRepo := &repository.PermissionRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	roleSvc := &service.RoleService{
+		Context:        app.Context,
+		Config:         app.Config,
+		Logger:         app.Logger,
+		RoleRepo:       roleRepo,
+		PermissionRepo: permissionRepo,
+	}
+
+	roleCtrl := &controller.RoleController{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		RoleSvc: roleSvc,
+	}
+
+	return roleCtrl
+}
+
+// setupLocationDependency is a function to set up dependencies to be used inside location controller layer
+func setupLocationDependency(app *App) *controller.LocationController {
+	locationRepo := &repository.LocationRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	locationSvc := &service.LocationService{
+		Context:      app.Context,
+		Config:       app.Config,
+		Logger:       app.Logger,
+		LocationRepo: locationRepo,
+	}
+
+	locationCtrl := &controller.LocationController{
+		Context:     app.Context,
+		Config:      app.Config,
+		Logger:      app.Logger,
+		LocationSvc: locationSvc,
+	}
+
+	return locationCtrl
+}
+
+// setupJWTMiddlewareDependency is a function to set up dependencies to be used inside jwt middleware
+func setupJWTMiddlewareDependency(app *App) *middleware.JWTMiddleware {
+	blacklistRepo := &repository.BlacklistRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	userRepo := &repository.UserRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	blacklistSvc := &service.BlacklistService{
+		Context:       app.Context,
+		Config:        app.Config,
+		Logger:        app.Logger,
+		BlacklistRepo: blacklistRepo,
+		UserRepo:      userRepo,
+	}
+
+	roleRepo := &repository.RoleRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	permissionRepo := &repository.PermissionRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	roleSvc := &service.RoleService{
+		Context:        app.Context,
+		Config:         app.Config,
+		Logger:         app.Logger,
+		RoleRepo:       roleRepo,
+		PermissionRepo: permissionRepo,
+	}
+
+	jwtMiddleware := &middleware.JWTMiddleware{
+		Context:      app.Context,
+		Config:       app.Config,
+		Logger:       app.Logger,
+		BlacklistSvc: blacklistSvc,
+		RoleSvc:      roleSvc,
+	}
+
+	return jwtMiddleware
+}
+
+// setupScheduleDependency is a function to set up dependencies to be used inside schedule controller layer
+func setupScheduleDependency(app *App) *controller.ScheduleController {
+	scheduleRepo := &repository.ScheduleRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	lapakRepo := &repository.LapakRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.DB,
+	}
+
+	scheduleSvc := &service.ScheduleService{
+		Context:      app.Context,
+		Config:       app.Config,
+		Logger:       app.Logger,
+		ScheduleRepo: scheduleRepo,
+		LapakRepo:    lapakRepo,
+	}
+
+	scheduleCtrl := &controller.ScheduleController{
+		Context:     app.Context,
+		Config:      app.Config,
+		Logger:      app.Logger,
+		ScheduleSvc: scheduleSvc,
+	}
+
+	return scheduleCtrl
+}
+
+// SetupJobDependencyInjection is a function to set up dependencies of background job, using its own database connection
+func SetupJobDependencyInjection(app *App) *JobDependency {
+	return &JobDependency{
+		PurgeSvc:    setupPurgeDependency(app),
+		ScheduleSvc: setupScheduleJobDependency(app),
+	}
+}
+
+// setupPurgeDependency is a function to set up dependencies to be used inside purge job
+func setupPurgeDependency(app *App) *service.PurgeService {
+	purgeRepo := &repository.PurgeRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.JobDB,
+	}
+
+	purgeSvc := &service.PurgeService{
+		Context:   app.Context,
+		Config:    app.Config,
+		Logger:    app.Logger,
+		PurgeRepo: purgeRepo,
+	}
+
+	return purgeSvc
+}
+
+// setupScheduleJobDependency is a function to set up dependencies to be used inside schedule job
+func setupScheduleJobDependency(app *App) *service.ScheduleService {
+	scheduleRepo := &repository.ScheduleRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.JobDB,
+	}
+
+	lapakRepo := &repository.LapakRepository{
+		Context: app.Context,
+		Config:  app.Config,
+		Logger:  app.Logger,
+		DB:      app.JobDB,
+	}
+
+	scheduleSvc := &service.ScheduleService{
+		Context:      app.Context,
+		Config:       app.Config,
+		Logger:       app.Logger,
+		ScheduleRepo: scheduleRepo,
+		LapakRepo:    lapakRepo,
+	}
+
+	return scheduleSvc
+}
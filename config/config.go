@@ -10,6 +10,8 @@ type (
 		Const    *Constants
 		Database *Database
 		Secret   *Secret
+		Mail     *Mail
+		OAuth    *OAuth
 	}
 
 	// Constants is used To store configurable value, not the constant-constant value
@@ -17,6 +19,16 @@ type (
 		HTTPPort      int
 		ENV           string
 		CloudinaryUrl string
+		FrontendURL   string
+
+		RequireEmailVerification bool
+
+		// soft deleted record is purged after SoftDeleteRetentionDays, checked every PurgeIntervalMinutes
+		SoftDeleteRetentionDays int
+		PurgeIntervalMinutes    int
+
+		// lapak having opening hours is opened & closed by scheduler checked every ScheduleIntervalMinutes
+		ScheduleIntervalMinutes int
 	}
 
 	// Database configuration
@@ -32,6 +44,27 @@ type (
 	Secret struct {
 		JWTSecret string
 	}
+
+	// Mail configuration, Driver is either "smtp" or "file" (Dir empty means printed into console)
+	Mail struct {
+		Driver       string
+		SMTPHost     string
+		SMTPPort     int
+		SMTPUser     string
+		SMTPPassword string
+		From         string
+		Dir          string
+	}
+
+	// OAuth configuration, provider endpoints are configurable for testing against local OIDC server
+	OAuth struct {
+		GoogleClientID     string
+		GoogleClientSecret string
+		GoogleRedirectURL  string
+		GoogleAuthURL      string
+		GoogleTokenURL     string
+		GoogleUserInfoURL  string
+	}
 )
 
 // LoadConfiguration...
@@ -40,6 +73,8 @@ func LoadConfiguration() *Configuration {
 		Const:    loadConstants(),
 		Database: loadDatabase(),
 		Secret:   loadSecret(),
+		Mail:     loadMail(),
+		OAuth:    loadOAuth(),
 	}
 }
 
@@ -49,6 +84,14 @@ func loadConstants() *Constants {
 		HTTPPort:      helper.GetEnvInt("PORT"),
 		ENV:           helper.GetEnvString("ENV"),
 		CloudinaryUrl: helper.GetEnvString("CLOUDINARY_URL"),
+		FrontendURL:   helper.GetEnvString("FRONTEND_URL"),
+
+		RequireEmailVerification: helper.GetEnvBool("REQUIRE_EMAIL_VERIFICATION"),
+
+		SoftDeleteRetentionDays: helper.GetEnvInt("SOFT_DELETE_RETENTION_DAYS"),
+		PurgeIntervalMinutes:    helper.GetEnvInt("PURGE_INTERVAL_MINUTES"),
+
+		ScheduleIntervalMinutes: helper.GetEnvInt("SCHEDULE_INTERVAL_MINUTES"),
 	}
 }
 
@@ -69,3 +112,28 @@ func loadSecret() *Secret {
 		JWTSecret: helper.GetEnvString("JWT_SECRET"),
 	}
 }
+
+// loadMail...
+func loadMail() *Mail {
+	return &Mail{
+		Driver:       helper.GetEnvString("MAIL_DRIVER"),
+		SMTPHost:     helper.GetEnvString("SMTP_HOST"),
+		SMTPPort:     helper.GetEnvInt("SMTP_PORT"),
+		SMTPUser:     helper.GetEnvString("SMTP_USER"),
+		SMTPPassword: helper.GetEnvString("SMTP_PASSWORD"),
+		From:         helper.GetEnvString("MAIL_FROM"),
+		Dir:          helper.GetEnvString("MAIL_DIR"),
+	}
+}
+
+// loadOAuth...
+func loadOAuth() *OAuth {
+	return &OAuth{
+		GoogleClientID:     helper.GetEnvString("GOOGLE_CLIENT_ID"),
+		GoogleClientSecret: helper.GetEnvString("GOOGLE_CLIENT_SECRET"),
+		GoogleRedirectURL:  helper.GetEnvString("GOOGLE_REDIRECT_URL"),
+		GoogleAuthURL:      helper.GetEnvString("GOOGLE_AUTH_URL"),
+		GoogleTokenURL:     helper.GetEnvString("GOOGLE_TOKEN_URL"),
+		GoogleUserInfoURL:  helper.GetEnvString("GOOGLE_USERINFO_URL"),
+	}
+}
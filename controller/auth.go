@@ -11,6 +11,7 @@ import (
 	"github.com/wiormiw/GrowBaks/helper"
 	"github.com/wiormiw/GrowBaks/model"
 	"github.com/wiormiw/GrowBaks/service"
+	"github.com/wiormiw/GrowBaks/util"
 )
 
 type (
@@ -18,6 +19,15 @@ type (
 	IAuthController interface {
 		RegisterAuthor(ctx *fiber.Ctx) error
 		Login(ctx *fiber.Ctx) error
+		LoginMFA(ctx *fiber.Ctx) error
+		Refresh(ctx *fiber.Ctx) error
+		Logout(ctx *fiber.Ctx) error
+		ForgotPassword(ctx *fiber.Ctx) error
+		ResetPassword(ctx *fiber.Ctx) error
+		VerifyEmail(ctx *fiber.Ctx) error
+		ResendVerification(ctx *fiber.Ctx) error
+		GoogleLogin(ctx *fiber.Ctx) error
+		GoogleCallback(ctx *fiber.Ctx) error
 	}
 
 	// AuthController is an app auth struct that consists of all the dependencies needed for auth controller
@@ -59,7 +69,7 @@ func (ac *AuthController) Login(ctx *fiber.Ctx) error {
 		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
 	}
 
-	jwt, err := ac.AuthSvc.LoginSvc(loginReq)
+	jwt, mfaChallenge, err := ac.AuthSvc.LoginSvc(loginReq)
 	if err != nil {
 		if errors.Is(err, model.ErrUserNotFound) {
 			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
@@ -69,8 +79,222 @@ func (ac *AuthController) Login(ctx *fiber.Ctx) error {
 			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
 		}
 
+		if errors.Is(err, model.ErrUserBlacklisted) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, model.ErrUserBlacklisted, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	if mfaChallenge != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Two-Factor Authentication Required, continue through /v1/auth/login/mfa", mfaChallenge)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Login", jwt)
+}
+
+// LoginMFA responsible to finishing two-step log-in with mfa code from controller layer
+func (ac *AuthController) LoginMFA(ctx *fiber.Ctx) error {
+	var mfaLoginReq model.MFALoginRequest
+
+	if err := ctx.BodyParser(&mfaLoginReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	jwt, err := ac.AuthSvc.LoginMFASvc(mfaLoginReq)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) || errors.Is(err, model.ErrInvalidMFACode) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrInvalidMFAToken) || errors.Is(err, model.ErrUserNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusUnauthorized, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrUserBlacklisted) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, model.ErrUserBlacklisted, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Login", jwt)
+}
+
+// Refresh responsible to exchanging a refresh token into a new token pair from controller layer
+func (ac *AuthController) Refresh(ctx *fiber.Ctx) error {
+	var refreshReq model.RefreshTokenRequest
+
+	if err := ctx.BodyParser(&refreshReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	jwt, err := ac.AuthSvc.RefreshSvc(refreshReq)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrInvalidRefreshToken) || errors.Is(err, model.ErrUserNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusUnauthorized, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrUserBlacklisted) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, model.ErrUserBlacklisted, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Refresh Token", jwt)
+}
+
+// Logout responsible to revoking current session from controller layer
+func (ac *AuthController) Logout(ctx *fiber.Ctx) error {
+	var logoutReq model.LogoutRequest
+
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&logoutReq); err != nil {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+		}
+	}
+
+	data := ctx.Locals(model.KeyJWTValidAccess)
+	extData, err := util.ExtractPayloadJWT(data)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = ac.AuthSvc.LogoutSvc(extData, logoutReq)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidToken) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusUnauthorized, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Logout", nil)
+}
+
+// ForgotPassword responsible to requesting reset password email from controller layer
+func (ac *AuthController) ForgotPassword(ctx *fiber.Ctx) error {
+	var forgotReq model.ForgotPasswordRequest
+
+	if err := ctx.BodyParser(&forgotReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	err := ac.AuthSvc.ForgotPasswordSvc(forgotReq)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "If the email is registered, a reset password link has been sent", nil)
+}
+
+// ResetPassword responsible to resetting password with reset token from controller layer
+func (ac *AuthController) ResetPassword(ctx *fiber.Ctx) error {
+	var resetReq model.ResetPasswordRequest
+
+	if err := ctx.BodyParser(&resetReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	err := ac.AuthSvc.ResetPasswordSvc(resetReq)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) || errors.Is(err, model.ErrInvalidResetToken) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Reset Password", nil)
+}
+
+// VerifyEmail responsible to verifying user email with verification token from controller layer
+func (ac *AuthController) VerifyEmail(ctx *fiber.Ctx) error {
+	err := ac.AuthSvc.VerifyEmailSvc(ctx.Query("token", ""))
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) || errors.Is(err, model.ErrInvalidVerificationToken) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
 		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
 	}
 
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Verify Email", nil)
+}
+
+// ResendVerification responsible to resending email verification from controller layer
+func (ac *AuthController) ResendVerification(ctx *fiber.Ctx) error {
+	var resendReq model.ResendVerificationRequest
+
+	if err := ctx.BodyParser(&resendReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	err := ac.AuthSvc.ResendVerificationSvc(resendReq)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "If the email is registered and not verified yet, a verification link has been sent", nil)
+}
+
+// GoogleLogin responsible to starting google oauth login from controller layer
+func (ac *AuthController) GoogleLogin(ctx *fiber.Ctx) error {
+	var oauthReq model.OAuthLoginRequest
+
+	if err := ctx.QueryParser(&oauthReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	data, err := ac.AuthSvc.GoogleLoginSvc(oauthReq)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting Google Login URL", data)
+}
+
+// GoogleCallback responsible to finishing google oauth login from controller layer
+func (ac *AuthController) GoogleCallback(ctx *fiber.Ctx) error {
+	var callbackReq model.OAuthCallbackRequest
+
+	if err := ctx.QueryParser(&callbackReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	jwt, mfaChallenge, err := ac.AuthSvc.GoogleCallbackSvc(callbackReq)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidExchange) || errors.Is(err, model.ErrOAuthRegistrationRequired) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrUserBlacklisted) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, model.ErrUserBlacklisted, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	if mfaChallenge != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Two-Factor Authentication Required, continue through /v1/auth/login/mfa", mfaChallenge)
+	}
+
 	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Login", jwt)
 }
@@ -1 +1,105 @@
 package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/helper"
+	"github.com/wiormiw/GrowBaks/model"
+	"github.com/wiormiw/GrowBaks/service"
+	"github.com/wiormiw/GrowBaks/util"
+)
+
+type (
+	// IBlacklistController is an interface that has all the function to be implemented inside blacklist controller
+	IBlacklistController interface {
+		ListBlacklist(ctx *fiber.Ctx) error
+		BlacklistUser(ctx *fiber.Ctx) error
+		UnblacklistUser(ctx *fiber.Ctx) error
+	}
+
+	// BlacklistController is an app blacklist struct that consists of all the dependencies needed for blacklist controller
+	BlacklistController struct {
+		Context      context.Context
+		Config       *config.Configuration
+		Logger       *logrus.Logger
+		BlacklistSvc service.IBlacklistService
+	}
+)
+
+// ListBlacklist responsible to getting all active ban from controller layer
+func (bc *BlacklistController) ListBlacklist(ctx *fiber.Ctx) error {
+	data, err := bc.BlacklistSvc.GetAllBlacklistSvc()
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting all Blacklist", data)
+}
+
+// BlacklistUser responsible to banning a user by id from controller layer
+func (bc *BlacklistController) BlacklistUser(ctx *fiber.Ctx) error {
+	var blacklistReq model.BlacklistUserRequest
+
+	userID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrUserNotFound.Error(), nil)
+	}
+
+	if err := ctx.BodyParser(&blacklistReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	data := ctx.Locals(model.KeyJWTValidAccess)
+	extData, err := util.ExtractPayloadJWT(data)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	adminID, err := uuid.Parse(extData.UserID)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusUnauthorized, model.ErrInvalidToken, model.ErrInvalidToken.Error(), nil)
+	}
+
+	err = bc.BlacklistSvc.BlacklistUserSvc(adminID, userID, blacklistReq)
+	if err != nil {
+		if errors.Is(err, model.ErrUserNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrForbiddenBlacklistSelf) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusCreated, nil, "Success Blacklist User", nil)
+}
+
+// UnblacklistUser responsible to lifting ban of a user by id from controller layer
+func (bc *BlacklistController) UnblacklistUser(ctx *fiber.Ctx) error {
+	userID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrUserNotFound.Error(), nil)
+	}
+
+	err = bc.BlacklistSvc.UnblacklistUserSvc(userID)
+	if err != nil {
+		if errors.Is(err, model.ErrBlacklistNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Unblacklist User", nil)
+}
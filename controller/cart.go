@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/helper"
+	"github.com/wiormiw/GrowBaks/model"
+	"github.com/wiormiw/GrowBaks/service"
+)
+
+type (
+	// ICartController is an interface that has all the function to be implemented inside cart controller
+	ICartController interface {
+		GetCart(ctx *fiber.Ctx) error
+		AddCartItem(ctx *fiber.Ctx) error
+		UpdateCartItem(ctx *fiber.Ctx) error
+		DeleteCartItem(ctx *fiber.Ctx) error
+		Checkout(ctx *fiber.Ctx) error
+	}
+
+	// CartController is an app cart struct that consists of all the dependencies needed for cart controller
+	CartController struct {
+		Context context.Context
+		Config  *config.Configuration
+		Logger  *logrus.Logger
+		CartSvc service.ICartService
+	}
+)
+
+// GetCart responsible to getting every item inside cart of current user from controller layer
+func (cc *CartController) GetCart(ctx *fiber.Ctx) error {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	data, err := cc.CartSvc.GetCartSvc(userID)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting Cart", data)
+}
+
+// AddCartItem responsible to adding a product into cart of current user from controller layer
+func (cc *CartController) AddCartItem(ctx *fiber.Ctx) error {
+	var req model.AddCartItemRequest
+
+	if err := ctx.BodyParser(&req); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = cc.CartSvc.AddCartItemSvc(userID, req)
+	if err != nil {
+		return cartErrorResponse(ctx, err)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusCreated, nil, "Success Adding Cart Item", nil)
+}
+
+// UpdateCartItem responsible to replacing qty of a product inside cart of current user from controller layer
+func (cc *CartController) UpdateCartItem(ctx *fiber.Ctx) error {
+	var req model.UpdateCartItemRequest
+
+	if err := ctx.BodyParser(&req); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	productID, err := uuid.Parse(ctx.Params("product_id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = cc.CartSvc.UpdateCartItemSvc(userID, productID, req)
+	if err != nil {
+		return cartErrorResponse(ctx, err)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Update Cart Item", nil)
+}
+
+// DeleteCartItem responsible to removing a product from cart of current user from controller layer
+func (cc *CartController) DeleteCartItem(ctx *fiber.Ctx) error {
+	productID, err := uuid.Parse(ctx.Params("product_id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = cc.CartSvc.DeleteCartItemSvc(userID, productID)
+	if err != nil {
+		return cartErrorResponse(ctx, err)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Delete Cart Item", nil)
+}
+
+// Checkout responsible to turning cart of current user into pemesanan from controller layer
+func (cc *CartController) Checkout(ctx *fiber.Ctx) error {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	data, err := cc.CartSvc.CheckoutSvc(userID)
+	if err != nil {
+		return cartErrorResponse(ctx, err)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusCreated, nil, "Success Checkout Cart", data)
+}
+
+// cartErrorResponse responsible to mapping cart service error into response status
+func cartErrorResponse(ctx *fiber.Ctx, err error) error {
+	if errors.Is(err, model.ErrInvalidRequest) || errors.Is(err, model.ErrCartEmpty) {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	if errors.Is(err, model.ErrProductNotFound) || errors.Is(err, model.ErrCartItemNotFound) {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+	}
+
+	if errors.Is(err, model.ErrInsufficientStock) || errors.Is(err, model.ErrProductNotPriced) {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusConflict, err, err.Error(), nil)
+	}
+
+	if errors.Is(err, model.ErrEmailNotVerified) {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+}
@@ -20,10 +20,17 @@ type (
 	ILapakController interface {
 		ListLapak(ctx *fiber.Ctx) error
 		ListLapakByLocation(ctx *fiber.Ctx) error
+		ListNearbyLapak(ctx *fiber.Ctx) error
+		ListLapakSelf(ctx *fiber.Ctx) error
+		ListLapakByUser(ctx *fiber.Ctx) error
+		CreateLapak(ctx *fiber.Ctx) error
+		RelocateLapak(ctx *fiber.Ctx) error
+		ListLapakLocationHistory(ctx *fiber.Ctx) error
 		DetailLapak(ctx *fiber.Ctx) error
 		UpdateLapak(ctx *fiber.Ctx) error
 		UpdateLapakByStatus(ctx *fiber.Ctx) error
 		DeleteLapak(ctx *fiber.Ctx) error
+		RestoreLapak(ctx *fiber.Ctx) error
 	}
 
 	// TagController is an app tag struct that consists of all the dependencies needed for tag controller
@@ -47,12 +54,26 @@ func (lapc *LapakController) ListLapak(ctx *fiber.Ctx) error {
 		}
 	}
 
-	data, err := lapc.LapakSvc.GetAllLapakSvc(search)
+	includeDeleted, err := includeDeletedQuery(ctx)
 	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+	}
+
+	page, err := parsePageQuery(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	data, total, err := lapc.LapakSvc.GetAllLapakSvc(search, includeDeleted, page)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
 		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
 	}
 
-	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting all Lapak", data)
+	return helper.ResponseFormatterWithMeta[any](ctx, fiber.StatusOK, nil, "Success Getting all Lapak", data, pageMetadata(ctx, page, total))
 }
 
 // ListLapak responsible to getting all lapak from controller layer by location
@@ -67,6 +88,16 @@ func (lapc *LapakController) ListLapakByLocation(ctx *fiber.Ctx) error {
 		}
 	}
 
+	includeDeleted, err := includeDeletedQuery(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+	}
+
+	page, err := parsePageQuery(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
 	data := ctx.Locals(model.KeyJWTValidAccess)
 	extData, err := util.ExtractPayloadJWT(data)
 	if err != nil {
@@ -80,12 +111,105 @@ func (lapc *LapakController) ListLapakByLocation(ctx *fiber.Ctx) error {
 
 	lapc.Logger.Info(fmt.Sprintf("Value Test : %s ID, %s Search", userID, search))
 
-	data, err = lapc.LapakSvc.GetAllLapakByLocationSvc(userID, search)
+	lapakData, total, err := lapc.LapakSvc.GetAllLapakByLocationSvc(userID, search, includeDeleted, page)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatterWithMeta[any](ctx, fiber.StatusOK, nil, "Success Getting all Lapak By Location", lapakData, pageMetadata(ctx, page, total))
+}
+
+// ListNearbyLapak responsible to getting lapak around lat & lng query sorted by distance from controller layer
+func (lapc *LapakController) ListNearbyLapak(ctx *fiber.Ctx) error {
+	near, err := parseGeoRadiusQuery(ctx)
+	if err != nil || near == nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, model.ErrInvalidRequest, model.ErrInvalidRequest.Error(), nil)
+	}
+
+	page, err := parsePageQuery(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	page.Sort, page.Order = "distance", "asc"
+
+	data, total, err := lapc.LapakSvc.GetNearbyLapakSvc(*near, page)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatterWithMeta[any](ctx, fiber.StatusOK, nil, "Success Getting Nearby Lapak", data, pageMetadata(ctx, page, total))
+}
+
+// ListLapakSelf responsible to getting every lapak owned by the caller from controller layer
+func (lapc *LapakController) ListLapakSelf(ctx *fiber.Ctx) error {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	data, err := lapc.LapakSvc.GetLapakByUserSvc(userID)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting all Lapak", data)
+}
+
+// ListLapakByUser responsible to getting every lapak owned by a user from controller layer
+func (lapc *LapakController) ListLapakByUser(ctx *fiber.Ctx) error {
+	userID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrUserNotFound.Error(), nil)
+	}
+
+	data, err := lapc.LapakSvc.GetLapakByUserSvc(userID)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting all Lapak", data)
+}
+
+// CreateLapak responsible to creating a lapak owned by the caller from controller layer
+func (lapc *LapakController) CreateLapak(ctx *fiber.Ctx) error {
+	var lapakReq model.LapakCreateRequest
+
+	if err := ctx.BodyParser(&lapakReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	principal, err := extractPrincipal(ctx)
 	if err != nil {
 		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
 	}
 
-	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting all Lapak By Location", data)
+	data, err := lapc.LapakSvc.CreateLapakSvc(principal, lapakReq)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrLapakLimitReached) || errors.Is(err, model.ErrEmailNotVerified) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrUserBlacklisted) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, model.ErrUserBlacklisted, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusCreated, nil, "Success Create Lapak", data)
 }
 
 // DetailLapak responsible to getting one lapak from controller layer
@@ -98,11 +222,15 @@ func (lapc *LapakController) DetailLapak(ctx *fiber.Ctx) error {
 
 	lapakID, err := uuid.Parse(id)
 	if err != nil {
-		return err
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrLapakNotFound.Error(), nil)
 	}
 
-	data, err := lapc.LapakSvc.GetLapakByIDSvc(lapakID)
+	data, err := lapc.LapakSvc.GetLapakDetailSvc(lapakID)
 	if err != nil {
+		if errors.Is(err, model.ErrLapakNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
 		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
 	}
 
@@ -124,9 +252,14 @@ func (lapc *LapakController) UpdateLapak(ctx *fiber.Ctx) error {
 		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
 	}
 
-	err = lapc.LapakSvc.UpdateLapakSvc(lapakID, lapakUpdate)
+	principal, err := extractPrincipal(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = lapc.LapakSvc.UpdateLapakSvc(principal, lapakID, lapakUpdate)
 	if err != nil {
-		if errors.Is(err, model.ErrTagNotFound) {
+		if errors.Is(err, model.ErrLapakNotFound) {
 			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
 		}
 
@@ -134,6 +267,18 @@ func (lapc *LapakController) UpdateLapak(ctx *fiber.Ctx) error {
 			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
 		}
 
+		if errors.Is(err, model.ErrForbiddenAccess) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrUserBlacklisted) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, model.ErrUserBlacklisted, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrEmailNotVerified) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
 		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
 	}
 
@@ -155,9 +300,14 @@ func (lapc *LapakController) UpdateLapakByStatus(ctx *fiber.Ctx) error {
 		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
 	}
 
-	err = lapc.LapakSvc.UpdateLapakStatusSvc(lapakID, lapakUpdate)
+	principal, err := extractPrincipal(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = lapc.LapakSvc.UpdateLapakStatusSvc(principal, lapakID, lapakUpdate)
 	if err != nil {
-		if errors.Is(err, model.ErrTagNotFound) {
+		if errors.Is(err, model.ErrLapakNotFound) {
 			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
 		}
 
@@ -165,6 +315,18 @@ func (lapc *LapakController) UpdateLapakByStatus(ctx *fiber.Ctx) error {
 			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
 		}
 
+		if errors.Is(err, model.ErrForbiddenAccess) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrUserBlacklisted) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, model.ErrUserBlacklisted, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrEmailNotVerified) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
 		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
 	}
 
@@ -180,14 +342,108 @@ func (lapc *LapakController) DeleteLapak(ctx *fiber.Ctx) error {
 		return err
 	}
 
-	err = lapc.LapakSvc.DeleteLapakSvc(lapakID)
+	principal, err := extractPrincipal(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = lapc.LapakSvc.DeleteLapakSvc(principal, lapakID)
 	if err != nil {
-		if errors.Is(err, model.ErrTagNotFound) {
+		if errors.Is(err, model.ErrLapakNotFound) {
 			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
 		}
 
+		if errors.Is(err, model.ErrForbiddenAccess) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
 		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
 	}
 
 	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Deleting Tags", nil)
 }
+
+// RestoreLapak responsible to restoring a soft deleted lapak by id from controller layer
+func (lapc *LapakController) RestoreLapak(ctx *fiber.Ctx) error {
+	lapakID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	err = lapc.LapakSvc.RestoreLapakSvc(lapakID)
+	if err != nil {
+		if errors.Is(err, model.ErrLapakNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Restore Lapak", nil)
+}
+
+// RelocateLapak responsible to moving a lapak into other location from controller layer
+func (lapc *LapakController) RelocateLapak(ctx *fiber.Ctx) error {
+	var relocateReq model.LapakRelocateRequest
+
+	lapakID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrLapakNotFound.Error(), nil)
+	}
+
+	if err := ctx.BodyParser(&relocateReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	principal, err := extractPrincipal(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = lapc.LapakSvc.RelocateLapakSvc(principal, lapakID, relocateReq)
+	if err != nil {
+		if errors.Is(err, model.ErrLapakNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrForbiddenAccess) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Relocate Lapak", nil)
+}
+
+// ListLapakLocationHistory responsible to getting every relocation of a lapak from controller layer
+func (lapc *LapakController) ListLapakLocationHistory(ctx *fiber.Ctx) error {
+	lapakID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrLapakNotFound.Error(), nil)
+	}
+
+	principal, err := extractPrincipal(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	data, err := lapc.LapakSvc.GetLapakLocationHistorySvc(principal, lapakID)
+	if err != nil {
+		if errors.Is(err, model.ErrLapakNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrForbiddenAccess) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting Lapak Location History", data)
+}
@@ -0,0 +1,220 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/helper"
+	"github.com/wiormiw/GrowBaks/model"
+	"github.com/wiormiw/GrowBaks/service"
+)
+
+type (
+	// ILocationController is an interface that has all the function to be implemented inside location controller
+	ILocationController interface {
+		ListLocation(ctx *fiber.Ctx) error
+		ListProvinsi(ctx *fiber.Ctx) error
+		ListKota(ctx *fiber.Ctx) error
+		DetailLocation(ctx *fiber.Ctx) error
+		CreateLocation(ctx *fiber.Ctx) error
+		UpdateLocation(ctx *fiber.Ctx) error
+		DeleteLocation(ctx *fiber.Ctx) error
+		ImportLocation(ctx *fiber.Ctx) error
+	}
+
+	// LocationController is an app location struct that consists of all the dependencies needed for location controller
+	LocationController struct {
+		Context     context.Context
+		Config      *config.Configuration
+		Logger      *logrus.Logger
+		LocationSvc service.ILocationService
+	}
+)
+
+// ListLocation responsible to getting all location filtered by provinsi, kota & search from controller layer
+func (loc *LocationController) ListLocation(ctx *fiber.Ctx) error {
+	filter := model.LocationFilter{
+		Provinsi: strings.TrimSpace(ctx.Query("provinsi", "")),
+		Kota:     strings.TrimSpace(ctx.Query("kota", "")),
+		Search:   strings.TrimSpace(ctx.Query("s", "")),
+	}
+
+	page, err := parsePageQuery(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	// location has no created_at, listed alphabetically by daerah unless sort is given
+	if ctx.Query("sort", "") == "" {
+		page.Sort = "daerah"
+	}
+
+	if ctx.Query("order", "") == "" {
+		page.Order = "asc"
+	}
+
+	data, total, err := loc.LocationSvc.GetAllLocationSvc(filter, page)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatterWithMeta[any](ctx, fiber.StatusOK, nil, "Success Getting all Location", data, pageMetadata(ctx, page, total))
+}
+
+// ListProvinsi responsible to getting every provinsi from controller layer
+func (loc *LocationController) ListProvinsi(ctx *fiber.Ctx) error {
+	data, err := loc.LocationSvc.GetAllProvinsiSvc()
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting all Provinsi", data)
+}
+
+// ListKota responsible to getting every kota inside provinsi query from controller layer
+func (loc *LocationController) ListKota(ctx *fiber.Ctx) error {
+	data, err := loc.LocationSvc.GetAllKotaSvc(ctx.Query("provinsi", ""))
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting all Kota", data)
+}
+
+// DetailLocation responsible to getting a location by id from controller layer
+func (loc *LocationController) DetailLocation(ctx *fiber.Ctx) error {
+	locationID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrLocationNotFound.Error(), nil)
+	}
+
+	data, err := loc.LocationSvc.GetLocationByIDSvc(locationID)
+	if err != nil {
+		if errors.Is(err, model.ErrLocationNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting Location", data)
+}
+
+// CreateLocation responsible to creating a location from controller layer
+func (loc *LocationController) CreateLocation(ctx *fiber.Ctx) error {
+	var locationReq model.LocationRequest
+
+	if err := ctx.BodyParser(&locationReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	data, err := loc.LocationSvc.CreateLocationSvc(locationReq)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrLocationExisted) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusConflict, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusCreated, nil, "Success Create Location", data)
+}
+
+// UpdateLocation responsible to updating a location by id from controller layer
+func (loc *LocationController) UpdateLocation(ctx *fiber.Ctx) error {
+	var locationReq model.LocationRequest
+
+	locationID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrLocationNotFound.Error(), nil)
+	}
+
+	if err := ctx.BodyParser(&locationReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	data, err := loc.LocationSvc.UpdateLocationSvc(locationID, locationReq)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrLocationNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrLocationExisted) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusConflict, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Update Location", data)
+}
+
+// DeleteLocation responsible to deleting an unused location by id from controller layer
+func (loc *LocationController) DeleteLocation(ctx *fiber.Ctx) error {
+	locationID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrLocationNotFound.Error(), nil)
+	}
+
+	err = loc.LocationSvc.DeleteLocationSvc(locationID)
+	if err != nil {
+		if errors.Is(err, model.ErrLocationNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrLocationInUse) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusConflict, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Delete Location", nil)
+}
+
+// ImportLocation responsible to creating many location from uploaded csv file from controller layer
+func (loc *LocationController) ImportLocation(ctx *fiber.Ctx) error {
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	defer file.Close()
+
+	data, err := loc.LocationSvc.ImportLocationSvc(file)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusCreated, nil, "Success Import Location", data)
+}
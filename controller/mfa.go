@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/helper"
+	"github.com/wiormiw/GrowBaks/model"
+	"github.com/wiormiw/GrowBaks/service"
+)
+
+type (
+	// IMFAController is an interface that has all the function to be implemented inside mfa controller
+	IMFAController interface {
+		Enroll(ctx *fiber.Ctx) error
+		Verify(ctx *fiber.Ctx) error
+		Disable(ctx *fiber.Ctx) error
+		RegenerateRecoveryCodes(ctx *fiber.Ctx) error
+	}
+
+	// MFAController is an app mfa struct that consists of all the dependencies needed for mfa controller
+	MFAController struct {
+		Context context.Context
+		Config  *config.Configuration
+		Logger  *logrus.Logger
+		MFASvc  service.IMFAService
+	}
+)
+
+// Enroll responsible to generating TOTP secret of current user from controller layer
+func (mc *MFAController) Enroll(ctx *fiber.Ctx) error {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusUnauthorized, err, err.Error(), nil)
+	}
+
+	data, err := mc.MFASvc.EnrollSvc(userID)
+	if err != nil {
+		return mfaErrorResponse(ctx, err)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Enroll Two-Factor Authentication, verify it with code from your authenticator app", data)
+}
+
+// Verify responsible to enabling TOTP of current user from controller layer
+func (mc *MFAController) Verify(ctx *fiber.Ctx) error {
+	var codeReq model.MFACodeRequest
+
+	if err := ctx.BodyParser(&codeReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusUnauthorized, err, err.Error(), nil)
+	}
+
+	data, err := mc.MFASvc.VerifySvc(userID, codeReq)
+	if err != nil {
+		return mfaErrorResponse(ctx, err)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Enable Two-Factor Authentication, store these recovery codes safely", data)
+}
+
+// Disable responsible to disabling TOTP of current user from controller layer
+func (mc *MFAController) Disable(ctx *fiber.Ctx) error {
+	var codeReq model.MFACodeRequest
+
+	if err := ctx.BodyParser(&codeReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusUnauthorized, err, err.Error(), nil)
+	}
+
+	err = mc.MFASvc.DisableSvc(userID, codeReq)
+	if err != nil {
+		return mfaErrorResponse(ctx, err)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Disable Two-Factor Authentication", nil)
+}
+
+// RegenerateRecoveryCodes responsible to replacing recovery codes of current user from controller layer
+func (mc *MFAController) RegenerateRecoveryCodes(ctx *fiber.Ctx) error {
+	var codeReq model.MFACodeRequest
+
+	if err := ctx.BodyParser(&codeReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusUnauthorized, err, err.Error(), nil)
+	}
+
+	data, err := mc.MFASvc.RegenerateRecoveryCodesSvc(userID, codeReq)
+	if err != nil {
+		return mfaErrorResponse(ctx, err)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Regenerate Recovery Codes, store these recovery codes safely", data)
+}
+
+// mfaErrorResponse responsible to mapping mfa service error into http response
+func mfaErrorResponse(ctx *fiber.Ctx, err error) error {
+	if errors.Is(err, model.ErrUserNotFound) {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+	}
+
+	if errors.Is(err, model.ErrInvalidMFACode) || errors.Is(err, model.ErrMFANotEnrolled) || errors.Is(err, model.ErrMFANotEnabled) {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	if errors.Is(err, model.ErrMFAAlreadyEnabled) {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusConflict, err, err.Error(), nil)
+	}
+
+	if errors.Is(err, model.ErrForbiddenDisableMFA) {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wiormiw/GrowBaks/helper"
+	"github.com/wiormiw/GrowBaks/model"
+)
+
+const (
+	// defaultPageSize is used when size query is not given
+	defaultPageSize = 20
+	// maxPageSize is upper bound of size query
+	maxPageSize = 100
+)
+
+// parsePageQuery responsible to parsing page, size, sort & order query, newest row comes first by default
+func parsePageQuery(ctx *fiber.Ctx) (model.PageRequest, error) {
+	page := model.PageRequest{
+		Page:  1,
+		Size:  defaultPageSize,
+		Sort:  ctx.Query("sort", "created_at"),
+		Order: ctx.Query("order", "desc"),
+	}
+
+	var err error
+
+	if value := ctx.Query("page", ""); value != "" {
+		page.Page, err = strconv.Atoi(value)
+		if err != nil || page.Page < 1 {
+			return page, model.ErrInvalidRequest
+		}
+	}
+
+	if value := ctx.Query("size", ""); value != "" {
+		page.Size, err = strconv.Atoi(value)
+		if err != nil || page.Size < 1 || page.Size > maxPageSize {
+			return page, model.ErrInvalidRequest
+		}
+	}
+
+	if page.Order != "asc" && page.Order != "desc" {
+		return page, model.ErrInvalidRequest
+	}
+
+	return page, nil
+}
+
+// pageMetadata responsible to building meta block of a page along with its links
+func pageMetadata(ctx *fiber.Ctx, page model.PageRequest, totalData int) *model.Metadata {
+	totalPage := (totalData + page.Size - 1) / page.Size
+
+	meta := helper.BuildMetaData(page.Page, page.Size, page.Order, totalData, totalPage)
+	meta.Sort = page.Sort
+	meta.Links = helper.BuildPageLinks(ctx, meta)
+
+	return meta
+}
+
+// cursorMetadata responsible to building meta block of a cursor based page along with its links
+func cursorMetadata(ctx *fiber.Ctx, size int, totalData int, nextCursor string) *model.Metadata {
+	totalPage := (totalData + size - 1) / size
+
+	meta := helper.BuildMetaData(0, size, "", totalData, totalPage)
+	meta.NextCursor = nextCursor
+	meta.Links = helper.BuildPageLinks(ctx, meta)
+
+	return meta
+}
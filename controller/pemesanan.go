@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -21,8 +22,10 @@ type (
 		CreatePemesanan(ctx *fiber.Ctx) error
 		ListPemesanan(ctx *fiber.Ctx) error
 		ListPemesananPribadi(ctx *fiber.Ctx) error
+		ListPemesananByLapak(ctx *fiber.Ctx) error
 		DetailPemesanan(ctx *fiber.Ctx) error
 		UpdatePemesanan(ctx *fiber.Ctx) error
+		UpdatePemesananByLapak(ctx *fiber.Ctx) error
 		DeletePemesanan(ctx *fiber.Ctx) error
 	}
 
@@ -71,6 +74,18 @@ func (pemc *PemesananController) CreatePemesanan(ctx *fiber.Ctx) error {
 			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
 		}
 
+		if errors.Is(err, model.ErrEmailNotVerified) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrProductNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrInsufficientStock) || errors.Is(err, model.ErrProductNotPriced) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusConflict, err, err.Error(), nil)
+		}
+
 		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
 	}
 
@@ -99,15 +114,76 @@ func (pemc *PemesananController) ListPemesananPribadi(ctx *fiber.Ctx) error {
 	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting all Pemesanan", data)
 }
 
+// ListPemesananByLapak responsible to getting all pemesanan placed on a lapak from controller layer
+func (pemc *PemesananController) ListPemesananByLapak(ctx *fiber.Ctx) error {
+	lapakID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	filter := model.PemesananLapakFilter{
+		Status: strings.Trim(ctx.Query("status", ""), " "),
+	}
+
+	filter.From, err = parseDateQuery(ctx.Query("from", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	filter.To, err = parseDateQuery(ctx.Query("to", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	// "to" is inclusive, so the whole day is taken
+	if filter.To != nil {
+		endOfDay := filter.To.AddDate(0, 0, 1)
+		filter.To = &endOfDay
+	}
+
+	principal, err := extractPrincipal(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	data, err := pemc.PemesananSvc.GetAllPemesananByLapakSvc(principal, lapakID, filter)
+	if err != nil {
+		if errors.Is(err, model.ErrLapakNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrForbiddenAccess) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting all Pemesanan Lapak", data)
+}
+
 // ListPemesanan responsible to getting all pemesanan from controller layer
 func (pemc *PemesananController) ListPemesanan(ctx *fiber.Ctx) error {
-	data, err := pemc.PemesananSvc.GetAllPemesananSvc()
+	page, err := parsePageQuery(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	data, total, err := pemc.PemesananSvc.GetAllPemesananSvc(page)
 	if err != nil {
 		pemc.Logger.Info(fmt.Sprintf("Error: %s", err))
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
 		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
 	}
 
-	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting all Pemesanan", data)
+	return helper.ResponseFormatterWithMeta[any](ctx, fiber.StatusOK, nil, "Success Getting all Pemesanan", data, pageMetadata(ctx, page, total))
 }
 
 func (pemc *PemesananController) DetailPemesanan(ctx *fiber.Ctx) error {
@@ -122,11 +198,24 @@ func (pemc *PemesananController) DetailPemesanan(ctx *fiber.Ctx) error {
 		return err
 	}
 
-	data, err := pemc.PemesananSvc.GetPemesananByIDSvc(pemesananID)
+	principal, err := extractPrincipal(ctx)
 	if err != nil {
 		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
 	}
 
+	data, err := pemc.PemesananSvc.GetPemesananByIDSvc(principal, pemesananID)
+	if err != nil {
+		if errors.Is(err, model.ErrPemesananNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrForbiddenAccess) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
 	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting Pemesanan Detail", data)
 }
 
@@ -145,16 +234,76 @@ func (pemc *PemesananController) UpdatePemesanan(ctx *fiber.Ctx) error {
 		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
 	}
 
-	err = pemc.PemesananSvc.UpdatePemesananStatusSvc(pemesananID, pemesananUpdate)
+	principal, err := extractPrincipal(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = pemc.PemesananSvc.UpdatePemesananStatusSvc(principal, pemesananID, pemesananUpdate)
+	if err != nil {
+		if errors.Is(err, model.ErrPemesananNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrForbiddenAccess) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrInvalidStatusTransition) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusConflict, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Update Pemesanan Status", nil)
+}
+
+// UpdatePemesananByLapak responsible to updating status of a pemesanan placed on a lapak from controller layer
+func (pemc *PemesananController) UpdatePemesananByLapak(ctx *fiber.Ctx) error {
+	var pemesananUpdate model.UpdatePemesananRequest
+
+	if err := ctx.BodyParser(&pemesananUpdate); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	lapakID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	pemesananID, err := uuid.Parse(ctx.Params("pemesanan_id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	principal, err := extractPrincipal(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = pemc.PemesananSvc.UpdatePemesananLapakStatusSvc(principal, lapakID, pemesananID, pemesananUpdate)
 	if err != nil {
-		if errors.Is(err, model.ErrTagNotFound) {
+		if errors.Is(err, model.ErrLapakNotFound) || errors.Is(err, model.ErrPemesananNotFound) {
 			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
 		}
 
+		if errors.Is(err, model.ErrForbiddenAccess) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
 		if errors.Is(err, model.ErrInvalidRequest) {
 			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
 		}
 
+		if errors.Is(err, model.ErrInvalidStatusTransition) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusConflict, err, err.Error(), nil)
+		}
+
 		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
 	}
 
@@ -173,12 +322,25 @@ func (pemc *PemesananController) DeletePemesanan(ctx *fiber.Ctx) error {
 		return err
 	}
 
-	err = pemc.PemesananSvc.DeletePemesananSvc(pemesananID)
+	principal, err := extractPrincipal(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = pemc.PemesananSvc.DeletePemesananSvc(principal, pemesananID)
 	if err != nil {
-		if errors.Is(err, model.ErrUserNotFound) {
+		if errors.Is(err, model.ErrPemesananNotFound) {
 			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
 		}
 
+		if errors.Is(err, model.ErrPemesananNotCancelled) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusConflict, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrForbiddenAccess) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
 		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
 	}
 
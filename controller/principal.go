@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/wiormiw/GrowBaks/model"
+	"github.com/wiormiw/GrowBaks/util"
+)
+
+// currentUserID responsible to extracting user id from validated jwt payload
+func currentUserID(ctx *fiber.Ctx) (uuid.UUID, error) {
+	data := ctx.Locals(model.KeyJWTValidAccess)
+	extData, err := util.ExtractPayloadJWT(data)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	userID, err := uuid.Parse(extData.UserID)
+	if err != nil {
+		return uuid.Nil, model.ErrInvalidToken
+	}
+
+	return userID, nil
+}
+
+// extractPrincipal responsible to building caller principal from validated jwt payload & its role permissions
+func extractPrincipal(ctx *fiber.Ctx) (model.Principal, error) {
+	data := ctx.Locals(model.KeyJWTValidAccess)
+	extData, err := util.ExtractPayloadJWT(data)
+	if err != nil {
+		return model.Principal{}, err
+	}
+
+	userID, err := uuid.Parse(extData.UserID)
+	if err != nil {
+		return model.Principal{}, model.ErrInvalidToken
+	}
+
+	permissions, ok := ctx.Locals(model.KeyJWTPermissions).([]string)
+	if !ok {
+		return model.Principal{}, model.ErrTypeAssertion
+	}
+
+	return model.Principal{
+		UserID:      userID,
+		RoleName:    extData.RoleName,
+		Permissions: permissions,
+	}, nil
+}
+
+// parseDateQuery responsible to parsing optional date query formatted as YYYY-MM-DD
+func parseDateQuery(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, model.ErrInvalidRequest
+	}
+
+	return &t, nil
+}
+
+// includeDeletedQuery responsible to parsing optional include_deleted query, only caller granted trash:manage may see soft deleted rows
+func includeDeletedQuery(ctx *fiber.Ctx) (bool, error) {
+	if ctx.Query("include_deleted", "") != "true" {
+		return false, nil
+	}
+
+	permissions, _ := ctx.Locals(model.KeyJWTPermissions).([]string)
+	for _, granted := range permissions {
+		if granted == model.PermissionTrashManage {
+			return true, nil
+		}
+	}
+
+	return false, model.ErrForbiddenAccess
+}
+
+// defaultNearbyRadiusKM is used when radius_km query is not given
+const defaultNearbyRadiusKM = 5
+
+// parseGeoRadiusQuery responsible to parsing optional lat, lng & radius_km query, nil means no coordinate is given
+func parseGeoRadiusQuery(ctx *fiber.Ctx) (*model.GeoRadius, error) {
+	lat, lng := ctx.Query("lat", ""), ctx.Query("lng", "")
+	if lat == "" && lng == "" {
+		return nil, nil
+	}
+
+	near := &model.GeoRadius{RadiusKM: defaultNearbyRadiusKM}
+
+	var err error
+
+	near.Latitude, err = strconv.ParseFloat(lat, 64)
+	if err != nil {
+		return nil, model.ErrInvalidRequest
+	}
+
+	near.Longitude, err = strconv.ParseFloat(lng, 64)
+	if err != nil {
+		return nil, model.ErrInvalidRequest
+	}
+
+	if radius := ctx.Query("radius_km", ""); radius != "" {
+		near.RadiusKM, err = strconv.ParseFloat(radius, 64)
+		if err != nil {
+			return nil, model.ErrInvalidRequest
+		}
+	}
+
+	return near, nil
+}
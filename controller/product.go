@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/cloudinary/cloudinary-go/v2"
@@ -25,11 +26,12 @@ type (
 		CreateProduct(ctx *fiber.Ctx) error
 		ListProduct(ctx *fiber.Ctx) error
 		ListProductByLapak(ctx *fiber.Ctx) error
+		SuggestProduct(ctx *fiber.Ctx) error
 		DetailProduct(ctx *fiber.Ctx) error
-		// DetailUserProfile(ctx *fiber.Ctx) error
-		// UpdateUser(ctx *fiber.Ctx) error
-		// UpdateUserProfile(ctx *fiber.Ctx) error
-		// DeleteUser(ctx *fiber.Ctx) error
+		UpdateProduct(ctx *fiber.Ctx) error
+		PatchProduct(ctx *fiber.Ctx) error
+		DeleteProduct(ctx *fiber.Ctx) error
+		RestoreProduct(ctx *fiber.Ctx) error
 	}
 
 	// Product Controller is an app tag struct that consists of all the dependencies needed for user controller
@@ -110,12 +112,25 @@ func (pc *ProductController) CreateProduct(ctx *fiber.Ctx) error {
 		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
 	}
 
-	err = pc.ProductSvc.CreateProductSvc(lapakID, prodReq)
+	principal, err := extractPrincipal(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = pc.ProductSvc.CreateProductSvc(principal, lapakID, prodReq)
 	if err != nil {
 		if errors.Is(err, model.ErrInvalidRequest) {
 			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
 		}
 
+		if errors.Is(err, model.ErrLapakNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrForbiddenAccess) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
 		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
 	}
 
@@ -134,6 +149,34 @@ func (pc *ProductController) ListProduct(ctx *fiber.Ctx) error {
 		}
 	}
 
+	includeDeleted, err := includeDeletedQuery(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+	}
+
+	page, err := parsePageQuery(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	filter, err := parseProductFilter(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	filter.Search = search
+	filter.IncludeDeleted = includeDeleted
+
+	filter.Near, err = parseGeoRadiusQuery(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	// searched product is ranked by relevance unless sort is given
+	if search != "" && ctx.Query("sort", "") == "" {
+		page.Sort = "relevance"
+	}
+
 	data := ctx.Locals(model.KeyJWTValidAccess)
 	extData, err := util.ExtractPayloadJWT(data)
 	if err != nil {
@@ -147,12 +190,105 @@ func (pc *ProductController) ListProduct(ctx *fiber.Ctx) error {
 
 	pc.Logger.Info(fmt.Sprintf("Value Test : %s ID, %s Search", userID, search))
 
-	data, err = pc.ProductSvc.GetAllProductSvc(userID, search)
+	// feed client pages by cursor, starting with empty cursor, newest product first
+	if ctx.Context().QueryArgs().Has("cursor") {
+		products, total, nextCursor, err := pc.ProductSvc.GetProductFeedSvc(userID, filter, page.Size, ctx.Query("cursor", ""))
+		if err != nil {
+			return productErrorResponse(ctx, err)
+		}
+
+		facets, err := pc.ProductSvc.GetProductFacetsSvc(userID, filter)
+		if err != nil {
+			return productErrorResponse(ctx, err)
+		}
+
+		meta := cursorMetadata(ctx, page.Size, total, nextCursor)
+		meta.Facets = facets
+
+		return helper.ResponseFormatterWithMeta[any](ctx, fiber.StatusOK, nil, "Success Getting all Product", products, meta)
+	}
+
+	products, total, err := pc.ProductSvc.GetAllProductSvc(userID, filter, page)
 	if err != nil {
-		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+		return productErrorResponse(ctx, err)
 	}
 
-	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting all Product", data)
+	facets, err := pc.ProductSvc.GetProductFacetsSvc(userID, filter)
+	if err != nil {
+		return productErrorResponse(ctx, err)
+	}
+
+	meta := pageMetadata(ctx, page, total)
+	meta.Facets = facets
+
+	return helper.ResponseFormatterWithMeta[any](ctx, fiber.StatusOK, nil, "Success Getting all Product", products, meta)
+}
+
+// parseProductFilter responsible to parsing product list filter query, kategori may be repeated or comma separated
+func parseProductFilter(ctx *fiber.Ctx) (model.ProductFilter, error) {
+	filter := model.ProductFilter{
+		InStock:       ctx.Query("in_stock", "") == "true",
+		OpenLapakOnly: ctx.Query("open_only", "") == "true",
+		Daerah:        strings.TrimSpace(ctx.Query("daerah", "")),
+	}
+
+	for _, value := range ctx.Context().QueryArgs().PeekMulti("kategori") {
+		for _, category := range strings.Split(string(value), ",") {
+			if category = strings.TrimSpace(category); category != "" {
+				filter.Categories = append(filter.Categories, category)
+			}
+		}
+	}
+
+	prices := []struct {
+		key   string
+		value **int64
+	}{
+		{"price_min", &filter.PriceMin},
+		{"price_max", &filter.PriceMax},
+	}
+
+	for _, price := range prices {
+		if value := ctx.Query(price.key, ""); value != "" {
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return filter, model.ErrInvalidRequest
+			}
+
+			*price.value = &parsed
+		}
+	}
+
+	ids := []struct {
+		key   string
+		value **uuid.UUID
+	}{
+		{"lapak_id", &filter.LapakID},
+		{"location_id", &filter.LocationID},
+	}
+
+	for _, id := range ids {
+		if value := ctx.Query(id.key, ""); value != "" {
+			parsed, err := uuid.Parse(value)
+			if err != nil {
+				return filter, model.ErrInvalidRequest
+			}
+
+			*id.value = &parsed
+		}
+	}
+
+	return filter, nil
+}
+
+// SuggestProduct responsible to getting product name autocompletion of q query from controller layer
+func (pc *ProductController) SuggestProduct(ctx *fiber.Ctx) error {
+	data, err := pc.ProductSvc.SuggestProductSvc(strings.TrimSpace(ctx.Query("q", "")))
+	if err != nil {
+		return productErrorResponse(ctx, err)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting Product Suggestion", data)
 }
 
 func (pc *ProductController) ListProductByLapak(ctx *fiber.Ctx) error {
@@ -167,7 +303,12 @@ func (pc *ProductController) ListProductByLapak(ctx *fiber.Ctx) error {
 		return err
 	}
 
-	data, err := pc.ProductSvc.GetAllProductByLapakSvc(lapakID)
+	includeDeleted, err := includeDeletedQuery(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+	}
+
+	data, err := pc.ProductSvc.GetAllProductByLapakSvc(lapakID, includeDeleted)
 	if err != nil {
 		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
 	}
@@ -189,8 +330,116 @@ func (pc *ProductController) DetailProduct(ctx *fiber.Ctx) error {
 
 	data, err := pc.ProductSvc.GetProductByIDSvc(productID)
 	if err != nil {
+		if errors.Is(err, model.ErrProductNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
 		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
 	}
 
 	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting Product Detail", data)
 }
+
+// UpdateProduct responsible to replacing every field of a product by id from controller layer
+func (pc *ProductController) UpdateProduct(ctx *fiber.Ctx) error {
+	var prodReq model.UpdateProductRequest
+
+	if err := ctx.BodyParser(&prodReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	productID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	principal, err := extractPrincipal(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = pc.ProductSvc.UpdateProductSvc(principal, productID, prodReq)
+	if err != nil {
+		return productErrorResponse(ctx, err)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Update Product", nil)
+}
+
+// PatchProduct responsible to updating given fields of a product by id from controller layer
+func (pc *ProductController) PatchProduct(ctx *fiber.Ctx) error {
+	var prodReq model.PatchProductRequest
+
+	if err := ctx.BodyParser(&prodReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	productID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	principal, err := extractPrincipal(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = pc.ProductSvc.PatchProductSvc(principal, productID, prodReq)
+	if err != nil {
+		return productErrorResponse(ctx, err)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Update Product", nil)
+}
+
+// DeleteProduct responsible to soft deleting a product by id from controller layer
+func (pc *ProductController) DeleteProduct(ctx *fiber.Ctx) error {
+	productID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	principal, err := extractPrincipal(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = pc.ProductSvc.DeleteProductSvc(principal, productID)
+	if err != nil {
+		return productErrorResponse(ctx, err)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Delete Product", nil)
+}
+
+// RestoreProduct responsible to restoring a soft deleted product by id from controller layer
+func (pc *ProductController) RestoreProduct(ctx *fiber.Ctx) error {
+	productID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	err = pc.ProductSvc.RestoreProductSvc(productID)
+	if err != nil {
+		return productErrorResponse(ctx, err)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Restore Product", nil)
+}
+
+// productErrorResponse responsible to mapping product service error into response status
+func productErrorResponse(ctx *fiber.Ctx, err error) error {
+	if errors.Is(err, model.ErrInvalidRequest) {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	if errors.Is(err, model.ErrProductNotFound) {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+	}
+
+	if errors.Is(err, model.ErrForbiddenAccess) {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+}
@@ -0,0 +1,270 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/helper"
+	"github.com/wiormiw/GrowBaks/model"
+	"github.com/wiormiw/GrowBaks/service"
+	"github.com/wiormiw/GrowBaks/util"
+)
+
+type (
+	// IRoleController is an interface that has all the function to be implemented inside role controller
+	IRoleController interface {
+		ListRole(ctx *fiber.Ctx) error
+		CreateRole(ctx *fiber.Ctx) error
+		DeleteRole(ctx *fiber.Ctx) error
+		UpdateRoleMFA(ctx *fiber.Ctx) error
+		UpdateRoleLapakLimit(ctx *fiber.Ctx) error
+		UpdateRolePermissions(ctx *fiber.Ctx) error
+		AssignUserRole(ctx *fiber.Ctx) error
+		ListPermission(ctx *fiber.Ctx) error
+		CreatePermission(ctx *fiber.Ctx) error
+	}
+
+	// RoleController is an app role struct that consists of all the dependencies needed for role controller
+	RoleController struct {
+		Context context.Context
+		Config  *config.Configuration
+		Logger  *logrus.Logger
+		RoleSvc service.IRoleService
+	}
+)
+
+// ListRole responsible to getting all role from controller layer
+func (rc *RoleController) ListRole(ctx *fiber.Ctx) error {
+	data, err := rc.RoleSvc.GetAllRoleSvc()
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting all Roles", data)
+}
+
+// CreateRole responsible to creating a role from controller layer
+func (rc *RoleController) CreateRole(ctx *fiber.Ctx) error {
+	var roleReq model.CreateRoleRequest
+
+	if err := ctx.BodyParser(&roleReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	data, err := rc.RoleSvc.CreateRoleSvc(roleReq)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrRoleExisted) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusConflict, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusCreated, nil, "Success Create Role", data)
+}
+
+// DeleteRole responsible to deleting a role by id from controller layer
+func (rc *RoleController) DeleteRole(ctx *fiber.Ctx) error {
+	roleID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrRoleNotFound.Error(), nil)
+	}
+
+	data := ctx.Locals(model.KeyJWTValidAccess)
+	extData, err := util.ExtractPayloadJWT(data)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = rc.RoleSvc.DeleteRoleSvc(extData.RoleName, roleID)
+	if err != nil {
+		if errors.Is(err, model.ErrRoleNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrForbiddenDeleteRole) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrRoleInUse) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusConflict, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Delete Role", nil)
+}
+
+// UpdateRoleMFA responsible to toggling mfa requirement of a role from controller layer
+func (rc *RoleController) UpdateRoleMFA(ctx *fiber.Ctx) error {
+	var roleMFAReq model.UpdateRoleMFARequest
+
+	roleID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrRoleNotFound.Error(), nil)
+	}
+
+	if err := ctx.BodyParser(&roleMFAReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	err = rc.RoleSvc.UpdateRoleMFASvc(roleID, roleMFAReq)
+	if err != nil {
+		if errors.Is(err, model.ErrRoleNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Update Role", nil)
+}
+
+// UpdateRoleLapakLimit responsible to capping lapak owned by each user of a role from controller layer
+func (rc *RoleController) UpdateRoleLapakLimit(ctx *fiber.Ctx) error {
+	var lapakLimitReq model.UpdateRoleLapakLimitRequest
+
+	roleID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrRoleNotFound.Error(), nil)
+	}
+
+	if err := ctx.BodyParser(&lapakLimitReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	err = rc.RoleSvc.UpdateRoleLapakLimitSvc(roleID, lapakLimitReq)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrRoleNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Update Role", nil)
+}
+
+// UpdateRolePermissions responsible to replacing every permission of a role from controller layer
+func (rc *RoleController) UpdateRolePermissions(ctx *fiber.Ctx) error {
+	var permissionsReq model.UpdateRolePermissionsRequest
+
+	roleID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrRoleNotFound.Error(), nil)
+	}
+
+	if err := ctx.BodyParser(&permissionsReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	data := ctx.Locals(model.KeyJWTValidAccess)
+	extData, err := util.ExtractPayloadJWT(data)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	role, err := rc.RoleSvc.UpdateRolePermissionsSvc(extData.RoleName, roleID, permissionsReq)
+	if err != nil {
+		if errors.Is(err, model.ErrRoleNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrInvalidRequest) || errors.Is(err, model.ErrPermissionNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrForbiddenRevokeRoleManage) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Update Role Permissions", role)
+}
+
+// AssignUserRole responsible to moving a user into another role from controller layer
+func (rc *RoleController) AssignUserRole(ctx *fiber.Ctx) error {
+	var assignReq model.AssignUserRoleRequest
+
+	userID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrUserNotFound.Error(), nil)
+	}
+
+	if err := ctx.BodyParser(&assignReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	principal, err := extractPrincipal(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	role, err := rc.RoleSvc.AssignUserRoleSvc(principal.UserID, userID, assignReq)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrRoleNotFound) || errors.Is(err, model.ErrUserNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrForbiddenAssignSelf) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Assign User Role", role)
+}
+
+// ListPermission responsible to getting all permission from controller layer
+func (rc *RoleController) ListPermission(ctx *fiber.Ctx) error {
+	data, err := rc.RoleSvc.GetAllPermissionSvc()
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting all Permissions", data)
+}
+
+// CreatePermission responsible to creating a permission from controller layer
+func (rc *RoleController) CreatePermission(ctx *fiber.Ctx) error {
+	var permissionReq model.CreatePermissionRequest
+
+	if err := ctx.BodyParser(&permissionReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	data, err := rc.RoleSvc.CreatePermissionSvc(permissionReq)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
+		if errors.Is(err, model.ErrPermissionExisted) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusConflict, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusCreated, nil, "Success Create Permission", data)
+}
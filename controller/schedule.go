@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/helper"
+	"github.com/wiormiw/GrowBaks/model"
+	"github.com/wiormiw/GrowBaks/service"
+)
+
+type (
+	// IScheduleController is an interface that has all the function to be implemented inside schedule controller
+	IScheduleController interface {
+		DetailLapakSchedule(ctx *fiber.Ctx) error
+		UpdateOpeningHours(ctx *fiber.Ctx) error
+		CreateHoliday(ctx *fiber.Ctx) error
+		DeleteHoliday(ctx *fiber.Ctx) error
+	}
+
+	// ScheduleController is an app schedule struct that consists of all the dependencies needed for schedule controller
+	ScheduleController struct {
+		Context     context.Context
+		Config      *config.Configuration
+		Logger      *logrus.Logger
+		ScheduleSvc service.IScheduleService
+	}
+)
+
+// DetailLapakSchedule responsible to getting opening hours & upcoming holidays of a lapak from controller layer
+func (sc *ScheduleController) DetailLapakSchedule(ctx *fiber.Ctx) error {
+	lapakID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrLapakNotFound.Error(), nil)
+	}
+
+	data, err := sc.ScheduleSvc.GetLapakScheduleSvc(lapakID)
+	if err != nil {
+		if errors.Is(err, model.ErrLapakNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting Lapak Schedule", data)
+}
+
+// UpdateOpeningHours responsible to replacing weekly opening hours of a lapak from controller layer
+func (sc *ScheduleController) UpdateOpeningHours(ctx *fiber.Ctx) error {
+	var hoursReq model.UpdateOpeningHoursRequest
+
+	lapakID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrLapakNotFound.Error(), nil)
+	}
+
+	if err := ctx.BodyParser(&hoursReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	principal, err := extractPrincipal(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = sc.ScheduleSvc.UpdateOpeningHoursSvc(principal, lapakID, hoursReq)
+	if err != nil {
+		return scheduleErrorResponse(ctx, err)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Update Opening Hours", nil)
+}
+
+// CreateHoliday responsible to adding a holiday into lapak from controller layer
+func (sc *ScheduleController) CreateHoliday(ctx *fiber.Ctx) error {
+	var holidayReq model.LapakHoliday
+
+	lapakID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrLapakNotFound.Error(), nil)
+	}
+
+	if err := ctx.BodyParser(&holidayReq); err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrFailedParseBody.Error(), nil)
+	}
+
+	principal, err := extractPrincipal(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = sc.ScheduleSvc.CreateHolidaySvc(principal, lapakID, holidayReq)
+	if err != nil {
+		return scheduleErrorResponse(ctx, err)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusCreated, nil, "Success Create Holiday", nil)
+}
+
+// DeleteHoliday responsible to removing a holiday of lapak from controller layer
+func (sc *ScheduleController) DeleteHoliday(ctx *fiber.Ctx) error {
+	lapakID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, model.ErrLapakNotFound.Error(), nil)
+	}
+
+	principal, err := extractPrincipal(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = sc.ScheduleSvc.DeleteHolidaySvc(principal, lapakID, ctx.Params("date", ""))
+	if err != nil {
+		return scheduleErrorResponse(ctx, err)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Delete Holiday", nil)
+}
+
+// scheduleErrorResponse responsible to mapping error of managing lapak schedule into response
+func scheduleErrorResponse(ctx *fiber.Ctx, err error) error {
+	if errors.Is(err, model.ErrLapakNotFound) || errors.Is(err, model.ErrHolidayNotFound) {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+	}
+
+	if errors.Is(err, model.ErrInvalidRequest) {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	if errors.Is(err, model.ErrForbiddenAccess) {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+}
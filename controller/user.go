@@ -23,6 +23,7 @@ type (
 		UpdateUser(ctx *fiber.Ctx) error
 		UpdateUserProfile(ctx *fiber.Ctx) error
 		DeleteUser(ctx *fiber.Ctx) error
+		RestoreUser(ctx *fiber.Ctx) error
 	}
 
 	// UserController is an app tag struct that consists of all the dependencies needed for user controller
@@ -47,12 +48,26 @@ func (uc *UserController) ListUser(ctx *fiber.Ctx) error {
 		}
 	}
 
-	data, err := uc.UserSvc.GetAllUserSvc(search)
+	includeDeleted, err := includeDeletedQuery(ctx)
 	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+	}
+
+	page, err := parsePageQuery(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	data, total, err := uc.UserSvc.GetAllUserSvc(search, includeDeleted, page)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRequest) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+		}
+
 		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
 	}
 
-	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Getting all Users", data)
+	return helper.ResponseFormatterWithMeta[any](ctx, fiber.StatusOK, nil, "Success Getting all Users", data, pageMetadata(ctx, page, total))
 }
 
 // DetailUser responsible to get a user by id from controller layer
@@ -93,12 +108,21 @@ func (uc *UserController) DetailUserProfile(ctx *fiber.Ctx) error {
 		return err
 	}
 
-	data, err := uc.UserSvc.GetUserProfileByIDSvc(userID)
+	principal, err := extractPrincipal(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	data, err := uc.UserSvc.GetUserProfileByIDSvc(principal, userID)
 	if err != nil {
 		if errors.Is(err, model.ErrUserNotFound) {
 			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
 		}
 
+		if errors.Is(err, model.ErrForbiddenAccess) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
 		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
 	}
 
@@ -124,12 +148,21 @@ func (uc *UserController) UpdateUser(ctx *fiber.Ctx) error {
 		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
 	}
 
-	err = uc.UserSvc.UpdateUserByIDSvc(userID, userReq)
+	principal, err := extractPrincipal(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = uc.UserSvc.UpdateUserByIDSvc(principal, userID, userReq)
 	if err != nil {
 		if errors.Is(err, model.ErrUserNotFound) {
 			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
 		}
 
+		if errors.Is(err, model.ErrForbiddenAccess) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
 		if errors.Is(err, model.ErrInvalidRequest) || errors.Is(err, model.ErrEmailExisted) {
 			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
 		}
@@ -159,12 +192,21 @@ func (uc *UserController) UpdateUserProfile(ctx *fiber.Ctx) error {
 		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
 	}
 
-	err = uc.UserSvc.UpdateUserProfileByIDSvc(userID, userReq)
+	principal, err := extractPrincipal(ctx)
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	err = uc.UserSvc.UpdateUserProfileByIDSvc(principal, userID, userReq)
 	if err != nil {
 		if errors.Is(err, model.ErrUserNotFound) {
 			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
 		}
 
+		if errors.Is(err, model.ErrForbiddenAccess) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, err, err.Error(), nil)
+		}
+
 		if errors.Is(err, model.ErrInvalidRequest) || errors.Is(err, model.ErrEmailExisted) {
 			return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
 		}
@@ -199,3 +241,22 @@ func (uc *UserController) DeleteUser(ctx *fiber.Ctx) error {
 
 	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Delete User", nil)
 }
+
+// RestoreUser responsible to restoring a soft deleted user by id from controller layer
+func (uc *UserController) RestoreUser(ctx *fiber.Ctx) error {
+	userID, err := uuid.Parse(ctx.Params("id", ""))
+	if err != nil {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusBadRequest, err, err.Error(), nil)
+	}
+
+	err = uc.UserSvc.RestoreUserByIDSvc(userID)
+	if err != nil {
+		if errors.Is(err, model.ErrUserNotFound) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusNotFound, err, err.Error(), nil)
+		}
+
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	return helper.ResponseFormatter[any](ctx, fiber.StatusOK, nil, "Success Restore User", nil)
+}
@@ -1,3 +1,3 @@
 INSERT INTO roles (name) VALUES ('Admin');
-INSERT INTO roles (name) VALUES ('Penjual');
+INSERT INTO roles (name, max_lapak) VALUES ('Penjual', 3);
 INSERT INTO roles (name) VALUES ('Pembeli');
\ No newline at end of file
@@ -0,0 +1,44 @@
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM (VALUES
+     ('Admin', 'lapak:list'),
+     ('Admin', 'lapak:create'),
+     ('Admin', 'lapak:update'),
+     ('Admin', 'lapak:delete'),
+     ('Admin', 'product:create'),
+     ('Admin', 'product:update'),
+     ('Admin', 'product:delete'),
+     ('Admin', 'order:list'),
+     ('Admin', 'user:list'),
+     ('Admin', 'user:read'),
+     ('Admin', 'user:delete'),
+     ('Admin', 'blacklist:manage'),
+     ('Admin', 'mfa:manage'),
+     ('Admin', 'role:manage'),
+     ('Admin', 'lapak:manage:any'),
+     ('Admin', 'product:manage:any'),
+     ('Admin', 'order:manage:any'),
+     ('Admin', 'trash:manage'),
+     ('Admin', 'order:list:lapak'),
+     ('Admin', 'order:update:lapak'),
+     ('Admin', 'location:manage'),
+     ('Admin', 'user:manage:any'),
+     ('Penjual', 'lapak:create'),
+     ('Penjual', 'lapak:update'),
+     ('Penjual', 'product:create'),
+     ('Penjual', 'product:update'),
+     ('Penjual', 'product:delete'),
+     ('Penjual', 'order:list:own'),
+     ('Penjual', 'order:update:own'),
+     ('Penjual', 'order:delete:own'),
+     ('Penjual', 'order:list:lapak'),
+     ('Penjual', 'order:update:lapak'),
+     ('Penjual', 'mfa:manage'),
+     ('Pembeli', 'lapak:list:location'),
+     ('Pembeli', 'order:create'),
+     ('Pembeli', 'order:list:own'),
+     ('Pembeli', 'order:update:own'),
+     ('Pembeli', 'order:delete:own')
+) AS m (role_name, permission_name)
+JOIN roles r ON r.name = m.role_name
+JOIN permissions p ON p.name = m.permission_name
+ON CONFLICT DO NOTHING;
\ No newline at end of file
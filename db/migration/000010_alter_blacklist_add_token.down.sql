@@ -0,0 +1,3 @@
+ALTER TABLE blacklist
+     DROP COLUMN IF EXISTS token_id,
+     DROP COLUMN IF EXISTS expired_at;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE blacklist
+     ADD COLUMN IF NOT EXISTS token_id uuid UNIQUE,
+     ADD COLUMN IF NOT EXISTS expired_at TIMESTAMPTZ;
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+     id uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+     user_id uuid NOT NULL,
+     token_hash VARCHAR UNIQUE NOT NULL,
+     expired_at TIMESTAMPTZ NOT NULL,
+     revoked_at TIMESTAMPTZ,
+     replaced_by uuid,
+     created_at TIMESTAMPTZ DEFAULT now()
+);
\ No newline at end of file
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS blacklist_user_id_idx;
+
+ALTER TABLE blacklist
+     DROP COLUMN IF EXISTS created_by;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE blacklist
+     ADD COLUMN IF NOT EXISTS created_by uuid;
+
+CREATE INDEX IF NOT EXISTS blacklist_user_id_idx ON blacklist (user_id) WHERE token_id IS NULL;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS password_resets CASCADE;
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS password_resets (
+     id uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+     user_id uuid NOT NULL,
+     token_hash VARCHAR UNIQUE NOT NULL,
+     expired_at TIMESTAMPTZ NOT NULL,
+     used_at TIMESTAMPTZ,
+     created_at TIMESTAMPTZ DEFAULT now()
+);
\ No newline at end of file
@@ -0,0 +1,14 @@
+ALTER TABLE users
+     ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+
+-- accounts registered before verification existed are trusted as verified
+UPDATE users SET email_verified_at = COALESCE(created_at, now()) WHERE email_verified_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS email_verifications (
+     id uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+     user_id uuid NOT NULL,
+     token_hash VARCHAR UNIQUE NOT NULL,
+     expired_at TIMESTAMPTZ NOT NULL,
+     used_at TIMESTAMPTZ,
+     created_at TIMESTAMPTZ DEFAULT now()
+);
\ No newline at end of file
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS oauth_states CASCADE;
+
+DROP INDEX IF EXISTS users_provider_subject_idx;
+
+ALTER TABLE users
+     DROP COLUMN IF EXISTS login_provider,
+     DROP COLUMN IF EXISTS provider_subject;
\ No newline at end of file
@@ -0,0 +1,15 @@
+ALTER TABLE users
+     ADD COLUMN IF NOT EXISTS login_provider VARCHAR NOT NULL DEFAULT 'password',
+     ADD COLUMN IF NOT EXISTS provider_subject VARCHAR;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_provider_subject_idx ON users (provider_subject) WHERE provider_subject IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS oauth_states (
+     state VARCHAR PRIMARY KEY,
+     code_verifier VARCHAR NOT NULL,
+     location_id uuid,
+     telepon VARCHAR,
+     is_seller BOOLEAN NOT NULL DEFAULT false,
+     expired_at TIMESTAMPTZ NOT NULL,
+     created_at TIMESTAMPTZ DEFAULT now()
+);
\ No newline at end of file
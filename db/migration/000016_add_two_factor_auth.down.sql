@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS mfa_challenges CASCADE;
+
+DROP TABLE IF EXISTS recovery_codes CASCADE;
+
+ALTER TABLE roles
+     DROP COLUMN IF EXISTS require_mfa;
+
+ALTER TABLE users
+     DROP COLUMN IF EXISTS totp_secret,
+     DROP COLUMN IF EXISTS totp_enabled_at,
+     DROP COLUMN IF EXISTS totp_last_step;
\ No newline at end of file
@@ -0,0 +1,27 @@
+ALTER TABLE users
+     ADD COLUMN IF NOT EXISTS totp_secret VARCHAR,
+     ADD COLUMN IF NOT EXISTS totp_enabled_at TIMESTAMPTZ,
+     ADD COLUMN IF NOT EXISTS totp_last_step BIGINT;
+
+ALTER TABLE roles
+     ADD COLUMN IF NOT EXISTS require_mfa BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+     id uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+     user_id uuid NOT NULL,
+     code_hash VARCHAR NOT NULL,
+     used_at TIMESTAMPTZ,
+     created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS recovery_codes_user_id_idx ON recovery_codes (user_id);
+
+CREATE TABLE IF NOT EXISTS mfa_challenges (
+     id uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+     user_id uuid NOT NULL,
+     token_hash VARCHAR UNIQUE NOT NULL,
+     attempts INT NOT NULL DEFAULT 0,
+     expired_at TIMESTAMPTZ NOT NULL,
+     used_at TIMESTAMPTZ,
+     created_at TIMESTAMPTZ DEFAULT now()
+);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS role_permissions CASCADE;
+
+DROP TABLE IF EXISTS permissions CASCADE;
\ No newline at end of file
@@ -0,0 +1,62 @@
+CREATE TABLE IF NOT EXISTS permissions (
+     id uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+     name VARCHAR UNIQUE NOT NULL,
+     description VARCHAR,
+     created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+     role_id uuid NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+     permission_id uuid NOT NULL REFERENCES permissions (id) ON DELETE CASCADE,
+     created_at TIMESTAMPTZ DEFAULT now(),
+     PRIMARY KEY (role_id, permission_id)
+);
+
+INSERT INTO permissions (name, description) VALUES
+     ('lapak:list', 'Melihat seluruh lapak'),
+     ('lapak:list:location', 'Melihat lapak sesuai lokasi'),
+     ('lapak:update', 'Mengubah data & status lapak'),
+     ('lapak:delete', 'Menghapus lapak'),
+     ('product:create', 'Menambah produk & gambar produk'),
+     ('order:create', 'Membuat pemesanan'),
+     ('order:list', 'Melihat seluruh pemesanan'),
+     ('order:list:own', 'Melihat pemesanan pribadi'),
+     ('order:update:own', 'Mengubah pemesanan pribadi'),
+     ('order:delete:own', 'Menghapus pemesanan pribadi'),
+     ('user:list', 'Melihat seluruh user'),
+     ('user:read', 'Melihat detail user'),
+     ('user:delete', 'Menghapus user'),
+     ('blacklist:manage', 'Mengelola blacklist user'),
+     ('mfa:manage', 'Mengelola two-factor authentication pribadi'),
+     ('role:manage', 'Mengelola role & permission')
+ON CONFLICT (name) DO NOTHING;
+
+-- existing database already has roles seeded, fresh database gets mapping from db/data
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM (VALUES
+     ('Admin', 'lapak:list'),
+     ('Admin', 'lapak:update'),
+     ('Admin', 'lapak:delete'),
+     ('Admin', 'product:create'),
+     ('Admin', 'order:list'),
+     ('Admin', 'user:list'),
+     ('Admin', 'user:read'),
+     ('Admin', 'user:delete'),
+     ('Admin', 'blacklist:manage'),
+     ('Admin', 'mfa:manage'),
+     ('Admin', 'role:manage'),
+     ('Penjual', 'lapak:update'),
+     ('Penjual', 'product:create'),
+     ('Penjual', 'order:list:own'),
+     ('Penjual', 'order:update:own'),
+     ('Penjual', 'order:delete:own'),
+     ('Penjual', 'mfa:manage'),
+     ('Pembeli', 'lapak:list:location'),
+     ('Pembeli', 'order:create'),
+     ('Pembeli', 'order:list:own'),
+     ('Pembeli', 'order:update:own'),
+     ('Pembeli', 'order:delete:own')
+) AS m (role_name, permission_name)
+JOIN roles r ON r.name = m.role_name
+JOIN permissions p ON p.name = m.permission_name
+ON CONFLICT DO NOTHING;
\ No newline at end of file
@@ -0,0 +1 @@
+DELETE FROM permissions WHERE name IN ('lapak:manage:any', 'product:manage:any', 'order:manage:any');
\ No newline at end of file
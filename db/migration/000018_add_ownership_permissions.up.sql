@@ -0,0 +1,11 @@
+INSERT INTO permissions (name, description) VALUES
+     ('lapak:manage:any', 'Mengelola lapak milik siapa pun'),
+     ('product:manage:any', 'Mengelola produk pada lapak milik siapa pun'),
+     ('order:manage:any', 'Mengelola pemesanan milik siapa pun')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r
+JOIN permissions p ON p.name IN ('lapak:manage:any', 'product:manage:any', 'order:manage:any')
+WHERE r.name = 'Admin'
+ON CONFLICT DO NOTHING;
\ No newline at end of file
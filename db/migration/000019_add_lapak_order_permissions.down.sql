@@ -0,0 +1 @@
+DELETE FROM permissions WHERE name IN ('order:list:lapak', 'order:update:lapak');
\ No newline at end of file
@@ -0,0 +1,10 @@
+INSERT INTO permissions (name, description) VALUES
+     ('order:list:lapak', 'Melihat pemesanan yang masuk ke lapak sendiri'),
+     ('order:update:lapak', 'Memproses pemesanan yang masuk ke lapak sendiri')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r
+JOIN permissions p ON p.name IN ('order:list:lapak', 'order:update:lapak')
+WHERE r.name IN ('Admin', 'Penjual')
+ON CONFLICT DO NOTHING;
\ No newline at end of file
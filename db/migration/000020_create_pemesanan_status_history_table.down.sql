@@ -0,0 +1 @@
+DROP TABLE IF EXISTS pemesanan_status_history CASCADE;
\ No newline at end of file
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS pemesanan_status_history (
+     id uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+     pemesanan_id uuid NOT NULL,
+     from_status pemesanan_status,
+     to_status pemesanan_status NOT NULL,
+     actor_id uuid NOT NULL,
+     actor_role VARCHAR NOT NULL,
+     created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS pemesanan_status_history_pemesanan_id_idx ON pemesanan_status_history (pemesanan_id);
\ No newline at end of file
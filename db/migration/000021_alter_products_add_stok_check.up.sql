@@ -0,0 +1,2 @@
+ALTER TABLE products
+     ADD CONSTRAINT products_stok_non_negative CHECK (stok >= 0);
\ No newline at end of file
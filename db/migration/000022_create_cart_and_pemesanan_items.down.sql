@@ -0,0 +1,11 @@
+DELETE FROM pemesanan_status_history WHERE pemesanan_id IN (SELECT id FROM pemesanan WHERE product_id IS NULL);
+
+DELETE FROM pemesanan WHERE product_id IS NULL;
+
+ALTER TABLE pemesanan
+     DROP COLUMN IF EXISTS lapak_id,
+     ALTER COLUMN product_id SET NOT NULL;
+
+DROP TABLE IF EXISTS pemesanan_items CASCADE;
+
+DROP TABLE IF EXISTS cart_items CASCADE;
\ No newline at end of file
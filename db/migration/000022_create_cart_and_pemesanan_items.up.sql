@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS cart_items (
+     id uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+     user_id uuid NOT NULL,
+     product_id uuid NOT NULL,
+     qty INTEGER NOT NULL CHECK (qty > 0),
+     created_at TIMESTAMPTZ DEFAULT now(),
+     updated_at TIMESTAMPTZ,
+     UNIQUE (user_id, product_id)
+);
+
+CREATE TABLE IF NOT EXISTS pemesanan_items (
+     id uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+     pemesanan_id uuid NOT NULL,
+     product_id uuid NOT NULL,
+     qty INTEGER NOT NULL,
+     created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS pemesanan_items_pemesanan_id_idx ON pemesanan_items (pemesanan_id);
+
+ALTER TABLE pemesanan
+     ADD COLUMN IF NOT EXISTS lapak_id uuid,
+     ALTER COLUMN product_id DROP NOT NULL;
+
+UPDATE pemesanan pem SET lapak_id = p.lapak_id FROM products p WHERE p.id = pem.product_id;
+
+INSERT INTO pemesanan_items (pemesanan_id, product_id, qty, created_at)
+SELECT id, product_id, qty, created_at FROM pemesanan WHERE product_id IS NOT NULL;
\ No newline at end of file
@@ -0,0 +1,10 @@
+ALTER TABLE pemesanan_items
+     DROP COLUMN IF EXISTS unit_price,
+     DROP COLUMN IF EXISTS subtotal;
+
+ALTER TABLE pemesanan
+     DROP COLUMN IF EXISTS total_price;
+
+ALTER TABLE products
+     DROP CONSTRAINT IF EXISTS products_price_non_negative,
+     DROP COLUMN IF EXISTS price;
\ No newline at end of file
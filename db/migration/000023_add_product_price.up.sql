@@ -0,0 +1,10 @@
+ALTER TABLE products
+     ADD COLUMN IF NOT EXISTS price BIGINT NOT NULL DEFAULT 0,
+     ADD CONSTRAINT products_price_non_negative CHECK (price >= 0);
+
+ALTER TABLE pemesanan
+     ADD COLUMN IF NOT EXISTS total_price BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE pemesanan_items
+     ADD COLUMN IF NOT EXISTS unit_price BIGINT NOT NULL DEFAULT 0,
+     ADD COLUMN IF NOT EXISTS subtotal BIGINT NOT NULL DEFAULT 0;
\ No newline at end of file
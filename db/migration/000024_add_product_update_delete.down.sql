@@ -0,0 +1,4 @@
+DELETE FROM permissions WHERE name IN ('product:update', 'product:delete');
+
+ALTER TABLE products
+     DROP COLUMN IF EXISTS deleted_at;
\ No newline at end of file
@@ -0,0 +1,13 @@
+ALTER TABLE products
+     ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+INSERT INTO permissions (name, description) VALUES
+     ('product:update', 'Mengubah produk pada lapak sendiri'),
+     ('product:delete', 'Menghapus produk pada lapak sendiri')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r
+JOIN permissions p ON p.name IN ('product:update', 'product:delete')
+WHERE r.name IN ('Admin', 'Penjual')
+ON CONFLICT DO NOTHING;
\ No newline at end of file
@@ -0,0 +1,7 @@
+DELETE FROM permissions WHERE name = 'trash:manage';
+
+ALTER TABLE lapak
+     DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE users
+     DROP COLUMN IF EXISTS deleted_at;
\ No newline at end of file
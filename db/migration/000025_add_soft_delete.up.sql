@@ -0,0 +1,15 @@
+ALTER TABLE users
+     ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+ALTER TABLE lapak
+     ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+INSERT INTO permissions (name, description) VALUES
+     ('trash:manage', 'Melihat dan memulihkan data yang dihapus')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r
+JOIN permissions p ON p.name = 'trash:manage'
+WHERE r.name = 'Admin'
+ON CONFLICT DO NOTHING;
\ No newline at end of file
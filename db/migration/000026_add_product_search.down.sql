@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS products_name_trgm_idx;
+DROP INDEX IF EXISTS products_search_vector_idx;
+
+DROP TRIGGER IF EXISTS lokasi_search_vector_update ON lokasi;
+DROP TRIGGER IF EXISTS lapak_search_vector_update ON lapak;
+DROP TRIGGER IF EXISTS products_search_vector_update ON products;
+
+DROP FUNCTION IF EXISTS lokasi_search_vector_trigger();
+DROP FUNCTION IF EXISTS lapak_search_vector_trigger();
+DROP FUNCTION IF EXISTS products_search_vector_trigger();
+DROP FUNCTION IF EXISTS product_search_vector(TEXT, TEXT, uuid);
+
+ALTER TABLE products
+     DROP COLUMN IF EXISTS search_vector;
+
+DROP TEXT SEARCH CONFIGURATION IF EXISTS product_search;
\ No newline at end of file
@@ -0,0 +1,67 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- indonesian snowball stemmer is only shipped since postgres 13, falling back to simple elsewhere
+DO $$
+BEGIN
+     IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'product_search') THEN
+          IF EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'indonesian') THEN
+               CREATE TEXT SEARCH CONFIGURATION product_search (COPY = indonesian);
+          ELSE
+               CREATE TEXT SEARCH CONFIGURATION product_search (COPY = simple);
+          END IF;
+     END IF;
+END
+$$;
+
+ALTER TABLE products
+     ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+CREATE OR REPLACE FUNCTION product_search_vector(p_name TEXT, p_kategori TEXT, p_lapak_id uuid) RETURNS tsvector AS $$
+     SELECT setweight(to_tsvector('product_search', COALESCE(p_name, '')), 'A') ||
+          setweight(to_tsvector('product_search', COALESCE(p_kategori, '')), 'B') ||
+          setweight(to_tsvector('product_search', COALESCE(l.name, '')), 'B') ||
+          setweight(to_tsvector('product_search', COALESCE(loc.daerah, '')), 'C')
+     FROM (SELECT 1) AS one
+     LEFT JOIN lapak l ON l.id = p_lapak_id
+     LEFT JOIN lokasi loc ON loc.id = l.location_id
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION products_search_vector_trigger() RETURNS trigger AS $$
+BEGIN
+     NEW.search_vector := product_search_vector(NEW.name, NEW.product_kategori::text, NEW.lapak_id);
+     RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER products_search_vector_update
+     BEFORE INSERT OR UPDATE OF name, product_kategori, lapak_id ON products
+     FOR EACH ROW EXECUTE FUNCTION products_search_vector_trigger();
+
+CREATE OR REPLACE FUNCTION lapak_search_vector_trigger() RETURNS trigger AS $$
+BEGIN
+     UPDATE products SET search_vector = product_search_vector(name, product_kategori::text, lapak_id)
+     WHERE lapak_id = NEW.id;
+     RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER lapak_search_vector_update
+     AFTER UPDATE OF name, location_id ON lapak
+     FOR EACH ROW EXECUTE FUNCTION lapak_search_vector_trigger();
+
+CREATE OR REPLACE FUNCTION lokasi_search_vector_trigger() RETURNS trigger AS $$
+BEGIN
+     UPDATE products SET search_vector = product_search_vector(name, product_kategori::text, lapak_id)
+     WHERE lapak_id IN (SELECT id FROM lapak WHERE location_id = NEW.id);
+     RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER lokasi_search_vector_update
+     AFTER UPDATE OF daerah ON lokasi
+     FOR EACH ROW EXECUTE FUNCTION lokasi_search_vector_trigger();
+
+UPDATE products SET search_vector = product_search_vector(name, product_kategori::text, lapak_id);
+
+CREATE INDEX IF NOT EXISTS products_search_vector_idx ON products USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS products_name_trgm_idx ON products USING GIN (name gin_trgm_ops);
\ No newline at end of file
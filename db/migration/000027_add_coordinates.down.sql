@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS lapak_coordinate_idx;
+
+ALTER TABLE users_profile
+     DROP CONSTRAINT IF EXISTS users_profile_coordinate_valid,
+     DROP COLUMN IF EXISTS longitude,
+     DROP COLUMN IF EXISTS latitude;
+
+ALTER TABLE lapak
+     DROP CONSTRAINT IF EXISTS lapak_coordinate_valid,
+     DROP COLUMN IF EXISTS longitude,
+     DROP COLUMN IF EXISTS latitude;
\ No newline at end of file
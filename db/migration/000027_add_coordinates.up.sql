@@ -0,0 +1,18 @@
+ALTER TABLE lapak
+     ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION,
+     ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION,
+     ADD CONSTRAINT lapak_coordinate_valid CHECK (
+          (latitude IS NULL AND longitude IS NULL) OR
+          (latitude BETWEEN -90 AND 90 AND longitude BETWEEN -180 AND 180)
+     );
+
+ALTER TABLE users_profile
+     ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION,
+     ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION,
+     ADD CONSTRAINT users_profile_coordinate_valid CHECK (
+          (latitude IS NULL AND longitude IS NULL) OR
+          (latitude BETWEEN -90 AND 90 AND longitude BETWEEN -180 AND 180)
+     );
+
+-- bounding box prefilter of nearby query, haversine is only computed on rows inside the box
+CREATE INDEX IF NOT EXISTS lapak_coordinate_idx ON lapak (latitude, longitude) WHERE latitude IS NOT NULL;
\ No newline at end of file
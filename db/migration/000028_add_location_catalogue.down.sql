@@ -0,0 +1,5 @@
+DELETE FROM permissions WHERE name = 'location:manage';
+
+DROP INDEX IF EXISTS lokasi_provinsi_kota_idx;
+
+DROP INDEX IF EXISTS lokasi_unique_idx;
\ No newline at end of file
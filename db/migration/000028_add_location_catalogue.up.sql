@@ -0,0 +1,13 @@
+CREATE UNIQUE INDEX IF NOT EXISTS lokasi_unique_idx ON lokasi (lower(provinsi), lower(kota), lower(daerah));
+
+CREATE INDEX IF NOT EXISTS lokasi_provinsi_kota_idx ON lokasi (provinsi, kota);
+
+INSERT INTO permissions (name, description) VALUES
+     ('location:manage', 'Mengelola data lokasi')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r
+JOIN permissions p ON p.name = 'location:manage'
+WHERE r.name = 'Admin'
+ON CONFLICT DO NOTHING;
\ No newline at end of file
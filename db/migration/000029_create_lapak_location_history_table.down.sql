@@ -0,0 +1 @@
+DROP TABLE IF EXISTS lapak_location_history CASCADE;
\ No newline at end of file
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS lapak_location_history (
+     id uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+     lapak_id uuid NOT NULL,
+     from_location_id uuid NOT NULL,
+     to_location_id uuid NOT NULL,
+     actor_id uuid NOT NULL,
+     actor_role VARCHAR NOT NULL,
+     created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS lapak_location_history_lapak_id_idx ON lapak_location_history (lapak_id);
\ No newline at end of file
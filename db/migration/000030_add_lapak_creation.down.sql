@@ -0,0 +1,9 @@
+DELETE FROM permissions WHERE name = 'lapak:create';
+
+ALTER TABLE roles
+     DROP COLUMN IF EXISTS max_lapak;
+
+DROP INDEX IF EXISTS lapak_user_id_idx;
+
+ALTER TABLE lapak
+     DROP COLUMN IF EXISTS description;
\ No newline at end of file
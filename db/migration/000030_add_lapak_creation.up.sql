@@ -0,0 +1,20 @@
+ALTER TABLE lapak
+     ADD COLUMN IF NOT EXISTS description VARCHAR;
+
+CREATE INDEX IF NOT EXISTS lapak_user_id_idx ON lapak (user_id);
+
+-- NULL means the role may own any number of lapak
+ALTER TABLE roles
+     ADD COLUMN IF NOT EXISTS max_lapak INTEGER CHECK (max_lapak >= 0);
+
+UPDATE roles SET max_lapak = 3 WHERE name = 'Penjual';
+
+INSERT INTO permissions (name, description) VALUES
+     ('lapak:create', 'Membuat lapak baru')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r
+JOIN permissions p ON p.name = 'lapak:create'
+WHERE r.name IN ('Admin', 'Penjual')
+ON CONFLICT DO NOTHING;
\ No newline at end of file
@@ -0,0 +1,6 @@
+ALTER TABLE lapak
+     DROP COLUMN IF EXISTS landmark,
+     DROP COLUMN IF EXISTS address,
+     DROP COLUMN IF EXISTS whatsapp,
+     DROP COLUMN IF EXISTS logo_url,
+     DROP COLUMN IF EXISTS banner_url;
\ No newline at end of file
@@ -0,0 +1,6 @@
+ALTER TABLE lapak
+     ADD COLUMN IF NOT EXISTS banner_url VARCHAR,
+     ADD COLUMN IF NOT EXISTS logo_url VARCHAR,
+     ADD COLUMN IF NOT EXISTS whatsapp VARCHAR,
+     ADD COLUMN IF NOT EXISTS address VARCHAR,
+     ADD COLUMN IF NOT EXISTS landmark VARCHAR;
\ No newline at end of file
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS lapak_holidays CASCADE;
+
+DROP TABLE IF EXISTS lapak_opening_hours CASCADE;
+
+ALTER TABLE lapak
+     DROP COLUMN IF EXISTS scheduled_status,
+     DROP COLUMN IF EXISTS timezone;
\ No newline at end of file
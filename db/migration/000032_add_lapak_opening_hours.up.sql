@@ -0,0 +1,24 @@
+ALTER TABLE lapak
+     ADD COLUMN IF NOT EXISTS timezone VARCHAR NOT NULL DEFAULT 'Asia/Jakarta',
+     ADD COLUMN IF NOT EXISTS scheduled_status lapak_status;
+
+-- close_time not after open_time means the lapak closes on the next day
+CREATE TABLE IF NOT EXISTS lapak_opening_hours (
+     id uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+     lapak_id uuid NOT NULL,
+     day_of_week SMALLINT NOT NULL CHECK (day_of_week BETWEEN 0 AND 6),
+     open_time TIME NOT NULL,
+     close_time TIME NOT NULL,
+     created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS lapak_opening_hours_lapak_id_idx ON lapak_opening_hours (lapak_id);
+
+CREATE TABLE IF NOT EXISTS lapak_holidays (
+     id uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+     lapak_id uuid NOT NULL,
+     holiday_date DATE NOT NULL,
+     note VARCHAR,
+     created_at TIMESTAMPTZ DEFAULT now(),
+     UNIQUE (lapak_id, holiday_date)
+);
\ No newline at end of file
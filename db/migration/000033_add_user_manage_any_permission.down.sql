@@ -0,0 +1 @@
+DELETE FROM permissions WHERE name = 'user:manage:any';
\ No newline at end of file
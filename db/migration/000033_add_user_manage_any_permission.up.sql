@@ -0,0 +1,9 @@
+INSERT INTO permissions (name, description) VALUES
+     ('user:manage:any', 'Mengubah akun & profil milik pengguna siapa pun')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r
+JOIN permissions p ON p.name = 'user:manage:any'
+WHERE r.name = 'Admin'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS products_lapak_id_idx;
+DROP INDEX IF EXISTS lapak_name_trgm_idx;
+
+DO $$
+BEGIN
+     EXECUTE format('ALTER DATABASE %I RESET pg_trgm.word_similarity_threshold', current_database());
+END
+$$;
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- <% matches against pg_trgm.word_similarity_threshold instead of a literal, which lets trigram index serve the search
+DO $$
+BEGIN
+     EXECUTE format('ALTER DATABASE %I SET pg_trgm.word_similarity_threshold = 0.4', current_database());
+END
+$$;
+
+-- lapak name is matched on its own so products of a matching lapak are found through products_lapak_id_idx
+CREATE INDEX IF NOT EXISTS lapak_name_trgm_idx ON lapak USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS products_lapak_id_idx ON products (lapak_id);
\ No newline at end of file
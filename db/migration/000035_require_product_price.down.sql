@@ -0,0 +1,9 @@
+ALTER TABLE products
+     DROP CONSTRAINT IF EXISTS products_price_positive;
+
+UPDATE products SET price = 0 WHERE price IS NULL;
+
+ALTER TABLE products
+     ALTER COLUMN price SET DEFAULT 0,
+     ALTER COLUMN price SET NOT NULL,
+     ADD CONSTRAINT products_price_non_negative CHECK (price >= 0);
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- product existing before price was introduced was backfilled into 0, it is not priced yet rather than free
+ALTER TABLE products
+     ALTER COLUMN price DROP NOT NULL,
+     ALTER COLUMN price DROP DEFAULT;
+
+UPDATE products SET price = NULL WHERE price = 0;
+
+ALTER TABLE products
+     DROP CONSTRAINT IF EXISTS products_price_non_negative,
+     ADD CONSTRAINT products_price_positive CHECK (price > 0);
\ No newline at end of file
@@ -1,23 +1,18 @@
 package helper
 
 import (
-	"math/rand"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"strings"
-	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
-
-func init() {
-	rand.Seed(time.Now().UnixNano())
-}
-
 const (
 	letterBytes   = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	letterIdxBits = 6                    // 6 bits to represent a letter index
 	letterIdxMask = 1<<letterIdxBits - 1 // All 1-bits, as many as letterIdxBits
-	letterIdxMax  = 63 / letterIdxBits   // # of letter indices fitting in 63 bits
 )
 
 // HashPassword will transform from plain password into hashed password
@@ -32,27 +27,38 @@ func CheckPasswordHash(hash,password string) bool {
 	return err == nil
 }
 
+// HashToken will transform plain opaque token into sha256 hex digest before stored in database
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 // GenerateRandomToken is wrapper function to generating a unique random token
 func GenerateRandomToken(n int) string {
-	return strings.Trim(randStringBytesMaskImprSrcSB(n)," ")
+	return strings.Trim(randStringBytesCrypto(n), " ")
 }
 
-// randStringBytesMaskImprSrcSB will generating a unique random alphanumeric with fixed length
-func randStringBytesMaskImprSrcSB(n int) string {
-	var src = rand.NewSource(time.Now().UnixNano())
+// randStringBytesCrypto will generating a unique random alphanumeric with fixed length from crypto/rand
+func randStringBytesCrypto(n int) string {
 	sb := strings.Builder{}
 	sb.Grow(n)
-	// A src.Int63() generates 63 random bits, enough for letterIdxMax characters!
-	for i, cache, remain := n-1, src.Int63(), letterIdxMax; i >= 0; {
-		if remain == 0 {
-			cache, remain = src.Int63(), letterIdxMax
+
+	buf := make([]byte, n)
+	for sb.Len() < n {
+		if _, err := rand.Read(buf); err != nil {
+			// crypto/rand only fails when the OS entropy source is broken, never fall back to a weak source
+			panic(err)
 		}
-		if idx := int(cache & letterIdxMask); idx < len(letterBytes) {
-			sb.WriteByte(letterBytes[idx])
-			i--
+
+		for _, b := range buf {
+			// rejecting index >= len(letterBytes) keeps every letter equally likely
+			if idx := int(b & letterIdxMask); idx < len(letterBytes) {
+				sb.WriteByte(letterBytes[idx])
+				if sb.Len() == n {
+					break
+				}
+			}
 		}
-		cache >>= letterIdxBits
-		remain--
 	}
 
 	return sb.String()
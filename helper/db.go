@@ -72,3 +72,104 @@ func QueryUpdateBuilder(tableName string, mapArgs map[string]interface{}, wheres
 
 	return
 }
+
+// QuerySelectBuilder generate select query whose filter value is always bound as $n parameter
+type QuerySelectBuilder struct {
+	query     string
+	wheres    []string
+	args      []interface{}
+	orderBy   string
+	orderArgs []interface{}
+	limit     int
+	offset    int
+}
+
+// NewQuerySelectBuilder create select builder from base query, base query must not contain WHERE clause, every ? inside it is bound to args in order
+func NewQuerySelectBuilder(query string, args ...interface{}) *QuerySelectBuilder {
+	qb := &QuerySelectBuilder{}
+	for _, arg := range args {
+		qb.args = append(qb.args, arg)
+		query = strings.Replace(query, "?", fmt.Sprintf("$%d", len(qb.args)), 1)
+	}
+
+	qb.query = query
+
+	return qb
+}
+
+// Where add condition joined with AND, every ? inside condition is bound to args in order
+func (qb *QuerySelectBuilder) Where(condition string, args ...interface{}) *QuerySelectBuilder {
+	for _, arg := range args {
+		qb.args = append(qb.args, arg)
+		condition = strings.Replace(condition, "?", fmt.Sprintf("$%d", len(qb.args)), 1)
+	}
+
+	qb.wheres = append(qb.wheres, condition)
+
+	return qb
+}
+
+// OrderBy set ORDER BY clause, column is not bound so caller must only pass whitelisted column, every ? is bound to args in order
+func (qb *QuerySelectBuilder) OrderBy(orderBy string, args ...interface{}) *QuerySelectBuilder {
+	qb.orderBy = orderBy
+	qb.orderArgs = args
+
+	return qb
+}
+
+// Limit set LIMIT & OFFSET bound as parameter, zero limit means no limit
+func (qb *QuerySelectBuilder) Limit(limit int, offset int) *QuerySelectBuilder {
+	qb.limit = limit
+	qb.offset = offset
+
+	return qb
+}
+
+// Build return generated query & its args
+func (qb *QuerySelectBuilder) Build() (query string, args []interface{}) {
+	query = qb.filteredQuery()
+	args = append(args, qb.args...)
+
+	if qb.orderBy != "" {
+		orderBy := qb.orderBy
+		for _, arg := range qb.orderArgs {
+			args = append(args, arg)
+			orderBy = strings.Replace(orderBy, "?", fmt.Sprintf("$%d", len(args)), 1)
+		}
+
+		query += " ORDER BY " + orderBy
+	}
+
+	if qb.limit > 0 {
+		args = append(args, qb.limit, qb.offset)
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
+
+	return query, args
+}
+
+// BuildCount return query counting every row matched by filter, ignoring order & limit
+func (qb *QuerySelectBuilder) BuildCount() (query string, args []interface{}) {
+	return "SELECT COUNT(*) FROM (" + qb.filteredQuery() + ") AS total_data", qb.args
+}
+
+// BuildGroupCount return query counting every row matched by filter per value of given column, most common value first
+func (qb *QuerySelectBuilder) BuildGroupCount(column string) (query string, args []interface{}) {
+	return "SELECT " + column + ", COUNT(*) FROM (" + qb.filteredQuery() + ") AS group_data GROUP BY 1 ORDER BY 2 DESC, 1", qb.args
+}
+
+// filteredQuery return base query along with its WHERE clause
+func (qb *QuerySelectBuilder) filteredQuery() string {
+	if len(qb.wheres) == 0 {
+		return qb.query
+	}
+
+	return qb.query + " WHERE " + strings.Join(qb.wheres, " AND ")
+}
+
+// LikeContains wrap value as LIKE pattern matching anywhere, escaping wildcard inside value
+func LikeContains(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+	return "%" + replacer.Replace(value) + "%"
+}
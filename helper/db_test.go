@@ -0,0 +1,103 @@
+package helper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQuerySelectBuilderRenumbersPlaceholders(t *testing.T) {
+	qb := NewQuerySelectBuilder("SELECT * FROM (SELECT id, name FROM t WHERE a BETWEEN ? AND ?) AS sub", 1, 2).
+		Where("sub.name = ?", "x").
+		Where("sub.deleted_at IS NULL").
+		Where("(sub.a = ? OR sub.b = ?)", 3, 4).
+		OrderBy("similarity(?, sub.name) DESC, sub.id DESC", "y").
+		Limit(10, 20)
+
+	q, args := qb.Build()
+
+	wantQuery := "SELECT * FROM (SELECT id, name FROM t WHERE a BETWEEN $1 AND $2) AS sub" +
+		" WHERE sub.name = $3 AND sub.deleted_at IS NULL AND (sub.a = $4 OR sub.b = $5)" +
+		" ORDER BY similarity($6, sub.name) DESC, sub.id DESC LIMIT $7 OFFSET $8"
+	if q != wantQuery {
+		t.Errorf("Build query\n got %s\nwant %s", q, wantQuery)
+	}
+
+	wantArgs := []interface{}{1, 2, "x", 3, 4, "y", 10, 20}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("Build args = %v, want %v", args, wantArgs)
+	}
+
+	filterArgs := []interface{}{1, 2, "x", 3, 4}
+	filtered := "SELECT * FROM (SELECT id, name FROM t WHERE a BETWEEN $1 AND $2) AS sub" +
+		" WHERE sub.name = $3 AND sub.deleted_at IS NULL AND (sub.a = $4 OR sub.b = $5)"
+
+	q, args = qb.BuildCount()
+	if want := "SELECT COUNT(*) FROM (" + filtered + ") AS total_data"; q != want {
+		t.Errorf("BuildCount query\n got %s\nwant %s", q, want)
+	}
+
+	if !reflect.DeepEqual(args, filterArgs) {
+		t.Errorf("BuildCount args = %v, want %v", args, filterArgs)
+	}
+
+	q, args = qb.BuildGroupCount("sub.name")
+	if want := "SELECT sub.name, COUNT(*) FROM (" + filtered + ") AS group_data GROUP BY 1 ORDER BY 2 DESC, 1"; q != want {
+		t.Errorf("BuildGroupCount query\n got %s\nwant %s", q, want)
+	}
+
+	if !reflect.DeepEqual(args, filterArgs) {
+		t.Errorf("BuildGroupCount args = %v, want %v", args, filterArgs)
+	}
+}
+
+func TestQuerySelectBuilderBuildIsRepeatable(t *testing.T) {
+	qb := NewQuerySelectBuilder("SELECT id FROM t").Where("name = ?", "x").OrderBy("id ASC").Limit(5, 0)
+
+	first, firstArgs := qb.Build()
+	second, secondArgs := qb.Build()
+
+	if first != second || !reflect.DeepEqual(firstArgs, secondArgs) {
+		t.Errorf("second Build differs: %s %v, first %s %v", second, secondArgs, first, firstArgs)
+	}
+
+	if want := "SELECT id FROM t WHERE name = $1 ORDER BY id ASC LIMIT $2 OFFSET $3"; first != want {
+		t.Errorf("Build query\n got %s\nwant %s", first, want)
+	}
+}
+
+func TestQuerySelectBuilderKeepsValueOutOfQuery(t *testing.T) {
+	payloads := []string{`' OR 1=1--`, `%`, `_`, `'; DROP TABLE users; --`, `?`, `$1`}
+
+	for _, payload := range payloads {
+		q, args := NewQuerySelectBuilder("SELECT id FROM t").Where("name ILIKE ?", LikeContains(payload)).Where("kind = ?", payload).Build()
+
+		if want := "SELECT id FROM t WHERE name ILIKE $1 AND kind = $2"; q != want {
+			t.Errorf("%q: query\n got %s\nwant %s", payload, q, want)
+		}
+
+		if !reflect.DeepEqual(args, []interface{}{LikeContains(payload), payload}) {
+			t.Errorf("%q: args = %v", payload, args)
+		}
+	}
+}
+
+func TestLikeContains(t *testing.T) {
+	cases := []struct {
+		value string
+		want  string
+	}{
+		{"", `%%`},
+		{"sayur", `%sayur%`},
+		{"100%", `%100\%%`},
+		{"a_b", `%a\_b%`},
+		{`c:\dir`, `%c:\\dir%`},
+		{`\%_`, `%\\\%\_%`},
+		{`' OR 1=1--`, `%' OR 1=1--%`},
+	}
+
+	for _, tc := range cases {
+		if got := LikeContains(tc.value); got != tc.want {
+			t.Errorf("LikeContains(%q) = %q, want %q", tc.value, got, tc.want)
+		}
+	}
+}
@@ -15,4 +15,12 @@ func GetEnvInt(key string) int {
 	intEnv,_ := strconv.Atoi(getEnv)
 
 	return intEnv
+}
+
+// GetEnvBool return env boolean based on key
+func GetEnvBool(key string) bool {
+	getEnv := os.Getenv(key)
+	boolEnv, _ := strconv.ParseBool(getEnv)
+
+	return boolEnv
 }
\ No newline at end of file
@@ -1,20 +1,35 @@
 package helper
 
 import (
+	"net/url"
+	"strconv"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/wiormiw/GrowBaks/model"
 )
 
 // ResponseFormatter returning formatted JSON responses
 func ResponseFormatter[T any](ctx *fiber.Ctx, statusCode int, err error, message string, data T) error {
+	return ResponseFormatterWithMeta(ctx, statusCode, err, message, data, nil)
+}
+
+// ResponseFormatterWithMeta returning formatted JSON responses along with meta block, meta is omitted when nil
+func ResponseFormatterWithMeta[T any](ctx *fiber.Ctx, statusCode int, err error, message string, data T, meta *model.Metadata) error {
 	ctx.Accepts("application/json")
 
 	if statusCode < 400 {
-		return ctx.Status(statusCode).JSON(&fiber.Map{
+		body := fiber.Map{
 			"status_code": statusCode,
 			"message":     message,
 			"error":       nil,
 			"data":        data,
-		})
+		}
+
+		if meta != nil {
+			body["meta"] = meta
+		}
+
+		return ctx.Status(statusCode).JSON(&body)
 
 	}
 
@@ -26,5 +41,43 @@ func ResponseFormatter[T any](ctx *fiber.Ctx, statusCode int, err error, message
 	})
 }
 
+// BuildPageLinks will building self, first, last, prev & next links of current request by replacing given query param
+func BuildPageLinks(ctx *fiber.Ctx, meta *model.Metadata) map[string]string {
+	query, _ := url.ParseQuery(string(ctx.Request().URI().QueryString()))
+
+	link := func(key string, value string) string {
+		query.Set(key, value)
+		return ctx.BaseURL() + ctx.Path() + "?" + query.Encode()
+	}
+
+	links := map[string]string{
+		"self": ctx.BaseURL() + ctx.OriginalURL(),
+	}
+
+	// cursor based page only knows its next page
+	if query.Has("cursor") {
+		if meta.NextCursor != "" {
+			links["next"] = link("cursor", meta.NextCursor)
+		}
+
+		return links
+	}
+
+	links["first"] = link("page", "1")
+	if meta.TotalPage > 0 {
+		links["last"] = link("page", strconv.Itoa(meta.TotalPage))
+	}
+
+	if meta.Page > 1 {
+		links["prev"] = link("page", strconv.Itoa(meta.Page-1))
+	}
+
+	if meta.Page < meta.TotalPage {
+		links["next"] = link("page", strconv.Itoa(meta.Page+1))
+	}
+
+	return links
+}
+
 // OptionsHandler will handing preflight requests
 func OptionsHandler(ctx *fiber.Ctx) error { return nil }
@@ -20,9 +20,17 @@ func ServeHTTP(app *application.App) *fiber.App {
 
 // setupRouter is function to manage all routings
 func setupRouter(app *application.App) {
-	var dep = application.SetupDependencyInjection(app)
+	registerRoutes(app.Application, application.SetupDependencyInjection(app))
+}
+
+// registerRoutes is function to register every route of given dependency into router
+func registerRoutes(router *fiber.App, dep *application.Dependency) {
+	var (
+		validateJWT           = dep.JWTMiddleware.ValidateJWTMiddleware
+		validateJWTEnrollment = dep.JWTMiddleware.ValidateJWTEnrollmentMiddleware
+	)
 
-	v1 := app.Application.Group("/v1", func(ctx *fiber.Ctx) error {
+	v1 := router.Group("/v1", func(ctx *fiber.Ctx) error {
 		ctx.Set("Version", "v1")
 		return ctx.Next()
 	})
@@ -37,49 +45,141 @@ func setupRouter(app *application.App) {
 			Expiration: 15 * time.Minute,
 			Max:        5,
 			LimitReached: func(ctx *fiber.Ctx) error {
-				return helper.ResponseFormatter[any](ctx, fiber.StatusTooManyRequests, nil, "Login Attempts already reached the limit, tell our super admin about resetting a password, Thank you", nil)
+				return helper.ResponseFormatter[any](ctx, fiber.StatusTooManyRequests, nil, "Login Attempts already reached the limit, reset your password through /v1/auth/password/forgot, Thank you", nil)
 			},
 		}), dep.AuthController.Login)
+		v1.Post("/auth/login/mfa", limiter.New(limiter.Config{
+			Expiration: 15 * time.Minute,
+			Max:        5,
+			LimitReached: func(ctx *fiber.Ctx) error {
+				return helper.ResponseFormatter[any](ctx, fiber.StatusTooManyRequests, nil, "Two-Factor Authentication attempts already reached the limit, wait till 15 min", nil)
+			},
+		}), dep.AuthController.LoginMFA)
+		v1.Post("/auth/refresh", dep.AuthController.Refresh)
+		v1.Post("/auth/logout", validateJWTEnrollment, dep.AuthController.Logout)
+		v1.Post("/auth/password/forgot", limiter.New(limiter.Config{
+			Expiration: 15 * time.Minute,
+			Max:        3,
+			LimitReached: func(ctx *fiber.Ctx) error {
+				return helper.ResponseFormatter[any](ctx, fiber.StatusTooManyRequests, nil, "Reset password requests already reached the limit, wait till 15 min", nil)
+			},
+		}), dep.AuthController.ForgotPassword)
+		v1.Post("/auth/password/reset", dep.AuthController.ResetPassword)
+		v1.Get("/auth/verify", dep.AuthController.VerifyEmail)
+		v1.Post("/auth/verify/resend", limiter.New(limiter.Config{
+			Expiration: 15 * time.Minute,
+			Max:        3,
+			LimitReached: func(ctx *fiber.Ctx) error {
+				return helper.ResponseFormatter[any](ctx, fiber.StatusTooManyRequests, nil, "Resend verification requests already reached the limit, wait till 15 min", nil)
+			},
+		}), dep.AuthController.ResendVerification)
+		v1.Get("/auth/google/login", dep.AuthController.GoogleLogin)
+		v1.Get("/auth/google/callback", dep.AuthController.GoogleCallback)
+
+		// MFA SECTION
+		v1.Post("/auth/mfa/enroll", validateJWTEnrollment, m.Require("mfa:manage"), dep.MFAController.Enroll)
+		v1.Post("/auth/mfa/verify", validateJWTEnrollment, m.Require("mfa:manage"), dep.MFAController.Verify)
+		v1.Post("/auth/mfa/disable", validateJWT, m.Require("mfa:manage"), dep.MFAController.Disable)
+		v1.Post("/auth/mfa/recovery-codes", validateJWT, m.Require("mfa:manage"), dep.MFAController.RegenerateRecoveryCodes)
+	}
+
+	// LOCATION SECTION
+	{
+		// public since registration needs location_id before having any token
+		v1.Get("/locations", dep.LocationController.ListLocation)
+		v1.Get("/locations/provinsi", dep.LocationController.ListProvinsi)
+		v1.Get("/locations/kota", dep.LocationController.ListKota)
+		v1.Get("/locations/:id", dep.LocationController.DetailLocation)
+		v1.Post("/locations", validateJWT, m.Require("location:manage"), dep.LocationController.CreateLocation)
+		v1.Post("/locations/import", validateJWT, m.Require("location:manage"), dep.LocationController.ImportLocation)
+		v1.Put("/locations/:id", validateJWT, m.Require("location:manage"), dep.LocationController.UpdateLocation)
+		v1.Delete("/locations/:id", validateJWT, m.Require("location:manage"), dep.LocationController.DeleteLocation)
 	}
 
 	// E-COMMERCE SECTION
 	{
 		// LAPAK SECTION
-		v1.Get("/lapak", m.ValidateJWTMiddleware, m.SuperAdminOnlyMiddleware, dep.LapakController.ListLapak)
-		v1.Get("/lapak/location", m.ValidateJWTMiddleware, m.CustomerOnlyMiddleware, dep.LapakController.ListLapakByLocation)
-		v1.Get("/lapak/:id", m.ValidateJWTMiddleware, dep.LapakController.DetailLapak)
-		v1.Put("/lapak/:id", m.ValidateJWTMiddleware, m.AdminSellerOnlyMiddleware, dep.LapakController.UpdateLapak)
-		v1.Put("/lapak/:id/status", m.ValidateJWTMiddleware, m.AdminSellerOnlyMiddleware, dep.LapakController.UpdateLapakByStatus)
-		v1.Delete("/lapak/:id", m.ValidateJWTMiddleware, m.SuperAdminOnlyMiddleware, dep.LapakController.DeleteLapak)
+		v1.Get("/lapak", validateJWT, m.Require("lapak:list"), dep.LapakController.ListLapak)
+		v1.Post("/lapak", validateJWT, m.Require("lapak:create"), dep.LapakController.CreateLapak)
+		v1.Get("/lapak/self", validateJWT, m.Require("lapak:create"), dep.LapakController.ListLapakSelf)
+		v1.Get("/lapak/location", validateJWT, m.Require("lapak:list:location"), dep.LapakController.ListLapakByLocation)
+		v1.Get("/lapak/nearby", validateJWT, m.Require("lapak:list:location"), dep.LapakController.ListNearbyLapak)
+		v1.Get("/lapak/:id", validateJWT, dep.LapakController.DetailLapak)
+		v1.Put("/lapak/:id", validateJWT, m.Require("lapak:update"), dep.LapakController.UpdateLapak)
+		v1.Put("/lapak/:id/status", validateJWT, m.Require("lapak:update"), dep.LapakController.UpdateLapakByStatus)
+		v1.Put("/lapak/:id/location", validateJWT, m.Require("lapak:update"), dep.LapakController.RelocateLapak)
+		v1.Get("/lapak/:id/location/history", validateJWT, m.Require("lapak:update"), dep.LapakController.ListLapakLocationHistory)
+		v1.Get("/lapak/:id/schedule", validateJWT, dep.ScheduleController.DetailLapakSchedule)
+		v1.Put("/lapak/:id/schedule", validateJWT, m.Require("lapak:update"), dep.ScheduleController.UpdateOpeningHours)
+		v1.Post("/lapak/:id/holidays", validateJWT, m.Require("lapak:update"), dep.ScheduleController.CreateHoliday)
+		v1.Delete("/lapak/:id/holidays/:date", validateJWT, m.Require("lapak:update"), dep.ScheduleController.DeleteHoliday)
+		v1.Delete("/lapak/:id", validateJWT, m.Require("lapak:delete"), dep.LapakController.DeleteLapak)
+		v1.Post("/lapak/:id/restore", validateJWT, m.Require("trash:manage"), dep.LapakController.RestoreLapak)
+		v1.Get("/lapak/:id/pemesanan", validateJWT, m.Require("order:list:lapak"), dep.PemesananController.ListPemesananByLapak)
+		v1.Put("/lapak/:id/pemesanan/:pemesanan_id", validateJWT, m.Require("order:update:lapak"), dep.PemesananController.UpdatePemesananByLapak)
 
 		// PRODUCT SECTION
-		v1.Post("lapak/:id/product/upload", m.ValidateJWTMiddleware, m.AdminSellerOnlyMiddleware, dep.ProductController.UploadIMG)
-		v1.Post("lapak/:id/product", m.ValidateJWTMiddleware, m.AdminSellerOnlyMiddleware, dep.ProductController.CreateProduct)
-		v1.Get("product/", m.ValidateJWTMiddleware, dep.ProductController.ListProduct)
-		v1.Get("product/:id/", m.ValidateJWTMiddleware, dep.ProductController.DetailProduct)
-		v1.Get("lapak/:lapak_id/product/", m.ValidateJWTMiddleware, dep.ProductController.ListProductByLapak)
+		v1.Post("lapak/:id/product/upload", validateJWT, m.Require("product:create"), dep.ProductController.UploadIMG)
+		v1.Post("lapak/:id/product", validateJWT, m.Require("product:create"), dep.ProductController.CreateProduct)
+		v1.Get("product/", validateJWT, dep.ProductController.ListProduct)
+		v1.Get("product/suggest", validateJWT, dep.ProductController.SuggestProduct)
+		v1.Get("product/:id/", validateJWT, dep.ProductController.DetailProduct)
+		v1.Put("product/:id", validateJWT, m.Require("product:update"), dep.ProductController.UpdateProduct)
+		v1.Patch("product/:id", validateJWT, m.Require("product:update"), dep.ProductController.PatchProduct)
+		v1.Delete("product/:id", validateJWT, m.Require("product:delete"), dep.ProductController.DeleteProduct)
+		v1.Post("product/:id/restore", validateJWT, m.Require("trash:manage"), dep.ProductController.RestoreProduct)
+		v1.Get("lapak/:lapak_id/product/", validateJWT, dep.ProductController.ListProductByLapak)
 
 		// PEMESANAN SECTION
-		v1.Post("pemesanan/:product_id", m.ValidateJWTMiddleware, m.CustomerOnlyMiddleware, dep.PemesananController.CreatePemesanan)
-		v1.Get("pemesanan/", m.ValidateJWTMiddleware, m.SuperAdminOnlyMiddleware, dep.PemesananController.ListPemesanan)
-		v1.Get("pemesanan/self", m.ValidateJWTMiddleware, m.CustomerSellerOnlyMiddleware, dep.PemesananController.ListPemesananPribadi)
-		v1.Get("pemesanan/:id", m.ValidateJWTMiddleware, dep.PemesananController.DetailPemesanan)
-		v1.Put("pemesanan/:id", m.ValidateJWTMiddleware, m.CustomerSellerOnlyMiddleware, dep.PemesananController.UpdatePemesanan)
-		v1.Delete("pemesanan/:id", m.ValidateJWTMiddleware, m.CustomerSellerOnlyMiddleware, dep.PemesananController.DeletePemesanan)
+		v1.Post("pemesanan/:product_id", validateJWT, m.Require("order:create"), dep.PemesananController.CreatePemesanan)
+		v1.Get("pemesanan/", validateJWT, m.Require("order:list"), dep.PemesananController.ListPemesanan)
+		v1.Get("pemesanan/self", validateJWT, m.Require("order:list:own"), dep.PemesananController.ListPemesananPribadi)
+		v1.Get("pemesanan/:id", validateJWT, dep.PemesananController.DetailPemesanan)
+		v1.Put("pemesanan/:id", validateJWT, m.Require("order:update:own"), dep.PemesananController.UpdatePemesanan)
+		v1.Delete("pemesanan/:id", validateJWT, m.Require("order:delete:own"), dep.PemesananController.DeletePemesanan)
+
+		// CART SECTION
+		v1.Get("cart", validateJWT, m.Require("order:create"), dep.CartController.GetCart)
+		v1.Post("cart", validateJWT, m.Require("order:create"), dep.CartController.AddCartItem)
+		v1.Post("cart/checkout", validateJWT, m.Require("order:create"), dep.CartController.Checkout)
+		v1.Put("cart/:product_id", validateJWT, m.Require("order:create"), dep.CartController.UpdateCartItem)
+		v1.Delete("cart/:product_id", validateJWT, m.Require("order:create"), dep.CartController.DeleteCartItem)
 	}
 
 	// USER SECTION
 	{
-		v1.Get("/users", m.ValidateJWTMiddleware, m.SuperAdminOnlyMiddleware, dep.UserController.ListUser)
-		v1.Get("/users/:id", m.ValidateJWTMiddleware, m.SuperAdminOnlyMiddleware, dep.UserController.DetailUser)
-		v1.Get("/users/:id/profile", m.ValidateJWTMiddleware, dep.UserController.DetailUserProfile)
-		v1.Put("/users/:id", m.ValidateJWTMiddleware, dep.UserController.UpdateUser)
-		v1.Put("/users/:id/profile", m.ValidateJWTMiddleware, dep.UserController.UpdateUserProfile)
-		v1.Delete("/users/:id", m.ValidateJWTMiddleware, m.SuperAdminOnlyMiddleware, dep.UserController.DeleteUser)
+		v1.Get("/users", validateJWT, m.Require("user:list"), dep.UserController.ListUser)
+		v1.Get("/users/:id", validateJWT, m.Require("user:read"), dep.UserController.DetailUser)
+		v1.Get("/users/:id/profile", validateJWT, dep.UserController.DetailUserProfile)
+		v1.Get("/users/:id/lapak", validateJWT, m.Require("user:read"), dep.LapakController.ListLapakByUser)
+		v1.Put("/users/:id", validateJWT, dep.UserController.UpdateUser)
+		v1.Put("/users/:id/profile", validateJWT, dep.UserController.UpdateUserProfile)
+		v1.Delete("/users/:id", validateJWT, m.Require("user:delete"), dep.UserController.DeleteUser)
+		v1.Post("/users/:id/restore", validateJWT, m.Require("trash:manage"), dep.UserController.RestoreUser)
+	}
+
+	// BLACKLIST SECTION
+	{
+		v1.Get("/blacklist", validateJWT, m.Require("blacklist:manage"), dep.BlacklistController.ListBlacklist)
+		v1.Post("/users/:id/blacklist", validateJWT, m.Require("blacklist:manage"), dep.BlacklistController.BlacklistUser)
+		v1.Delete("/users/:id/blacklist", validateJWT, m.Require("blacklist:manage"), dep.BlacklistController.UnblacklistUser)
+	}
+
+	// ROLE SECTION
+	{
+		v1.Get("/roles", validateJWT, m.Require("role:manage"), dep.RoleController.ListRole)
+		v1.Post("/roles", validateJWT, m.Require("role:manage"), dep.RoleController.CreateRole)
+		v1.Delete("/roles/:id", validateJWT, m.Require("role:manage"), dep.RoleController.DeleteRole)
+		v1.Put("/roles/:id/mfa", validateJWT, m.Require("role:manage"), dep.RoleController.UpdateRoleMFA)
+		v1.Put("/roles/:id/lapak-limit", validateJWT, m.Require("role:manage"), dep.RoleController.UpdateRoleLapakLimit)
+		v1.Put("/roles/:id/permissions", validateJWT, m.Require("role:manage"), dep.RoleController.UpdateRolePermissions)
+		v1.Put("/users/:id/role", validateJWT, m.Require("role:manage"), dep.RoleController.AssignUserRole)
+		v1.Get("/permissions", validateJWT, m.Require("role:manage"), dep.RoleController.ListPermission)
+		v1.Post("/permissions", validateJWT, m.Require("role:manage"), dep.RoleController.CreatePermission)
 	}
 
 	// handler for route not found
-	app.Application.Use(func(c *fiber.Ctx) error {
+	router.Use(func(c *fiber.Ctx) error {
 		return helper.ResponseFormatter[any](c, fiber.StatusNotFound, nil, "Route not found", nil)
 	})
 
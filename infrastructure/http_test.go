@@ -0,0 +1,872 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/application"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/controller"
+	"github.com/wiormiw/GrowBaks/middleware"
+	"github.com/wiormiw/GrowBaks/model"
+	"github.com/wiormiw/GrowBaks/repository"
+	"github.com/wiormiw/GrowBaks/service"
+	"github.com/wiormiw/GrowBaks/util"
+)
+
+const (
+	// routeTestSecret signs every token of route test, util.ValidateJWT reads it from JWT_SECRET
+	routeTestSecret = "route-test-secret"
+	// routeTOTPSecret is TOTP secret of every user inside route test
+	routeTOTPSecret = "JBSWY3DPEHPK3PXP"
+
+	// routeRoleMember is granted every route permission but none of ownership bypass permission
+	routeRoleMember = "Member"
+	// routeRoleWithout prefixes role granted every permission except the one following it
+	routeRoleWithout = "without:"
+)
+
+var (
+	routeOwnerID  = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	routeOtherID  = uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	routeAdminID  = uuid.MustParse("00000000-0000-0000-0000-000000000003")
+	routeBannedID = uuid.MustParse("00000000-0000-0000-0000-000000000004")
+
+	// lapak, product & both pemesanan belong to owner, owner is also buyer of both pemesanan
+	routeLapakID       = uuid.MustParse("00000000-0000-0000-0001-000000000001")
+	routeProductID     = uuid.MustParse("00000000-0000-0000-0002-000000000001")
+	routePendingID     = uuid.MustParse("00000000-0000-0000-0003-000000000001")
+	routeCancelledID   = uuid.MustParse("00000000-0000-0000-0003-000000000002")
+	routeLocationID    = uuid.MustParse("00000000-0000-0000-0004-000000000001")
+	routeNewLocationID = uuid.MustParse("00000000-0000-0000-0004-000000000002")
+	routeRoleID        = uuid.MustParse("00000000-0000-0000-0005-000000000001")
+
+	// routeUserEmails is email already registered by user inside route test
+	routeUserEmails = map[uuid.UUID]string{
+		routeOwnerID: "pemilik@growbaks.test",
+		routeOtherID: "lain@growbaks.test",
+	}
+
+	// routeBypassPermissions is ownership bypass permission, only admin is granted it inside route test
+	routeBypassPermissions = []string{
+		model.PermissionLapakManageAny,
+		model.PermissionProductManageAny,
+		model.PermissionOrderManageAny,
+		model.PermissionUserManageAny,
+		model.PermissionUserRead,
+	}
+)
+
+type (
+	// routeCase is a registered route along with request reaching its handler successfully
+	routeCase struct {
+		method string
+		route  string
+		// path is requested instead of route when its params can not be filled by id
+		path string
+		id   string
+		// permission is required by m.Require, empty when a valid token is enough
+		permission string
+		public     bool
+		owned      bool
+		body       string
+		// form names multipart file field holding body, body is sent as json when empty
+		form       string
+		mfaEnabled bool
+		// reachOnly route calls external service, so it is only checked to be authorized
+		reachOnly bool
+	}
+
+	routeLapakRepo struct {
+		repository.ILapakRepository
+	}
+
+	routeUserRepo struct {
+		repository.IUserRepository
+	}
+
+	routeProductRepo struct {
+		repository.IProductRepository
+	}
+
+	routePemesananRepo struct {
+		repository.IPemesananRepository
+	}
+
+	routeCartRepo struct {
+		repository.ICartRepository
+	}
+
+	routeScheduleRepo struct {
+		repository.IScheduleRepository
+	}
+
+	routeLocationRepo struct {
+		repository.ILocationRepository
+	}
+
+	routeRoleRepo struct {
+		repository.IRoleRepository
+	}
+
+	routeBlacklistRepo struct {
+		repository.IBlacklistRepository
+	}
+
+	routeHealthRepo struct {
+		repository.IHealthCheckRepository
+	}
+
+	// routeAuthRepo is only reached by public route, every call panics & is recovered into 500
+	routeAuthRepo struct {
+		repository.IAuthRepository
+	}
+
+	routePermissionRepo struct {
+		repository.IPermissionRepository
+		routePermissions []string
+	}
+
+	routeMFARepo struct {
+		repository.IMFARepository
+		enabled bool
+	}
+)
+
+func TestRoutesEnforceAuthorization(t *testing.T) {
+	t.Setenv("JWT_SECRET", routeTestSecret)
+
+	cases := routeCases()
+
+	// a route added without a case here fails, so its authorization is tested too
+	covered := map[string]bool{}
+	for _, rc := range cases {
+		covered[rc.method+" "+rc.route] = true
+	}
+
+	for _, route := range newRouteTestApp(cases, false).GetRoutes(true) {
+		if route.Method == fiber.MethodHead {
+			continue
+		}
+
+		if !covered[route.Method+" "+route.Path] {
+			t.Errorf("%s %s has no route case", route.Method, route.Path)
+		}
+	}
+
+	for _, rc := range cases {
+		rc := rc
+
+		t.Run(rc.method+" "+rc.route, func(t *testing.T) {
+			if rc.public {
+				status, body := rc.do(t, cases, "")
+				if strings.Contains(body, model.ErrTokenNotFound.Error()) {
+					t.Errorf("public route rejected request without token, status %d", status)
+				}
+
+				return
+			}
+
+			rc.expect(t, cases, "without token", "", fiber.StatusUnauthorized)
+
+			if rc.permission != "" {
+				rc.expect(t, cases, "role lacking "+rc.permission, routeToken(t, routeOwnerID, routeRoleWithout+rc.permission), fiber.StatusForbidden)
+			}
+
+			if rc.owned {
+				rc.expect(t, cases, "other owner", routeToken(t, routeOtherID, routeRoleMember), fiber.StatusForbidden)
+				rc.expectSuccess(t, cases, "owner", routeToken(t, routeOwnerID, routeRoleMember))
+			}
+
+			rc.expectSuccess(t, cases, "admin", routeToken(t, routeAdminID, model.RoleAdmin))
+		})
+	}
+}
+
+func TestUpdateUserKeepsOwnEmail(t *testing.T) {
+	t.Setenv("JWT_SECRET", routeTestSecret)
+
+	var (
+		cases   = routeCases()
+		owner   = routeOwnerID.String()
+		account = `{"full_name":"Pemilik Lapak","email":"%s","password":"rahasia123"}`
+		profile = `{"full_name":"Pemilik Lapak","tanggal_lahir":"2000-01-01","gender":"perempuan","email":"%s","telepon":"081234567890","location_id":"` + routeNewLocationID.String() + `"}`
+		token   = routeToken(t, routeOwnerID, routeRoleMember)
+	)
+
+	for _, update := range []struct {
+		name  string
+		route string
+		body  string
+	}{
+		{"account", "/v1/users/:id", account},
+		{"profile location only", "/v1/users/:id/profile", profile},
+	} {
+		t.Run(update.name, func(t *testing.T) {
+			rc := routeCase{method: "PUT", route: update.route, id: owner, body: fmt.Sprintf(update.body, routeUserEmails[routeOwnerID])}
+			rc.expectSuccess(t, cases, "own email", token)
+
+			rc.body = fmt.Sprintf(update.body, routeUserEmails[routeOtherID])
+			rc.expect(t, cases, "email of other user", token, fiber.StatusBadRequest)
+		})
+	}
+}
+
+// routeCases responsible to listing every route registered by registerRoutes
+func routeCases() []routeCase {
+	var (
+		lapak     = routeLapakID.String()
+		product   = routeProductID.String()
+		pending   = routePendingID.String()
+		cancelled = routeCancelledID.String()
+		location  = routeLocationID.String()
+		role      = routeRoleID.String()
+		owner     = routeOwnerID.String()
+		other     = routeOtherID.String()
+		banned    = routeBannedID.String()
+
+		productBody = `{"product_name":"Nasi Uduk","stok":10,"price":15000,"product_kategori":"makanan","product_img":"https://img.growbaks.test/nasi.png"}`
+	)
+
+	return []routeCase{
+		{method: "OPTIONS", route: "/v1/*", path: "/v1/lapak", public: true},
+		{method: "GET", route: "/v1/health", public: true},
+
+		{method: "POST", route: "/v1/auth/register", public: true},
+		{method: "POST", route: "/v1/auth/login", public: true},
+		{method: "POST", route: "/v1/auth/login/mfa", public: true},
+		{method: "POST", route: "/v1/auth/refresh", public: true},
+		{method: "POST", route: "/v1/auth/logout"},
+		{method: "POST", route: "/v1/auth/password/forgot", public: true},
+		{method: "POST", route: "/v1/auth/password/reset", public: true},
+		{method: "GET", route: "/v1/auth/verify", public: true},
+		{method: "POST", route: "/v1/auth/verify/resend", public: true},
+		{method: "GET", route: "/v1/auth/google/login", public: true},
+		{method: "GET", route: "/v1/auth/google/callback", public: true},
+		{method: "POST", route: "/v1/auth/mfa/enroll", permission: "mfa:manage"},
+		{method: "POST", route: "/v1/auth/mfa/verify", permission: "mfa:manage", body: `{"code":"` + routeTOTPCode(routeTOTPSecret) + `"}`},
+		{method: "POST", route: "/v1/auth/mfa/disable", permission: "mfa:manage", mfaEnabled: true, body: `{"code":"recovery-code"}`},
+		{method: "POST", route: "/v1/auth/mfa/recovery-codes", permission: "mfa:manage", mfaEnabled: true, body: `{"code":"recovery-code"}`},
+
+		{method: "GET", route: "/v1/locations", public: true},
+		{method: "GET", route: "/v1/locations/provinsi", public: true},
+		{method: "GET", route: "/v1/locations/kota", public: true},
+		{method: "GET", route: "/v1/locations/:id", id: location, public: true},
+		{method: "POST", route: "/v1/locations", permission: "location:manage", body: `{"provinsi":"Jawa Barat","kota":"Bandung","daerah":"Coblong"}`},
+		{method: "POST", route: "/v1/locations/import", permission: "location:manage", form: "file", body: "provinsi,kota,daerah\nJawa Barat,Bandung,Coblong\n"},
+		{method: "PUT", route: "/v1/locations/:id", id: location, permission: "location:manage", body: `{"provinsi":"Jawa Barat","kota":"Bandung","daerah":"Dago"}`},
+		{method: "DELETE", route: "/v1/locations/:id", id: location, permission: "location:manage"},
+
+		{method: "GET", route: "/v1/lapak", permission: "lapak:list"},
+		{method: "POST", route: "/v1/lapak", permission: "lapak:create", body: `{"name":"Lapak Baru","location_id":"` + location + `"}`},
+		{method: "GET", route: "/v1/lapak/self", permission: "lapak:create"},
+		{method: "GET", route: "/v1/lapak/location", permission: "lapak:list:location"},
+		{method: "GET", route: "/v1/lapak/nearby", path: "/v1/lapak/nearby?lat=-6.9&lng=107.6", permission: "lapak:list:location"},
+		{method: "GET", route: "/v1/lapak/:id", id: lapak},
+		{method: "PUT", route: "/v1/lapak/:id", id: lapak, permission: "lapak:update", owned: true, body: `{"name":"Lapak Ganti Nama"}`},
+		{method: "PUT", route: "/v1/lapak/:id/status", id: lapak, permission: "lapak:update", owned: true, body: `{"status":"closed"}`},
+		{method: "PUT", route: "/v1/lapak/:id/location", id: lapak, permission: "lapak:update", owned: true, body: `{"location_id":"` + routeNewLocationID.String() + `"}`},
+		{method: "GET", route: "/v1/lapak/:id/location/history", id: lapak, permission: "lapak:update", owned: true},
+		{method: "GET", route: "/v1/lapak/:id/schedule", id: lapak},
+		{method: "PUT", route: "/v1/lapak/:id/schedule", id: lapak, permission: "lapak:update", owned: true, body: `{"timezone":"Asia/Jakarta","hours":[{"day_of_week":1,"open_time":"08:00","close_time":"17:00"}]}`},
+		{method: "POST", route: "/v1/lapak/:id/holidays", id: lapak, permission: "lapak:update", owned: true, body: `{"date":"` + time.Now().AddDate(0, 0, 7).Format("2006-01-02") + `"}`},
+		{method: "DELETE", route: "/v1/lapak/:id/holidays/:date", id: lapak, permission: "lapak:update", owned: true},
+		{method: "DELETE", route: "/v1/lapak/:id", id: lapak, permission: "lapak:delete", owned: true},
+		{method: "POST", route: "/v1/lapak/:id/restore", id: lapak, permission: "trash:manage"},
+		{method: "GET", route: "/v1/lapak/:id/pemesanan", id: lapak, permission: "order:list:lapak", owned: true},
+		{method: "PUT", route: "/v1/lapak/:id/pemesanan/:pemesanan_id", id: lapak, permission: "order:update:lapak", owned: true, body: `{"status":"done"}`},
+
+		{method: "POST", route: "/v1/lapak/:id/product/upload", id: lapak, permission: "product:create", reachOnly: true},
+		{method: "POST", route: "/v1/lapak/:id/product", id: lapak, permission: "product:create", owned: true, body: productBody},
+		{method: "GET", route: "/v1/product/"},
+		{method: "GET", route: "/v1/product/suggest", path: "/v1/product/suggest?q=nasi"},
+		{method: "GET", route: "/v1/product/:id/", id: product},
+		{method: "PUT", route: "/v1/product/:id", id: product, permission: "product:update", owned: true, body: productBody},
+		{method: "PATCH", route: "/v1/product/:id", id: product, permission: "product:update", owned: true, body: `{"price":20000}`},
+		{method: "DELETE", route: "/v1/product/:id", id: product, permission: "product:delete", owned: true},
+		{method: "POST", route: "/v1/product/:id/restore", id: product, permission: "trash:manage"},
+		{method: "GET", route: "/v1/lapak/:lapak_id/product/"},
+
+		{method: "POST", route: "/v1/pemesanan/:product_id", permission: "order:create", body: `{"qty":1}`},
+		{method: "GET", route: "/v1/pemesanan/", permission: "order:list"},
+		{method: "GET", route: "/v1/pemesanan/self", permission: "order:list:own"},
+		{method: "GET", route: "/v1/pemesanan/:id", id: pending, owned: true},
+		{method: "PUT", route: "/v1/pemesanan/:id", id: pending, permission: "order:update:own", owned: true, body: `{"status":"cancel"}`},
+		{method: "DELETE", route: "/v1/pemesanan/:id", id: cancelled, permission: "order:delete:own", owned: true},
+
+		{method: "GET", route: "/v1/cart", permission: "order:create"},
+		{method: "POST", route: "/v1/cart", permission: "order:create", body: `{"product_id":"` + product + `","qty":1}`},
+		{method: "POST", route: "/v1/cart/checkout", permission: "order:create"},
+		{method: "PUT", route: "/v1/cart/:product_id", permission: "order:create", body: `{"qty":2}`},
+		{method: "DELETE", route: "/v1/cart/:product_id", permission: "order:create"},
+
+		{method: "GET", route: "/v1/users", permission: "user:list"},
+		{method: "GET", route: "/v1/users/:id", id: other, permission: "user:read"},
+		{method: "GET", route: "/v1/users/:id/profile", id: owner, owned: true},
+		{method: "GET", route: "/v1/users/:id/lapak", id: other, permission: "user:read"},
+		{method: "PUT", route: "/v1/users/:id", id: owner, owned: true, body: `{"full_name":"Pemilik Lapak","email":"pemilik@growbaks.test","password":"rahasia123"}`},
+		{method: "PUT", route: "/v1/users/:id/profile", id: owner, owned: true, body: `{"full_name":"Pemilik Lapak","tanggal_lahir":"2000-01-01","gender":"perempuan","email":"pemilik@growbaks.test","telepon":"081234567890"}`},
+		{method: "DELETE", route: "/v1/users/:id", id: other, permission: "user:delete"},
+		{method: "POST", route: "/v1/users/:id/restore", id: other, permission: "trash:manage"},
+
+		{method: "GET", route: "/v1/blacklist", permission: "blacklist:manage"},
+		{method: "POST", route: "/v1/users/:id/blacklist", id: other, permission: "blacklist:manage", body: `{"reason":"Penipuan berulang"}`},
+		{method: "DELETE", route: "/v1/users/:id/blacklist", id: banned, permission: "blacklist:manage"},
+
+		{method: "GET", route: "/v1/roles", permission: "role:manage"},
+		{method: "POST", route: "/v1/roles", permission: "role:manage", body: `{"name":"Kurir"}`},
+		{method: "DELETE", route: "/v1/roles/:id", id: role, permission: "role:manage"},
+		{method: "PUT", route: "/v1/roles/:id/mfa", id: role, permission: "role:manage", body: `{"require_mfa":true}`},
+		{method: "PUT", route: "/v1/roles/:id/lapak-limit", id: role, permission: "role:manage", body: `{"max_lapak":3}`},
+		{method: "PUT", route: "/v1/roles/:id/permissions", id: role, permission: "role:manage", body: `{"permissions":["lapak:list"]}`},
+		{method: "PUT", route: "/v1/users/:id/role", id: other, permission: "role:manage", body: `{"role_id":"` + role + `"}`},
+		{method: "GET", route: "/v1/permissions", permission: "role:manage"},
+		{method: "POST", route: "/v1/permissions", permission: "role:manage", body: `{"name":"report:view"}`},
+	}
+}
+
+// expect responsible to checking route case responds with status for given token
+func (rc routeCase) expect(t *testing.T, cases []routeCase, name string, token string, status int) {
+	t.Helper()
+
+	got, body := rc.do(t, cases, token)
+	if got != status {
+		t.Errorf("%s: status %d, want %d, body %s", name, got, status, body)
+	}
+}
+
+// expectSuccess responsible to checking route case reaches its handler successfully for given token
+func (rc routeCase) expectSuccess(t *testing.T, cases []routeCase, name string, token string) {
+	t.Helper()
+
+	got, body := rc.do(t, cases, token)
+	if rc.reachOnly {
+		if got == fiber.StatusUnauthorized || got == fiber.StatusForbidden {
+			t.Errorf("%s: status %d, want authorized, body %s", name, got, body)
+		}
+
+		return
+	}
+
+	if got < 200 || got >= 300 {
+		t.Errorf("%s: status %d, want 2xx, body %s", name, got, body)
+	}
+}
+
+// do responsible to sending route case request with optional bearer token into a fresh app
+func (rc routeCase) do(t *testing.T, cases []routeCase, token string) (int, string) {
+	t.Helper()
+
+	path := rc.path
+	if path == "" {
+		path = strings.NewReplacer(
+			":id", rc.id,
+			":lapak_id", routeLapakID.String(),
+			":product_id", routeProductID.String(),
+			":pemesanan_id", routePendingID.String(),
+			":date", time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		).Replace(rc.route)
+	}
+
+	var (
+		body        io.Reader
+		contentType string
+	)
+
+	switch {
+	case rc.form != "":
+		buf := &bytes.Buffer{}
+		writer := multipart.NewWriter(buf)
+
+		part, err := writer.CreateFormFile(rc.form, "upload.csv")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := part.Write([]byte(rc.body)); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		body, contentType = buf, writer.FormDataContentType()
+	case rc.body != "":
+		body, contentType = strings.NewReader(rc.body), fiber.MIMEApplicationJSON
+	}
+
+	req := httptest.NewRequest(rc.method, path, body)
+	if contentType != "" {
+		req.Header.Set(fiber.HeaderContentType, contentType)
+	}
+
+	if token != "" {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	}
+
+	resp, err := newRouteTestApp(cases, rc.mfaEnabled).Test(req, -1)
+	if err != nil {
+		t.Fatalf("request %s %s: %v", rc.method, path, err)
+	}
+
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resp.StatusCode, string(respBody)
+}
+
+// routeToken responsible to signing access token of user with given role
+func routeToken(t *testing.T, userID uuid.UUID, roleName string) string {
+	t.Helper()
+
+	token, err := util.BuildJWT(routeTestConfig(), &model.AuthUserDetails{
+		UserID:   userID,
+		FullName: "Route Test",
+		Email:    "route@growbaks.test",
+		RoleName: roleName,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+// routeTOTPCode responsible to generating current RFC 6238 code of a base32 secret, the way authenticator app does
+func routeTOTPCode(secret string) string {
+	key, _ := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(time.Now().Unix()/30))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%06d", value%1000000)
+}
+
+// routeTestConfig responsible to returning configuration shared by route test
+func routeTestConfig() *config.Configuration {
+	return &config.Configuration{
+		Const:  &config.Constants{},
+		Secret: &config.Secret{JWTSecret: routeTestSecret},
+		OAuth:  &config.OAuth{},
+	}
+}
+
+// newRouteTestApp responsible to building every route over real controller, service & middleware backed by stubbed repository,
+// unstubbed repository call panics & is recovered into 500
+func newRouteTestApp(cases []routeCase, mfaEnabled bool) *fiber.App {
+	var (
+		ctx    = context.Background()
+		cfg    = routeTestConfig()
+		logger = logrus.New()
+	)
+
+	logger.SetOutput(io.Discard)
+
+	permissions := []string{}
+	for _, rc := range cases {
+		if rc.permission != "" {
+			permissions = append(permissions, rc.permission)
+		}
+	}
+
+	var (
+		lapakRepo      = routeLapakRepo{}
+		userRepo       = routeUserRepo{}
+		productRepo    = routeProductRepo{}
+		pemesananRepo  = routePemesananRepo{}
+		scheduleRepo   = routeScheduleRepo{}
+		locationRepo   = routeLocationRepo{}
+		roleRepo       = routeRoleRepo{}
+		blacklistRepo  = routeBlacklistRepo{}
+		permissionRepo = routePermissionRepo{routePermissions: permissions}
+		mailer         = &util.FileMailer{Logger: logger}
+	)
+
+	roleSvc := &service.RoleService{Context: ctx, Config: cfg, Logger: logger, RoleRepo: roleRepo, PermissionRepo: permissionRepo}
+	blacklistSvc := &service.BlacklistService{Context: ctx, Config: cfg, Logger: logger, BlacklistRepo: blacklistRepo, UserRepo: userRepo}
+
+	dep := &application.Dependency{
+		HealthCheckController: &controller.HealthCheckController{Context: ctx, Config: cfg, Logger: logger,
+			HealthCheckSvc: &service.HealthCheckService{Context: ctx, Config: cfg, Logger: logger, HealthCheckRepo: routeHealthRepo{}}},
+		AuthController: &controller.AuthController{Context: ctx, Config: cfg, Logger: logger,
+			AuthSvc: &service.AuthService{Context: ctx, Config: cfg, Logger: logger, AuthRepo: routeAuthRepo{}, RoleRepo: roleRepo,
+				LocationRepo: locationRepo, LapakRepo: lapakRepo, BlacklistRepo: blacklistRepo, MFARepo: routeMFARepo{enabled: mfaEnabled},
+				Mailer: mailer, GoogleOAuth: &util.OAuthProvider{HTTPClient: &http.Client{}}}},
+		LapakController: &controller.LapakController{Context: ctx, Config: cfg, Logger: logger,
+			LapakSvc: &service.LapakService{Context: ctx, Config: cfg, Logger: logger, LapakRepo: lapakRepo, UserRepo: userRepo,
+				BlacklistRepo: blacklistRepo, LocationRepo: locationRepo, RoleRepo: roleRepo, Mailer: mailer}},
+		UserController: &controller.UserController{Context: ctx, Config: cfg, Logger: logger,
+			UserSvc: &service.UserService{Context: ctx, Config: cfg, Logger: logger, UserRepo: userRepo, LocationRepo: locationRepo,
+				AuthRepo: routeAuthRepo{}, Mailer: mailer}},
+		ProductController: &controller.ProductController{Context: ctx, Config: cfg, Logger: logger,
+			ProductSvc: &service.ProductService{Context: ctx, Config: cfg, Logger: logger, ProductRepo: productRepo, UserRepo: userRepo,
+				LapakRepo: lapakRepo, ScheduleRepo: scheduleRepo}},
+		PemesananController: &controller.PemesananController{Context: ctx, Config: cfg, Logger: logger,
+			PemesananSvc: &service.PemesananService{Context: ctx, Config: cfg, Logger: logger, PemesananRepo: pemesananRepo, UserRepo: userRepo,
+				ProductRepo: productRepo, LapakRepo: lapakRepo}},
+		CartController: &controller.CartController{Context: ctx, Config: cfg, Logger: logger,
+			CartSvc: &service.CartService{Context: ctx, Config: cfg, Logger: logger, CartRepo: routeCartRepo{}, ProductRepo: productRepo,
+				PemesananRepo: pemesananRepo, UserRepo: userRepo}},
+		BlacklistController: &controller.BlacklistController{Context: ctx, Config: cfg, Logger: logger, BlacklistSvc: blacklistSvc},
+		MFAController: &controller.MFAController{Context: ctx, Config: cfg, Logger: logger,
+			MFASvc: &service.MFAService{Context: ctx, Config: cfg, Logger: logger, MFARepo: routeMFARepo{enabled: mfaEnabled}}},
+		RoleController: &controller.RoleController{Context: ctx, Config: cfg, Logger: logger, RoleSvc: roleSvc},
+		LocationController: &controller.LocationController{Context: ctx, Config: cfg, Logger: logger,
+			LocationSvc: &service.LocationService{Context: ctx, Config: cfg, Logger: logger, LocationRepo: locationRepo}},
+		ScheduleController: &controller.ScheduleController{Context: ctx, Config: cfg, Logger: logger,
+			ScheduleSvc: &service.ScheduleService{Context: ctx, Config: cfg, Logger: logger, ScheduleRepo: scheduleRepo, LapakRepo: lapakRepo}},
+		JWTMiddleware: &middleware.JWTMiddleware{Context: ctx, Config: cfg, Logger: logger, BlacklistSvc: blacklistSvc, RoleSvc: roleSvc},
+	}
+
+	router := fiber.New()
+	router.Use(recover.New())
+	registerRoutes(router, dep)
+
+	return router
+}
+
+func (routeLapakRepo) GetAllLapak(string, bool, model.PageRequest) ([]model.Lapak, int, error) {
+	return []model.Lapak{}, 0, nil
+}
+
+func (routeLapakRepo) GetAllLapakByLocation(string, string, bool, model.PageRequest) ([]model.Lapak, int, error) {
+	return []model.Lapak{}, 0, nil
+}
+
+func (routeLapakRepo) GetNearbyLapak(model.GeoRadius, model.PageRequest) ([]model.Lapak, int, error) {
+	return []model.Lapak{}, 0, nil
+}
+
+func (routeLapakRepo) GetLapakByUser(uuid.UUID) ([]model.Lapak, error) {
+	return []model.Lapak{}, nil
+}
+
+func (routeLapakRepo) GetLapakByID(id uuid.UUID) (*model.Lapak, error) {
+	if id != routeLapakID {
+		return nil, pgx.ErrNoRows
+	}
+
+	return &model.Lapak{LapakID: id, UserID: routeOwnerID, LapakName: "Lapak Pemilik", Status: "closed", LocationID: routeLocationID}, nil
+}
+
+func (routeLapakRepo) CreateLapak(uuid.UUID, model.LapakCreateRequest, uuid.UUID, *int) (uuid.UUID, error) {
+	return routeLapakID, nil
+}
+
+func (routeLapakRepo) GetProductCounts(uuid.UUID) (int, int, error) { return 0, 0, nil }
+
+func (routeLapakRepo) UpdateByID(uuid.UUID, map[string]interface{}) error { return nil }
+
+func (routeLapakRepo) UpdateStatusByID(uuid.UUID, string) error { return nil }
+
+func (routeLapakRepo) DeleteByID(uuid.UUID) error { return nil }
+
+func (routeLapakRepo) RestoreByID(uuid.UUID) error { return nil }
+
+func (routeLapakRepo) RelocateByID(uuid.UUID, uuid.UUID, *float64, *float64, uuid.UUID, string) error {
+	return nil
+}
+
+func (routeLapakRepo) GetLocationHistory(uuid.UUID) ([]model.LapakLocationHistory, error) {
+	return []model.LapakLocationHistory{}, nil
+}
+
+func (routeLapakRepo) GetOpenOrderBuyers(uuid.UUID) ([]model.LapakBuyer, error) { return nil, nil }
+
+func (routeUserRepo) GetAll(string, bool, model.PageRequest) ([]model.ViewUserResponse, int, error) {
+	return []model.ViewUserResponse{}, 0, nil
+}
+
+func (routeUserRepo) GetByID(id uuid.UUID) (*model.ViewUserResponse, error) {
+	verifiedAt := time.Now()
+	return &model.ViewUserResponse{ID: id, FullName: "Route Test", Email: routeUserEmails[id], EmailVerifiedAt: &verifiedAt}, nil
+}
+
+func (routeUserRepo) GetByEmail(email string) (*model.ViewUserResponse, error) {
+	for id, userEmail := range routeUserEmails {
+		if userEmail == email {
+			return &model.ViewUserResponse{ID: id, FullName: "Route Test", Email: email}, nil
+		}
+	}
+
+	return nil, pgx.ErrNoRows
+}
+
+func (routeUserRepo) GetProfileByID(id uuid.UUID) (*model.ViewUserProfileResponse, error) {
+	return &model.ViewUserProfileResponse{UserID: id, LocationID: routeLocationID, Daerah: "Coblong"}, nil
+}
+
+func (routeUserRepo) UpdateByID(uuid.UUID, string, string, string) error { return nil }
+
+func (routeUserRepo) UpdateProfileByID(uuid.UUID, string, string, string, string, string, *uuid.UUID, *float64, *float64) error {
+	return nil
+}
+
+func (routeUserRepo) DeleteByID(uuid.UUID) error { return nil }
+
+func (routeUserRepo) RestoreByID(uuid.UUID) error { return nil }
+
+func (routeProductRepo) Create(model.CreateProductRequest) error { return nil }
+
+func (routeProductRepo) GetAllProduct(model.ProductFilter, model.PageRequest) ([]model.GetAllProductRequest, int, error) {
+	return []model.GetAllProductRequest{}, 0, nil
+}
+
+func (routeProductRepo) GetProductFacets(model.ProductFilter) (map[string][]model.FacetCount, error) {
+	return map[string][]model.FacetCount{}, nil
+}
+
+func (routeProductRepo) SuggestProduct(string, int) ([]string, error) { return []string{}, nil }
+
+func (routeProductRepo) GetAllProductByLapak(uuid.UUID, bool) ([]model.GetAllProductRequest, error) {
+	return []model.GetAllProductRequest{}, nil
+}
+
+func (routeProductRepo) GetProductByID(id uuid.UUID) (*model.GetAllProductRequest, error) {
+	if id != routeProductID {
+		return nil, pgx.ErrNoRows
+	}
+
+	return &model.GetAllProductRequest{ID: id, ProductName: "Nasi Uduk", Stock: 10, Price: 15000, LapakID: routeLapakID}, nil
+}
+
+func (routeProductRepo) UpdateByID(uuid.UUID, map[string]interface{}) error { return nil }
+
+func (routeProductRepo) DeleteByID(uuid.UUID) error { return nil }
+
+func (routeProductRepo) RestoreByID(uuid.UUID) error { return nil }
+
+func (routePemesananRepo) CreatePemesanan(uuid.UUID, uuid.UUID, model.CreatePemesananRequest, *model.GetAllProductRequest) error {
+	return nil
+}
+
+func (routePemesananRepo) GetAllPemesanan(model.PageRequest) ([]model.PemesananResponse, int, error) {
+	return []model.PemesananResponse{}, 0, nil
+}
+
+func (routePemesananRepo) GetAllPemesananPribadi(uuid.UUID) ([]model.PemesananResponse, error) {
+	return []model.PemesananResponse{}, nil
+}
+
+func (routePemesananRepo) GetAllPemesananByLapak(uuid.UUID, model.PemesananLapakFilter) ([]model.PemesananResponse, error) {
+	return []model.PemesananResponse{}, nil
+}
+
+func (routePemesananRepo) GetPemesananByID(id uuid.UUID) (*model.PemesananResponse, error) {
+	status := map[uuid.UUID]string{routePendingID: model.PemesananStatusPending, routeCancelledID: model.PemesananStatusCancel}[id]
+	if status == "" {
+		return nil, pgx.ErrNoRows
+	}
+
+	return &model.PemesananResponse{ID: id, Status: status, UserID: routeOwnerID.String(), LapakID: routeLapakID.String(), QTY: 1}, nil
+}
+
+func (routePemesananRepo) GetPemesananItems(uuid.UUID) ([]model.PemesananItem, error) {
+	return []model.PemesananItem{}, nil
+}
+
+func (routePemesananRepo) GetPemesananStatusHistory(uuid.UUID) ([]model.PemesananStatusHistory, error) {
+	return []model.PemesananStatusHistory{}, nil
+}
+
+func (routePemesananRepo) UpdatePemesananStatus(*model.PemesananResponse, string, uuid.UUID, string) error {
+	return nil
+}
+
+func (routePemesananRepo) DeleteByID(*model.PemesananResponse) error { return nil }
+
+func (routePemesananRepo) CheckoutCart(uuid.UUID) ([]uuid.UUID, error) {
+	return []uuid.UUID{routePendingID}, nil
+}
+
+func (routeCartRepo) GetCartByUserID(uuid.UUID) ([]model.CartItem, error) {
+	return []model.CartItem{}, nil
+}
+
+func (routeCartRepo) GetCartItem(_ uuid.UUID, productID uuid.UUID) (*model.CartItem, error) {
+	return &model.CartItem{ProductID: productID, Stock: 10, QTY: 1}, nil
+}
+
+func (routeCartRepo) AddCartItem(uuid.UUID, uuid.UUID, int) error { return nil }
+
+func (routeCartRepo) UpdateCartItem(uuid.UUID, uuid.UUID, int) error { return nil }
+
+func (routeCartRepo) DeleteCartItem(uuid.UUID, uuid.UUID) error { return nil }
+
+func (routeScheduleRepo) GetScheduleByLapakIDs(ids []uuid.UUID) ([]model.LapakSchedule, error) {
+	schedules := make([]model.LapakSchedule, 0, len(ids))
+	for _, id := range ids {
+		schedules = append(schedules, model.LapakSchedule{LapakID: id, Timezone: "Asia/Jakarta", Status: "closed"})
+	}
+
+	return schedules, nil
+}
+
+func (routeScheduleRepo) ReplaceOpeningHours(uuid.UUID, string, []model.OpeningHours) error {
+	return nil
+}
+
+func (routeScheduleRepo) UpsertHoliday(uuid.UUID, model.LapakHoliday) error { return nil }
+
+func (routeScheduleRepo) DeleteHoliday(uuid.UUID, string) error { return nil }
+
+func (routeLocationRepo) GetAllLocation(model.LocationFilter, model.PageRequest) ([]model.Location, int, error) {
+	return []model.Location{}, 0, nil
+}
+
+func (routeLocationRepo) GetAllProvinsi() ([]string, error) { return []string{}, nil }
+
+func (routeLocationRepo) GetAllKota(string) ([]string, error) { return []string{}, nil }
+
+func (routeLocationRepo) GetLocationByID(id uuid.UUID) (*model.Location, error) {
+	return &model.Location{ID: id, Provinsi: "Jawa Barat", Kota: "Bandung", Daerah: "Coblong"}, nil
+}
+
+func (routeLocationRepo) CreateLocation(model.LocationRequest) (uuid.UUID, error) {
+	return routeNewLocationID, nil
+}
+
+func (routeLocationRepo) UpdateLocation(uuid.UUID, model.LocationRequest) error { return nil }
+
+func (routeLocationRepo) DeleteLocation(uuid.UUID) error { return nil }
+
+func (routeLocationRepo) ImportLocation(rows []model.LocationRequest) (int, error) {
+	return len(rows), nil
+}
+
+func (routeRoleRepo) GetRoleByName(name string) (*model.Role, error) {
+	return &model.Role{ID: routeRoleID, Name: name}, nil
+}
+
+func (routeRoleRepo) GetRoleByID(id uuid.UUID) (*model.Role, error) {
+	if id != routeRoleID {
+		return nil, pgx.ErrNoRows
+	}
+
+	return &model.Role{ID: id, Name: "Kurir", Permissions: []string{}}, nil
+}
+
+func (routeRoleRepo) GetAllRole() ([]model.Role, error) { return []model.Role{}, nil }
+
+func (routeRoleRepo) CreateRole(string) (uuid.UUID, error) { return routeRoleID, nil }
+
+func (routeRoleRepo) DeleteRole(uuid.UUID) error { return nil }
+
+func (routeRoleRepo) UpdateRoleRequireMFA(uuid.UUID, bool) error { return nil }
+
+func (routeRoleRepo) UpdateRoleMaxLapak(uuid.UUID, *int) error { return nil }
+
+func (routeRoleRepo) AssignUserRole(uuid.UUID, uuid.UUID) error { return nil }
+
+// GetPermissionNamesByRoleName responsible to granting admin every permission, member every route permission
+// & role prefixed by routeRoleWithout every permission except the named one
+func (pr routePermissionRepo) GetPermissionNamesByRoleName(roleName string) ([]string, error) {
+	var (
+		granted = []string{}
+		denied  = map[string]bool{}
+	)
+
+	switch {
+	case roleName == routeRoleMember:
+		for _, permission := range routeBypassPermissions {
+			denied[permission] = true
+		}
+	case strings.HasPrefix(roleName, routeRoleWithout):
+		denied[strings.TrimPrefix(roleName, routeRoleWithout)] = true
+	case roleName != model.RoleAdmin:
+		return granted, nil
+	}
+
+	for _, permission := range append(append([]string{}, pr.routePermissions...), routeBypassPermissions...) {
+		if !denied[permission] {
+			granted = append(granted, permission)
+		}
+	}
+
+	return granted, nil
+}
+
+func (routePermissionRepo) ReplaceRolePermissions(uuid.UUID, []string) error { return nil }
+
+func (routePermissionRepo) GetAllPermission() ([]model.Permission, error) {
+	return []model.Permission{}, nil
+}
+
+func (routePermissionRepo) CreatePermission(model.CreatePermissionRequest) (uuid.UUID, error) {
+	return uuid.New(), nil
+}
+
+func (routeBlacklistRepo) IsTokenRevoked(uuid.UUID) (bool, error) { return false, nil }
+
+func (routeBlacklistRepo) GetActiveBlacklistByUser(userID uuid.UUID) (*model.Blacklist, error) {
+	if userID != routeBannedID {
+		return nil, pgx.ErrNoRows
+	}
+
+	return &model.Blacklist{UserID: userID, Reason: "Penipuan berulang"}, nil
+}
+
+func (routeBlacklistRepo) BlacklistUser(uuid.UUID, string, *time.Time, uuid.UUID) error { return nil }
+
+func (routeBlacklistRepo) UnblacklistUser(uuid.UUID) error { return nil }
+
+func (routeBlacklistRepo) GetAllBlacklist() ([]model.Blacklist, error) {
+	return []model.Blacklist{}, nil
+}
+
+func (routeBlacklistRepo) RevokeToken(uuid.UUID, uuid.UUID, string, time.Time) error { return nil }
+
+func (routeHealthRepo) HealthCheck() (bool, error) { return true, nil }
+
+func (mr routeMFARepo) GetUserMFA(userID uuid.UUID) (*model.UserMFA, error) {
+	secret := routeTOTPSecret
+	userMFA := &model.UserMFA{UserID: userID, Email: "route@growbaks.test", TOTPSecret: &secret}
+
+	if mr.enabled {
+		enabledAt := time.Now()
+		userMFA.TOTPEnabledAt = &enabledAt
+	}
+
+	return userMFA, nil
+}
+
+func (routeMFARepo) SetTOTPSecret(uuid.UUID, string) error { return nil }
+
+func (routeMFARepo) EnableTOTP(uuid.UUID, int64, []string) error { return nil }
+
+func (routeMFARepo) DisableTOTP(uuid.UUID) error { return nil }
+
+func (routeMFARepo) ReplaceRecoveryCodes(uuid.UUID, []string) error { return nil }
+
+func (routeMFARepo) UseTOTPStep(uuid.UUID, int64) (bool, error) { return true, nil }
+
+func (routeMFARepo) UseRecoveryCode(uuid.UUID, string) (bool, error) { return true, nil }
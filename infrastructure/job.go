@@ -0,0 +1,73 @@
+package infrastructure
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/application"
+)
+
+// JobRunner runs registered job periodically until stopped
+type JobRunner struct {
+	Logger *logrus.Logger
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+	// jobs share a single database connection, so only one job runs at a time
+	mu sync.Mutex
+}
+
+// ServeJob is wrapper function to start the apps infra in background job mode
+func ServeJob(app *application.App) *JobRunner {
+	var (
+		dep    = application.SetupJobDependencyInjection(app)
+		runner = &JobRunner{Logger: app.Logger, stop: make(chan struct{})}
+	)
+
+	runner.Every("purge", dep.PurgeSvc.PurgeInterval(), func() error {
+		_, err := dep.PurgeSvc.PurgeSvc()
+		return err
+	})
+
+	runner.Every("schedule", dep.ScheduleSvc.ScheduleInterval(), func() error {
+		_, err := dep.ScheduleSvc.ApplyScheduleSvc()
+		return err
+	})
+
+	return runner
+}
+
+// Every is function to run job on every interval, next run waits until the previous one is finished
+func (jr *JobRunner) Every(name string, interval time.Duration, job func() error) {
+	jr.wg.Add(1)
+
+	go func() {
+		defer jr.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-jr.stop:
+				return
+			case <-ticker.C:
+				jr.mu.Lock()
+				err := job()
+				jr.mu.Unlock()
+
+				if err != nil {
+					jr.Logger.Error(fmt.Errorf("JobRunner %s ERROR %v MSG %s", name, err, err.Error()))
+				}
+			}
+		}
+	}()
+}
+
+// Stop is function to stop every job & wait for running job to be finished
+func (jr *JobRunner) Stop() {
+	close(jr.stop)
+	jr.wg.Wait()
+}
@@ -6,6 +6,8 @@ import (
 	"os"
 	"os/signal"
 	"runtime"
+	// lapak timezone is resolved even when the host has no zoneinfo installed
+	_ "time/tzdata"
 
 	"github.com/joho/godotenv"
 	"github.com/wiormiw/GrowBaks/application"
@@ -39,6 +41,9 @@ func main() {
 		panic(err)
 	}
 
+	// background job is stopped before the app closing its database connection
+	jobRunner := infrastructure.ServeJob(app)
+
 	//create a channel for listening to OS signals and connecting OS interrupts to the channel
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
@@ -47,6 +52,7 @@ func main() {
 	go func() {
 		_ = <-c
 		app.Logger.Info("APP GRACEFULLY SHUTDOWN")
+		jobRunner.Stop()
 		app.Close()
 		_ = app.Application.Shutdown()
 		serverShutdown <- struct{}{}
@@ -1,24 +1,77 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
 	"github.com/wiormiw/GrowBaks/helper"
 	"github.com/wiormiw/GrowBaks/model"
+	"github.com/wiormiw/GrowBaks/service"
 	"github.com/wiormiw/GrowBaks/util"
 )
 
+type (
+	// JWTMiddleware is an app jwt middleware struct that consists of all the dependencies needed for validating jwt
+	JWTMiddleware struct {
+		Context      context.Context
+		Config       *config.Configuration
+		Logger       *logrus.Logger
+		BlacklistSvc service.IBlacklistService
+		RoleSvc      service.IRoleService
+	}
+)
+
 // ValidateJWTMiddleware responsible to validating jwt in header each request
-func ValidateJWTMiddleware(ctx *fiber.Ctx) error {
+func (jm *JWTMiddleware) ValidateJWTMiddleware(ctx *fiber.Ctx) error {
+	return jm.validateJWT(ctx, false)
+}
+
+// ValidateJWTEnrollmentMiddleware responsible to validating jwt in header each request, still allowing token which waiting for mfa enrollment
+func (jm *JWTMiddleware) ValidateJWTEnrollmentMiddleware(ctx *fiber.Ctx) error {
+	return jm.validateJWT(ctx, true)
+}
+
+// validateJWT responsible to validating jwt, revocation & blacklist then passing decoded payload into next handler
+func (jm *JWTMiddleware) validateJWT(ctx *fiber.Ctx, allowMFAPending bool) error {
 	// validate JWT coming from request, if valid decode into a struct
 	decodedPayload, err := util.ValidateJWT(ctx)
 	if err != nil {
 		return helper.ResponseFormatter[any](ctx, fiber.StatusUnauthorized, err, fmt.Sprintf("Unauthorized access, reason : %s", err.Error()), nil)
 	}
 
-	// pass decoded payload into ctx.Locals()
+	// reject token already revoked by logout or owned by blacklisted user
+	err = jm.BlacklistSvc.ValidateAccessSvc(decodedPayload.TokenID, decodedPayload.UserID)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidToken) || errors.Is(err, model.ErrTokenRevoked) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusUnauthorized, err, fmt.Sprintf("Unauthorized access, reason : %s", err.Error()), nil)
+		}
+
+		if errors.Is(err, model.ErrUserBlacklisted) {
+			return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, model.ErrUserBlacklisted, err.Error(), nil)
+		}
+
+		jm.Logger.Error(fmt.Errorf("JWTMiddleware.ValidateJWTMiddleware ERROR : %v MSG : %s", err, err.Error()))
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	if decodedPayload.MFAPending && !allowMFAPending {
+		return helper.ResponseFormatter[any](ctx, fiber.StatusForbidden, model.ErrMFAEnrollmentRequired, model.ErrMFAEnrollmentRequired.Error(), nil)
+	}
+
+	// permissions are loaded each request so role changes by admin apply immediately
+	permissions, err := jm.RoleSvc.GetPermissionsByRoleNameSvc(decodedPayload.RoleName)
+	if err != nil {
+		jm.Logger.Error(fmt.Errorf("JWTMiddleware.ValidateJWTMiddleware GetPermissions ERROR : %v MSG : %s", err, err.Error()))
+		return helper.ResponseFormatter[any](ctx, fiber.StatusInternalServerError, err, err.Error(), nil)
+	}
+
+	// pass decoded payload & permissions into ctx.Locals()
 	ctx.Locals(model.KeyJWTValidAccess, decodedPayload)
+	ctx.Locals(model.KeyJWTPermissions, permissions)
 
 	// going to next handler..
 	return ctx.Next()
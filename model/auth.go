@@ -11,6 +11,9 @@ var (
 	// KeyJWTValidAccess is context key identifier for valid jwt token
 	KeyJWTValidAccess = "ValidJWTAccess"
 
+	// KeyJWTPermissions is context key identifier for permissions of valid jwt token role
+	KeyJWTPermissions = "JWTPermissions"
+
 	// IsAllowedEmailInput is regex validator to allowing only valid email
 	IsAllowedEmailInput *regexp.Regexp
 
@@ -30,6 +33,11 @@ type (
 		RoleID   uuid.UUID `db:"id" json:"role_id"`
 		RoleName string    `db:"name" json:"role_name"`
 		Password string    `db:"password" json:"-"`
+
+		EmailVerifiedAt *time.Time `db:"email_verified_at" json:"-"`
+		LoginProvider   string     `db:"login_provider" json:"-"`
+		TOTPEnabledAt   *time.Time `db:"totp_enabled_at" json:"-"`
+		RequireMFA      bool       `db:"require_mfa" json:"-"`
 	}
 
 	// CreateUserRequest consist data for creating a user
@@ -41,6 +49,10 @@ type (
 		LocationID string    `json:"location_id"`
 		IsSeller   bool      `json:"is_seller"`
 		RoleID     uuid.UUID `json:"-"`
+
+		LoginProvider   string `json:"-"`
+		ProviderSubject string `json:"-"`
+		EmailVerified   bool   `json:"-"`
 	}
 
 	// LoginRequest consist data for log-in a user
@@ -49,11 +61,89 @@ type (
 		Password string `json:"password"`
 	}
 
+	// ForgotPasswordRequest consist data for requesting a password reset
+	ForgotPasswordRequest struct {
+		Email string `json:"email"`
+	}
+
+	// ResetPasswordRequest consist data for resetting a password with reset token
+	ResetPasswordRequest struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+
+	// ResendVerificationRequest consist data for resending email verification
+	ResendVerificationRequest struct {
+		Email string `json:"email"`
+	}
+
+	// EmailVerification consist data of stored email verification token
+	EmailVerification struct {
+		ID        uuid.UUID  `db:"id" json:"-"`
+		UserID    uuid.UUID  `db:"user_id" json:"-"`
+		ExpiredAt time.Time  `db:"expired_at" json:"-"`
+		UsedAt    *time.Time `db:"used_at" json:"-"`
+	}
+
+	// PasswordReset consist data of stored password reset token
+	PasswordReset struct {
+		ID        uuid.UUID  `db:"id" json:"-"`
+		UserID    uuid.UUID  `db:"user_id" json:"-"`
+		ExpiredAt time.Time  `db:"expired_at" json:"-"`
+		UsedAt    *time.Time `db:"used_at" json:"-"`
+	}
+
+	// OAuthLoginRequest consist optional registration data used when oauth account is not registered yet
+	OAuthLoginRequest struct {
+		LocationID string `query:"location_id"`
+		Telepon    string `query:"telepon"`
+		IsSeller   bool   `query:"is_seller"`
+	}
+
+	// OAuthLoginResponse consist data for redirecting user into oauth provider
+	OAuthLoginResponse struct {
+		AuthURL string `json:"auth_url"`
+		State   string `json:"state"`
+	}
+
+	// OAuthCallbackRequest consist data sent back by oauth provider
+	OAuthCallbackRequest struct {
+		State string `query:"state"`
+		Code  string `query:"code"`
+	}
+
+	// OAuthState consist data of stored oauth state & pkce verifier
+	OAuthState struct {
+		State        string     `db:"state"`
+		CodeVerifier string     `db:"code_verifier"`
+		LocationID   *uuid.UUID `db:"location_id"`
+		Telepon      string     `db:"telepon"`
+		IsSeller     bool       `db:"is_seller"`
+		ExpiredAt    time.Time  `db:"expired_at"`
+	}
+
+	// OAuthUserInfo consist data of oidc userinfo response
+	OAuthUserInfo struct {
+		Subject       string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+
+	// Principal consist data of authenticated caller used for resource ownership check
+	Principal struct {
+		UserID      uuid.UUID
+		RoleName    string
+		Permissions []string
+	}
+
 	// SuccessLoginResponse consist data of success login
 	SuccessLoginResponse struct {
-		AccessToken string    `json:"access_token"`
-		ExpiredAt   time.Time `json:"expired_at"`
-		Role        string    `json:"role"`
+		AccessToken           string    `json:"access_token"`
+		ExpiredAt             time.Time `json:"expired_at"`
+		RefreshToken          string    `json:"refresh_token"`
+		RefreshTokenExpiredAt time.Time `json:"refresh_token_expired_at"`
+		Role                  string    `json:"role"`
 	}
 )
 
@@ -62,6 +152,10 @@ const (
 	RoleSeller   string = "Penjual"
 	RoleCustomer string = "Pembeli"
 	RoleAdmin    string = "Admin"
+
+	// Login Provider Mapping
+	LoginProviderPassword string = "password"
+	LoginProviderGoogle   string = "google"
 )
 
 func init() {
@@ -1 +1,53 @@
 package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// RefreshToken consist data of stored refresh token
+	RefreshToken struct {
+		ID         uuid.UUID  `db:"id" json:"-"`
+		UserID     uuid.UUID  `db:"user_id" json:"-"`
+		TokenHash  string     `db:"token_hash" json:"-"`
+		ExpiredAt  time.Time  `db:"expired_at" json:"-"`
+		RevokedAt  *time.Time `db:"revoked_at" json:"-"`
+		ReplacedBy *uuid.UUID `db:"replaced_by" json:"-"`
+	}
+
+	// RefreshTokenRequest consist data for refreshing an access token
+	RefreshTokenRequest struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	// Blacklist consist data of a banned user
+	Blacklist struct {
+		ID        uuid.UUID  `db:"id" json:"id"`
+		UserID    uuid.UUID  `db:"user_id" json:"user_id"`
+		FullName  string     `db:"full_name" json:"full_name"`
+		Email     string     `db:"email" json:"email"`
+		Reason    string     `db:"reason" json:"reason"`
+		ExpiredAt *time.Time `db:"expired_at" json:"expired_at,omitempty"`
+		CreatedBy *uuid.UUID `db:"created_by" json:"created_by,omitempty"`
+		CreatedAt *time.Time `db:"created_at" json:"created_at"`
+	}
+
+	// BlacklistUserRequest consist data for banning a user
+	BlacklistUserRequest struct {
+		Reason    string     `json:"reason"`
+		ExpiredAt *time.Time `json:"expired_at"`
+	}
+
+	// LogoutRequest consist data for log-out a user
+	LogoutRequest struct {
+		RefreshToken string `json:"refresh_token"`
+		All          bool   `json:"all"`
+	}
+)
+
+const (
+	// Blacklist reason mapping
+	ReasonLogout string = "logout"
+)
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// AddCartItemRequest holds product to be added into cart, qty is summed when product already inside cart
+	AddCartItemRequest struct {
+		ProductID uuid.UUID `json:"product_id"`
+		QTY       int       `json:"qty"`
+	}
+
+	// UpdateCartItemRequest holds new qty of a product inside cart
+	UpdateCartItemRequest struct {
+		QTY int `json:"qty"`
+	}
+
+	// CartItem is a product inside cart of a buyer
+	CartItem struct {
+		ProductID   uuid.UUID  `db:"product_id" json:"product_id"`
+		ProductName string     `db:"product_name" json:"product_name"`
+		Stock       int        `db:"stok" json:"stok"`
+		LapakID     uuid.UUID  `db:"lapak_id" json:"lapak_id"`
+		LapakName   string     `db:"lapak_name" json:"lapak_name"`
+		QTY         int        `db:"qty" json:"qty"`
+		Price       int64      `db:"price" json:"price"`
+		Subtotal    int64      `db:"subtotal" json:"subtotal"`
+		CreatedAt   *time.Time `db:"created_at" json:"created_at"`
+		UpdatedAt   *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+	}
+
+	// CheckoutResponse holds every pemesanan created from a checkout, one per lapak
+	CheckoutResponse struct {
+		PemesananIDs []uuid.UUID `json:"pemesanan_ids"`
+	}
+)
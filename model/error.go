@@ -11,6 +11,32 @@ var (
 	ErrInvalidToken = errors.New("invalid jwt token")
 	// ErrTokenExpire occurs when jwt token already expired
 	ErrTokenExpire = errors.New("token already expired, please to relogin application")
+	// ErrTokenRevoked occurs when jwt token already revoked by logout
+	ErrTokenRevoked = errors.New("token already revoked, please to relogin application")
+	// ErrUserBlacklisted occurs when blacklisted user trying to access the application
+	ErrUserBlacklisted = errors.New("account is blacklisted, please contact our admin")
+	// ErrInvalidResetToken occurs when password reset token is not found, expired or already used
+	ErrInvalidResetToken = errors.New("invalid or expired reset password token")
+	// ErrInvalidVerificationToken occurs when email verification token is not found, expired or already used
+	ErrInvalidVerificationToken = errors.New("invalid or expired email verification token")
+	// ErrEmailNotVerified occurs when unverified user trying to access resource which require verified email
+	ErrEmailNotVerified = errors.New("email is not verified, please verify your email first")
+	// ErrInvalidMFAToken occurs when mfa challenge token is not found, expired, already used or out of attempts
+	ErrInvalidMFAToken = errors.New("invalid or expired mfa token, please to relogin application")
+	// ErrInvalidMFACode occurs when TOTP or recovery code is invalid
+	ErrInvalidMFACode = errors.New("invalid mfa code")
+	// ErrMFAEnrollmentRequired occurs when user role require mfa but user has not enabled it yet
+	ErrMFAEnrollmentRequired = errors.New("two-factor authentication is required for your role, please enroll through /v1/auth/mfa/enroll")
+	// ErrMFAAlreadyEnabled occurs when user trying to enroll mfa twice
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	// ErrMFANotEnabled occurs when user trying to manage mfa which is not enabled yet
+	ErrMFANotEnabled = errors.New("two-factor authentication is not enabled")
+	// ErrMFANotEnrolled occurs when user trying to verify mfa before enrolling
+	ErrMFANotEnrolled = errors.New("two-factor authentication is not enrolled yet, please enroll through /v1/auth/mfa/enroll")
+	// ErrForbiddenDisableMFA occurs when user trying to disable mfa which is required by their role
+	ErrForbiddenDisableMFA = errors.New("forbidden disabling two-factor authentication required by your role")
+	// ErrInvalidRefreshToken occurs when refresh token is not found, expired or revoked
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
 
 	// ErrTypeAssertion occurs when doing invalid type assertion
 	ErrTypeAssertion = errors.New("type assertion error")
@@ -39,13 +65,37 @@ var (
 	ErrLapakNotFound = errors.New("lapak is not found")
 	// ErrProductNotFound occurs when like is not found in database
 	ErrProductNotFound = errors.New("product is not found")
+	// ErrCartEmpty occurs when checking out a cart without any item
+	ErrCartEmpty = errors.New("cart is empty")
+	// ErrCartItemNotFound occurs when product is not inside cart of the user
+	ErrCartItemNotFound = errors.New("cart item is not found")
+	// ErrInsufficientStock occurs when ordered qty exceeds remaining product stock
+	ErrInsufficientStock = errors.New("product stock is insufficient")
+	// ErrProductNotPriced occurs when ordering product whose price is not set by its seller yet
+	ErrProductNotPriced = errors.New("product is not priced yet by its seller")
 	// ErrPemesananNotFound occurs when like is not found in database
 	ErrPemesananNotFound = errors.New("pemesanan is not found")
+	// ErrInvalidStatusTransition occurs when pemesanan status change is not allowed from its current status or by the caller
+	ErrInvalidStatusTransition = errors.New("pemesanan status transition is not allowed")
+	// ErrPemesananNotCancelled occurs when deleting pemesanan which is not cancelled without order:manage:any
+	ErrPemesananNotCancelled = errors.New("only cancelled pemesanan can be deleted, cancel it first")
+	// ErrBlacklistNotFound occurs when user has no active blacklist in database
+	ErrBlacklistNotFound = errors.New("blacklist is not found")
+	// ErrLapakLimitReached occurs when seller already owns as many lapak as allowed by their role
+	ErrLapakLimitReached = errors.New("lapak limit of your role is reached")
+	// ErrHolidayNotFound occurs when lapak has no holiday on given date
+	ErrHolidayNotFound = errors.New("holiday is not found")
+	// ErrLocationNotFound occurs when location is not found in database
+	ErrLocationNotFound = errors.New("location is not found")
+	// ErrLocationExisted occurs when provinsi, kota & daerah already created inside database
+	ErrLocationExisted = errors.New("location is existed")
+	// ErrLocationInUse occurs when deleting location which still used by users or lapak
+	ErrLocationInUse = errors.New("location is still used by users or lapak")
 
 	// ErrInvalidPassword occurs when password user inputed is invalid
 	ErrInvalidPassword = errors.New("invalid password")
 	// ErrMismatchLogin occurs when user trying mismatch login method
-	ErrMismatchLogin = errors.New("mismatch login, please use endpoint /api/v1/auth/google/login")
+	ErrMismatchLogin = errors.New("mismatch login, please use endpoint /v1/auth/google/login")
 	// ErrAlreadyLikeBlog occurs when user trying to liking a blog more than 1 times
 	ErrAlreadyLikeBlog = errors.New("already liking this blog")
 
@@ -53,15 +103,35 @@ var (
 	ErrRedisKeyNotExisted = errors.New("keys not existed")
 	// ErrInvalidExchange occurs when client sent invalid state & code
 	ErrInvalidExchange = errors.New("invalid exchange")
+	// ErrOAuthRegistrationRequired occurs when oauth account is not registered yet and registration data is missing
+	ErrOAuthRegistrationRequired = errors.New("account is not registered, please provide location_id and telepon on /v1/auth/google/login")
 
 	// ErrRoleNotExisted occurs when role provided is not existed
 	ErrRoleNotExisted = errors.New("role not existed")
+	// ErrRoleNotFound occurs when role is not found in database
+	ErrRoleNotFound = errors.New("role is not found")
+	// ErrRoleExisted occurs when role name already created inside database
+	ErrRoleExisted = errors.New("role is existed")
+	// ErrRoleInUse occurs when deleting role which still assigned into users
+	ErrRoleInUse = errors.New("role is still assigned into users")
+	// ErrPermissionNotFound occurs when permission is not found in database
+	ErrPermissionNotFound = errors.New("permission is not found")
+	// ErrPermissionExisted occurs when permission name already created inside database
+	ErrPermissionExisted = errors.New("permission is existed")
 
 	// ErrForbidenAccess occurs when user trying to access forbidden resource
 	ErrForbiddenAccess = errors.New("forbidden access")
 
 	// ErrForbiddenDeleteSelf occurs when user trying deleting their account by self
 	ErrForbiddenDeleteSelf = errors.New("forbidden delete account self, make sure the id is corrent")
+	// ErrForbiddenBlacklistSelf occurs when admin trying blacklisting their account by self
+	ErrForbiddenBlacklistSelf = errors.New("forbidden blacklist account self, make sure the id is correct")
+	// ErrForbiddenRevokeRoleManage occurs when admin trying to revoke role:manage from their own role
+	ErrForbiddenRevokeRoleManage = errors.New("forbidden revoking role:manage from your own role")
+	// ErrForbiddenAssignSelf occurs when admin trying to change role of their own account
+	ErrForbiddenAssignSelf = errors.New("forbidden changing role of your own account")
+	// ErrForbiddenDeleteRole occurs when admin trying to delete their own role or a built-in role
+	ErrForbiddenDeleteRole = errors.New("forbidden deleting your own role or built-in role")
 	// ErrForbiddenUpdate occurs when user trying updating forbidden resource
 	ErrForbiddenUpdate = errors.New("forbidden updating data")
 	// ErrForbiddenDelete occurs when user trying deleting forbidden resource
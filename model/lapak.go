@@ -1,23 +1,97 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type (
 	// Lapak
 	Lapak struct {
-		UserID     uuid.UUID `db:"user_id" json:"user_id"`
-		FullName   string    `db:"user_id" json:"full_name"`
-		LapakID    uuid.UUID `db:"lapak_id" json:"lapak_id"`
-		LapakName  string    `db:"lapak_name" json:"lapak_name"`
-		Status     string    `db:"status" json:"status"`
-		LocationID uuid.UUID `db:"location_id" json:"location_id"`
-		Daerah     string    `db:"daerah" json:"daerah"`
+		UserID      uuid.UUID `db:"user_id" json:"user_id"`
+		FullName    string    `db:"user_id" json:"full_name"`
+		LapakID     uuid.UUID `db:"lapak_id" json:"lapak_id"`
+		LapakName   string    `db:"lapak_name" json:"lapak_name"`
+		Description string    `db:"description" json:"description,omitempty"`
+		Status      string    `db:"status" json:"status"`
+		LocationID  uuid.UUID `db:"location_id" json:"location_id"`
+		Daerah      string    `db:"daerah" json:"daerah"`
+		Latitude    *float64  `db:"latitude" json:"latitude,omitempty"`
+		Longitude   *float64  `db:"longitude" json:"longitude,omitempty"`
+		DistanceKM  *float64  `db:"distance_km" json:"distance_km,omitempty"`
+
+		// profile is only returned by lapak detail
+		BannerURL string `db:"banner_url" json:"banner_url,omitempty"`
+		LogoURL   string `db:"logo_url" json:"logo_url,omitempty"`
+		WhatsApp  string `db:"whatsapp" json:"whatsapp,omitempty"`
+		Address   string `db:"address" json:"address,omitempty"`
+		Landmark  string `db:"landmark" json:"landmark,omitempty"`
+
+		DeletedAt *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
 	}
 
-	// LapakUpdate
+	// LapakDetail consist a lapak along with summary of its products
+	LapakDetail struct {
+		Lapak
+		ProductCount int `db:"product_count" json:"product_count"`
+		InStockCount int `db:"in_stock_count" json:"in_stock_count"`
+	}
+
+	// LapakUpdate holds partial lapak update, nil field is left unchanged & empty profile field is cleared
 	LapakUpdate struct {
-		Name   string `db:"name" json:"name"`
-		Status string `db:"status" json:"status"`
+		Name        *string  `db:"name" json:"name"`
+		Status      *string  `db:"status" json:"status"`
+		Description *string  `db:"description" json:"description"`
+		BannerURL   *string  `db:"banner_url" json:"banner_url"`
+		LogoURL     *string  `db:"logo_url" json:"logo_url"`
+		WhatsApp    *string  `db:"whatsapp" json:"whatsapp"`
+		Address     *string  `db:"address" json:"address"`
+		Landmark    *string  `db:"landmark" json:"landmark"`
+		Latitude    *float64 `db:"latitude" json:"latitude"`
+		Longitude   *float64 `db:"longitude" json:"longitude"`
+	}
+
+	// GeoRadius holds circle of nearby search around a coordinate
+	GeoRadius struct {
+		Latitude  float64
+		Longitude float64
+		RadiusKM  float64
+	}
+
+	// LapakCreateRequest consist data for creating a lapak owned by the caller, status is closed when not given
+	LapakCreateRequest struct {
+		Name        string   `json:"name"`
+		Description string   `json:"description"`
+		LocationID  string   `json:"location_id"`
+		Status      string   `json:"status"`
+		Latitude    *float64 `json:"latitude"`
+		Longitude   *float64 `json:"longitude"`
+	}
+
+	// LapakRelocateRequest consist data for moving a lapak into other location, coordinate is cleared when not given
+	LapakRelocateRequest struct {
+		LocationID string   `json:"location_id"`
+		Latitude   *float64 `json:"latitude"`
+		Longitude  *float64 `json:"longitude"`
+	}
+
+	// LapakLocationHistory is a recorded relocation of a lapak
+	LapakLocationHistory struct {
+		ID             uuid.UUID  `db:"id" json:"id"`
+		FromLocationID uuid.UUID  `db:"from_location_id" json:"from_location_id"`
+		FromDaerah     string     `db:"from_daerah" json:"from_daerah"`
+		ToLocationID   uuid.UUID  `db:"to_location_id" json:"to_location_id"`
+		ToDaerah       string     `db:"to_daerah" json:"to_daerah"`
+		ActorID        uuid.UUID  `db:"actor_id" json:"actor_id"`
+		ActorRole      string     `db:"actor_role" json:"actor_role"`
+		CreatedAt      *time.Time `db:"created_at" json:"created_at"`
+	}
+
+	// LapakBuyer consist contact of buyer having open pemesanan on a lapak
+	LapakBuyer struct {
+		Email    string `db:"email"`
+		FullName string `db:"full_name"`
 	}
 
 	// LapakStatus
@@ -5,7 +5,30 @@ import "github.com/google/uuid"
 type (
 	// Location
 	Location struct {
-		ID     uuid.UUID `db:"id" json:"-"`
-		Daerah string    `db:"daerah" json:"daerah"`
+		ID       uuid.UUID `db:"id" json:"id"`
+		Provinsi string    `db:"provinsi" json:"provinsi"`
+		Kota     string    `db:"kota" json:"kota"`
+		Daerah   string    `db:"daerah" json:"daerah"`
+	}
+
+	// LocationFilter consist hierarchy & search filter of location listing, empty field means not filtered
+	LocationFilter struct {
+		Provinsi string
+		Kota     string
+		Search   string
+	}
+
+	// LocationRequest consist data for creating or updating a location
+	LocationRequest struct {
+		Provinsi string `json:"provinsi"`
+		Kota     string `json:"kota"`
+		Daerah   string `json:"daerah"`
+	}
+
+	// LocationImportResult consist summary of a bulk location import, skipped row is location which already existed
+	LocationImportResult struct {
+		Total   int `json:"total"`
+		Created int `json:"created"`
+		Skipped int `json:"skipped"`
 	}
 )
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// UserMFA consist data of user two-factor authentication state
+	UserMFA struct {
+		UserID        uuid.UUID  `db:"id" json:"-"`
+		Email         string     `db:"email" json:"-"`
+		TOTPSecret    *string    `db:"totp_secret" json:"-"`
+		TOTPEnabledAt *time.Time `db:"totp_enabled_at" json:"-"`
+		TOTPLastStep  *int64     `db:"totp_last_step" json:"-"`
+		RequireMFA    bool       `db:"require_mfa" json:"-"`
+	}
+
+	// MFAChallenge consist data of stored login mfa challenge token
+	MFAChallenge struct {
+		ID        uuid.UUID  `db:"id" json:"-"`
+		UserID    uuid.UUID  `db:"user_id" json:"-"`
+		Attempts  int        `db:"attempts" json:"-"`
+		ExpiredAt time.Time  `db:"expired_at" json:"-"`
+		UsedAt    *time.Time `db:"used_at" json:"-"`
+	}
+
+	// MFAChallengeResponse consist data of login which still waiting for mfa code
+	MFAChallengeResponse struct {
+		MFAToken  string    `json:"mfa_token"`
+		ExpiredAt time.Time `json:"expired_at"`
+	}
+
+	// MFALoginRequest consist data for finishing login with mfa code
+	MFALoginRequest struct {
+		MFAToken string `json:"mfa_token"`
+		Code     string `json:"code"`
+	}
+
+	// MFACodeRequest consist data of TOTP or recovery code
+	MFACodeRequest struct {
+		Code string `json:"code"`
+	}
+
+	// MFAEnrollResponse consist data for registering TOTP into authenticator app
+	MFAEnrollResponse struct {
+		Secret          string `json:"secret"`
+		ProvisioningURI string `json:"provisioning_uri"`
+	}
+
+	// MFARecoveryCodesResponse consist data of freshly generated recovery codes
+	MFARecoveryCodesResponse struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+)
@@ -17,15 +17,47 @@ type (
 		Status string `db:"status" json:"status"`
 	}
 
+	// PemesananLapakFilter holds optional filter of seller order inbox, both dates are inclusive
+	PemesananLapakFilter struct {
+		Status string
+		From   *time.Time
+		To     *time.Time
+	}
+
 	PemesananResponse struct {
 		ID            uuid.UUID  `db:"id" json:"id_pemesanan"`
 		PemesananName string     `db:"name" json:"pemesanan_name"`
 		Status        string     `db:"status" json:"status"`
 		UserID        string     `db:"user_id" json:"user_id"`
 		ProductID     string     `db:"product_id" json:"product_id"`
+		LapakID       string     `db:"lapak_id" json:"lapak_id"`
 		QTY           int        `db:"qty" json:"qty"`
+		TotalPrice    int64      `db:"total_price" json:"total_price"`
 		CreatedAt     *time.Time `db:"created_at" json:"created_at"`
 		UpdatedAt     *time.Time `db:"created_at" json:"updated_at"`
+
+		Items   []PemesananItem          `json:"items,omitempty"`
+		History []PemesananStatusHistory `json:"history,omitempty"`
+	}
+
+	// PemesananItem is an ordered product of a pemesanan
+	PemesananItem struct {
+		ID          uuid.UUID `db:"id" json:"id"`
+		ProductID   uuid.UUID `db:"product_id" json:"product_id"`
+		ProductName string    `db:"product_name" json:"product_name"`
+		QTY         int       `db:"qty" json:"qty"`
+		UnitPrice   int64     `db:"unit_price" json:"unit_price"`
+		Subtotal    int64     `db:"subtotal" json:"subtotal"`
+	}
+
+	// PemesananStatusHistory is a recorded status transition of a pemesanan
+	PemesananStatusHistory struct {
+		ID         uuid.UUID  `db:"id" json:"id"`
+		FromStatus *string    `db:"from_status" json:"from_status"`
+		ToStatus   string     `db:"to_status" json:"to_status"`
+		ActorID    uuid.UUID  `db:"actor_id" json:"actor_id"`
+		ActorRole  string     `db:"actor_role" json:"actor_role"`
+		CreatedAt  *time.Time `db:"created_at" json:"created_at"`
 	}
 
 	/*
@@ -39,3 +71,28 @@ type (
 		updated_at TIMESTAMPTZ
 	*/
 )
+
+const (
+	// Pemesanan status, mirroring pemesanan_status enum
+	PemesananStatusReserve string = "reserve"
+	PemesananStatusPending string = "pending"
+	PemesananStatusDone    string = "done"
+	PemesananStatusCancel  string = "cancel"
+)
+
+const (
+	// Pemesanan actor, the relation of caller into a pemesanan when changing its status
+	PemesananActorBuyer  string = "buyer"
+	PemesananActorSeller string = "seller"
+	PemesananActorAdmin  string = "admin"
+)
+
+// IsValidPemesananStatus responsible to checking status against pemesanan_status enum
+func IsValidPemesananStatus(status string) bool {
+	switch status {
+	case PemesananStatusReserve, PemesananStatusPending, PemesananStatusDone, PemesananStatusCancel:
+		return true
+	}
+
+	return false
+}
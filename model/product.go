@@ -11,6 +11,7 @@ type (
 	CreateProductRequest struct {
 		ProductName     string    `db:"name" json:"product_name"`
 		Stock           int       `db:"stok" json:"stok"`
+		Price           int64     `db:"price" json:"price"`
 		ProductCategory string    `db:"product_kategori" json:"product_kategori"`
 		ProductImg      string    `db:"product_img" json:"product_img"`
 		LapakID         uuid.UUID `db:"lapak_id" json:"-"`
@@ -20,15 +21,26 @@ type (
 	UpdateProductRequest struct {
 		ProductName     string `db:"name" json:"product_name"`
 		Stock           int    `db:"stok" json:"stok"`
+		Price           int64  `db:"price" json:"price"`
 		ProductCategory string `db:"product_kategori" json:"product_kategori"`
 		ProductImg      string `db:"product_img" json:"product_img" form:"image"`
 	}
 
+	// PatchProductRequest holds partial product update, nil field is left unchanged
+	PatchProductRequest struct {
+		ProductName     *string `json:"product_name"`
+		Stock           *int    `json:"stok"`
+		Price           *int64  `json:"price"`
+		ProductCategory *string `json:"product_kategori"`
+		ProductImg      *string `json:"product_img"`
+	}
+
 	// Get All Product Model
 	GetAllProductRequest struct {
 		ID              uuid.UUID  `db:"product_id" json:"product_id"`
 		ProductName     string     `db:"product_name" json:"product_name"`
 		Stock           int        `db:"stok" json:"stok"`
+		Price           int64      `db:"price" json:"price"`
 		ProductCategory string     `db:"product_kategori" json:"product_kategori"`
 		ProductImg      string     `db:"product_img" json:"product_img"`
 		CreatedAt       *time.Time `db:"created_at" json:"created_at"`
@@ -37,5 +49,35 @@ type (
 		LapakName       string     `db:"lapak_name" json:"lapak_name"`
 		LocationID      uuid.UUID  `db:"location_id" json:"location_id"`
 		Daerah          string     `db:"daerah" json:"daerah"`
+		LapakStatus     string     `db:"lapak_status" json:"lapak_status,omitempty"`
+		OpensAt         *time.Time `json:"opens_at,omitempty"`
+		DeletedAt       *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
+	}
+
+	// ProductFilter holds product list filter, zero value field is not applied
+	ProductFilter struct {
+		Search         string
+		Categories     []string
+		InStock        bool
+		OpenLapakOnly  bool
+		PriceMin       *int64
+		PriceMax       *int64
+		LapakID        *uuid.UUID
+		LocationID     *uuid.UUID
+		Daerah         string
+		IncludeDeleted bool
+		Near           *GeoRadius
+	}
+
+	// FacetCount holds number of product having a facet value
+	FacetCount struct {
+		Value string `json:"value"`
+		Count int    `json:"count"`
+	}
+
+	// ProductCursor points to last product of a feed page, next page continues after it
+	ProductCursor struct {
+		CreatedAt time.Time
+		ID        uuid.UUID
 	}
 )
@@ -0,0 +1,10 @@
+package model
+
+type (
+	// PurgeResult holds number of soft deleted record permanently removed by purge job
+	PurgeResult struct {
+		Products int64
+		Lapak    int64
+		Users    int64
+	}
+)
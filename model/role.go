@@ -1,11 +1,83 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// IsAllowedPermissionInput is regex validator to allowing only resource:action or resource:action:scope permission name
+	IsAllowedPermissionInput *regexp.Regexp
+)
 
 type (
 	// Role
 	Role struct {
-		ID   uuid.UUID `db:"id" json:"-"`
+		ID   uuid.UUID `db:"id" json:"id"`
 		Name string    `db:"name" json:"name"`
+
+		RequireMFA  bool     `db:"require_mfa" json:"require_mfa"`
+		MaxLapak    *int     `db:"max_lapak" json:"max_lapak"`
+		Permissions []string `db:"permissions" json:"permissions"`
+	}
+
+	// Permission consist data of an action which can be granted into role
+	Permission struct {
+		ID          uuid.UUID `db:"id" json:"id"`
+		Name        string    `db:"name" json:"name"`
+		Description string    `db:"description" json:"description"`
+	}
+
+	// CreateRoleRequest consist data for creating a role
+	CreateRoleRequest struct {
+		Name string `json:"name"`
+	}
+
+	// CreatePermissionRequest consist data for creating a permission
+	CreatePermissionRequest struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	// UpdateRolePermissionsRequest consist data for replacing every permission of a role
+	UpdateRolePermissionsRequest struct {
+		Permissions []string `json:"permissions"`
+	}
+
+	// AssignUserRoleRequest consist data for moving a user into another role
+	AssignUserRoleRequest struct {
+		RoleID string `json:"role_id"`
+	}
+
+	// UpdateRoleMFARequest consist data for requiring mfa on a role
+	UpdateRoleMFARequest struct {
+		RequireMFA bool `json:"require_mfa"`
+	}
+
+	// UpdateRoleLapakLimitRequest consist data for capping lapak owned by each user of a role, null means unlimited
+	UpdateRoleLapakLimitRequest struct {
+		MaxLapak *int `json:"max_lapak"`
 	}
 )
+
+const (
+	// PermissionRoleManage is permission for managing role & permission, admin must never lose it
+	PermissionRoleManage string = "role:manage"
+
+	// Ownership bypass permission, granted role may manage resource owned by anyone
+	PermissionLapakManageAny   string = "lapak:manage:any"
+	PermissionProductManageAny string = "product:manage:any"
+	PermissionOrderManageAny   string = "order:manage:any"
+	PermissionUserManageAny    string = "user:manage:any"
+
+	// PermissionUserRead is permission for reading account & profile of any user
+	PermissionUserRead string = "user:read"
+
+	// PermissionTrashManage is permission for listing soft deleted record & restoring it
+	PermissionTrashManage string = "trash:manage"
+)
+
+func init() {
+	IsAllowedPermissionInput = regexp.MustCompile(`^[a-z_]+:[a-z_]+(:[a-z_]+)?$`)
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// OpeningHours is a weekly opening window of a lapak, day_of_week 0 is sunday & close_time not after open_time closes on the next day
+	OpeningHours struct {
+		DayOfWeek int    `db:"day_of_week" json:"day_of_week"`
+		OpenTime  string `db:"open_time" json:"open_time"`
+		CloseTime string `db:"close_time" json:"close_time"`
+	}
+
+	// LapakHoliday is a date on which lapak stays closed regardless of its opening hours
+	LapakHoliday struct {
+		Date string `db:"holiday_date" json:"date"`
+		Note string `db:"note" json:"note,omitempty"`
+	}
+
+	// LapakSchedule consist opening hours & upcoming holidays of a lapak, opens_at is only given when the lapak is closed
+	LapakSchedule struct {
+		LapakID         uuid.UUID      `db:"lapak_id" json:"lapak_id"`
+		Timezone        string         `db:"timezone" json:"timezone"`
+		Status          string         `db:"status" json:"status"`
+		ScheduledStatus *string        `db:"scheduled_status" json:"-"`
+		Hours           []OpeningHours `json:"hours"`
+		Holidays        []LapakHoliday `json:"holidays"`
+		OpensAt         *time.Time     `json:"opens_at,omitempty"`
+	}
+
+	// UpdateOpeningHoursRequest consist data for replacing weekly opening hours of a lapak, empty hours turns the scheduler off
+	UpdateOpeningHoursRequest struct {
+		Timezone string         `json:"timezone"`
+		Hours    []OpeningHours `json:"hours"`
+	}
+
+	// ScheduleResult holds number of lapak opened & closed by scheduler
+	ScheduleResult struct {
+		Opened int64
+		Closed int64
+	}
+)
@@ -6,9 +6,20 @@ type (
 		Page  int               `json:"page,omitempty"`
 		Size  int               `json:"size,omitempty"`
 		Order string            `json:"order,omitempty"`
-		TotalData int           `json:"total_data,omitempty"`
-		TotalPage int           `json:"total_page,omitempty"`
+		Sort  string            `json:"sort,omitempty"`
+		TotalData int           `json:"total_data"`
+		TotalPage int           `json:"total_page"`
+		NextCursor string       `json:"next_cursor,omitempty"`
+		Facets map[string][]FacetCount `json:"facets,omitempty"`
 		Links map[string]string `json:"links,omitempty"`
 	}
 
+	// PageRequest consists pagination & sorting query of list endpoint
+	PageRequest struct {
+		Page  int
+		Size  int
+		Sort  string
+		Order string
+	}
+
 )
\ No newline at end of file
@@ -20,6 +20,11 @@ type (
 		Gender       string `json:"gender,omitempty"`
 		Email        string `json:"email,omitempty"`
 		Telepon      string `json:"telepon,omitempty"`
+
+		// location & coordinate is left unchanged when not given
+		LocationID string   `json:"location_id,omitempty"`
+		Latitude   *float64 `json:"latitude,omitempty"`
+		Longitude  *float64 `json:"longitude,omitempty"`
 	}
 
 	// ViewUserResponse consist data of user
@@ -29,6 +34,9 @@ type (
 		Email     string     `db:"email" json:"email,omitempty"`
 		CreatedAt *time.Time `db:"created_at" json:"created_at,omitempty"`
 		UpdatedAt *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+
+		EmailVerifiedAt *time.Time `db:"email_verified_at" json:"email_verified_at,omitempty"`
+		DeletedAt       *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
 	}
 
 	ViewUserProfileResponse struct {
@@ -42,5 +50,7 @@ type (
 		TanggalLahir *time.Time `db:"tanggal_lahir" json:"tanggal_lahir,omitempty"`
 		LocationID   uuid.UUID  `db:"location_id" json:"location_id,omitempty"`
 		Daerah       string     `db:"daerah" json:"daerah,omitempty"`
+		Latitude     *float64   `db:"latitude" json:"latitude,omitempty"`
+		Longitude    *float64   `db:"longitude" json:"longitude,omitempty"`
 	}
 )
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4"
@@ -14,8 +15,24 @@ import (
 type (
 	// IAuthRepository is an interface that has all the function to be implemented inside auth repository
 	IAuthRepository interface {
-		CreateUser(user model.CreateUserRequest, is_seller bool) error
+		CreateUser(user model.CreateUserRequest, is_seller bool) (uuid.UUID, error)
 		GetUserByEmail(email string) (*model.AuthUserDetails, error)
+		GetUserByID(id uuid.UUID) (*model.AuthUserDetails, error)
+		CreateRefreshToken(userID uuid.UUID, tokenHash string, expiredAt time.Time) error
+		GetRefreshTokenByHash(tokenHash string) (*model.RefreshToken, error)
+		RotateRefreshToken(oldID uuid.UUID, userID uuid.UUID, tokenHash string, expiredAt time.Time) error
+		RevokeRefreshTokenByHash(userID uuid.UUID, tokenHash string) error
+		RevokeAllRefreshTokenByUser(userID uuid.UUID) error
+		CreatePasswordReset(userID uuid.UUID, tokenHash string, expiredAt time.Time) error
+		GetPasswordResetByHash(tokenHash string) (*model.PasswordReset, error)
+		ResetPassword(resetID uuid.UUID, userID uuid.UUID, password string) error
+		CreateEmailVerification(userID uuid.UUID, tokenHash string, expiredAt time.Time) error
+		GetEmailVerificationByHash(tokenHash string) (*model.EmailVerification, error)
+		VerifyEmail(verificationID uuid.UUID, userID uuid.UUID) error
+		GetUserByProviderSubject(subject string) (*model.AuthUserDetails, error)
+		LinkProviderSubject(userID uuid.UUID, subject string) error
+		CreateOAuthState(state model.OAuthState) error
+		ConsumeOAuthState(state string) (*model.OAuthState, error)
 	}
 
 	// AuthRepository is an app auth struct that consists of all the dependencies needed for auth repository
@@ -28,26 +45,34 @@ type (
 )
 
 // CreateUser repository layer for executing command creating a user
-func (ar *AuthRepository) CreateUser(user model.CreateUserRequest, is_seller bool) error {
+func (ar *AuthRepository) CreateUser(user model.CreateUserRequest, is_seller bool) (uuid.UUID, error) {
 	tx, err := ar.DB.Begin(ar.Context)
 	if err != nil {
 		ar.Logger.Error(fmt.Sprintf("Error 13: %s", err))
-		return err
+		return uuid.Nil, err
 	}
-	q := `INSERT INTO "users" (full_name,email,password,role_id) VALUES ($1,$2,$3,$4) RETURNING id`
+	q := `INSERT INTO "users" (full_name,email,password,role_id,login_provider,provider_subject,email_verified_at) VALUES ($1,$2,$3,$4,$5,NULLIF($6,''),$7) RETURNING id`
+
+	var (
+		userID          uuid.UUID
+		emailVerifiedAt *time.Time
+	)
 
-	var userID uuid.UUID
+	if user.EmailVerified {
+		now := time.Now()
+		emailVerifiedAt = &now
+	}
 
-	err = tx.QueryRow(ar.Context, q, user.FullName, user.Email, user.Password, user.RoleID).Scan(&userID)
+	err = tx.QueryRow(ar.Context, q, user.FullName, user.Email, user.Password, user.RoleID, user.LoginProvider, user.ProviderSubject, emailVerifiedAt).Scan(&userID)
 	if err != nil {
 		err = tx.Rollback(ar.Context)
 		if err != nil {
 			ar.Logger.Error(fmt.Errorf("AuthRepository.CreateUser.QueryRow.Scan Rollback ERROR %v MSG %s", err, err.Error()))
-			return err
+			return uuid.Nil, err
 		}
 
 		ar.Logger.Error(fmt.Errorf("AuthRepository.CreateUser.QueryRow Scan ERROR %v MSG %s", err, err.Error()))
-		return err
+		return uuid.Nil, err
 	}
 
 	q2 := `INSERT INTO "users_profile" (telepon,location_id,user_id) VALUES ($1,$2,$3)`
@@ -56,11 +81,11 @@ func (ar *AuthRepository) CreateUser(user model.CreateUserRequest, is_seller boo
 		err = tx.Rollback(ar.Context)
 		if err != nil {
 			ar.Logger.Error(fmt.Errorf("AuthRepository.CreateUser.Exec User Profile Rollback ERROR %v MSG %s", err, err.Error()))
-			return err
+			return uuid.Nil, err
 		}
 
 		ar.Logger.Error(fmt.Errorf("AuthRepository.CreateUser.Exec User Profile ERROR %v MSG %s", err, err.Error()))
-		return err
+		return uuid.Nil, err
 	}
 
 	if is_seller {
@@ -71,21 +96,21 @@ func (ar *AuthRepository) CreateUser(user model.CreateUserRequest, is_seller boo
 			err = tx.Rollback(ar.Context)
 			if err != nil {
 				ar.Logger.Error(fmt.Errorf("AuthRepository.CreateUser.Exec Lapak Penjual Rollback ERROR %v MSG %s", err, err.Error()))
-				return err
+				return uuid.Nil, err
 			}
 
 			ar.Logger.Error(fmt.Errorf("AuthRepository.CreateUser.Exec Lapak Penjual ERROR %v MSG %s", err, err.Error()))
-			return err
+			return uuid.Nil, err
 		}
 	}
 
 	err = tx.Commit(ar.Context)
 	if err != nil {
 		ar.Logger.Error(fmt.Errorf("AuthRepository.CreateUser Commit ERROR %v MSG %s", err, err.Error()))
-		return err
+		return uuid.Nil, err
 	}
 
-	return nil
+	return userID, nil
 }
 
 // GetUserByEmail repository layer for querying command getting any user by email
@@ -97,14 +122,18 @@ func (ar *AuthRepository) GetUserByEmail(email string) (*model.AuthUserDetails,
 		u.full_name,
 		u.email,
 		u.password,
+		u.email_verified_at,
+		u.login_provider,
+		u.totp_enabled_at,
+		COALESCE(r.require_mfa, false),
 		r.id,
 		r.name FROM "users" u 
 		LEFT JOIN roles r ON r.id = u.role_id 
-		WHERE u.email = $1
+		WHERE u.email = $1 AND u.deleted_at IS NULL
 	`
 
 	row := ar.DB.QueryRow(ar.Context, q, email)
-	err := row.Scan(&authUserDetail.UserID, &authUserDetail.FullName, &authUserDetail.Email, &authUserDetail.Password, &authUserDetail.RoleID, &authUserDetail.RoleName)
+	err := row.Scan(&authUserDetail.UserID, &authUserDetail.FullName, &authUserDetail.Email, &authUserDetail.Password, &authUserDetail.EmailVerifiedAt, &authUserDetail.LoginProvider, &authUserDetail.TOTPEnabledAt, &authUserDetail.RequireMFA, &authUserDetail.RoleID, &authUserDetail.RoleName)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -118,3 +147,477 @@ func (ar *AuthRepository) GetUserByEmail(email string) (*model.AuthUserDetails,
 
 	return &authUserDetail, nil
 }
+
+// GetUserByID repository layer for querying command getting any user by id
+func (ar *AuthRepository) GetUserByID(id uuid.UUID) (*model.AuthUserDetails, error) {
+	var authUserDetail model.AuthUserDetails
+
+	q := `SELECT 
+		u.id AS user_id,
+		u.full_name,
+		u.email,
+		u.password,
+		u.email_verified_at,
+		u.login_provider,
+		u.totp_enabled_at,
+		COALESCE(r.require_mfa, false),
+		r.id,
+		r.name FROM "users" u 
+		LEFT JOIN roles r ON r.id = u.role_id 
+		WHERE u.id = $1 AND u.deleted_at IS NULL
+	`
+
+	row := ar.DB.QueryRow(ar.Context, q, id)
+	err := row.Scan(&authUserDetail.UserID, &authUserDetail.FullName, &authUserDetail.Email, &authUserDetail.Password, &authUserDetail.EmailVerifiedAt, &authUserDetail.LoginProvider, &authUserDetail.TOTPEnabledAt, &authUserDetail.RequireMFA, &authUserDetail.RoleID, &authUserDetail.RoleName)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			ar.Logger.Info(fmt.Errorf("AuthRepository.GetUserByID INFO : %v MSG : %s", err, err.Error()))
+		} else {
+			ar.Logger.Error(fmt.Errorf("AuthRepository.GetUserByID ERROR : %v MSG : %s", err, err.Error()))
+		}
+
+		return nil, err
+	}
+
+	return &authUserDetail, nil
+}
+
+// CreateRefreshToken repository layer for executing command storing a hashed refresh token
+func (ar *AuthRepository) CreateRefreshToken(userID uuid.UUID, tokenHash string, expiredAt time.Time) error {
+	q := `INSERT INTO "refresh_tokens" (user_id,token_hash,expired_at) VALUES ($1,$2,$3)`
+
+	_, err := ar.DB.Exec(ar.Context, q, userID, tokenHash, expiredAt)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.CreateRefreshToken Exec ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// GetRefreshTokenByHash repository layer for querying command getting a refresh token by its hash
+func (ar *AuthRepository) GetRefreshTokenByHash(tokenHash string) (*model.RefreshToken, error) {
+	var refreshToken model.RefreshToken
+
+	q := `SELECT 
+		id,
+		user_id,
+		token_hash,
+		expired_at,
+		revoked_at,
+		replaced_by
+		FROM "refresh_tokens"
+		WHERE token_hash = $1
+	`
+
+	row := ar.DB.QueryRow(ar.Context, q, tokenHash)
+	err := row.Scan(&refreshToken.ID, &refreshToken.UserID, &refreshToken.TokenHash, &refreshToken.ExpiredAt, &refreshToken.RevokedAt, &refreshToken.ReplacedBy)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			ar.Logger.Info(fmt.Errorf("AuthRepository.GetRefreshTokenByHash INFO : %v MSG : %s", err, err.Error()))
+		} else {
+			ar.Logger.Error(fmt.Errorf("AuthRepository.GetRefreshTokenByHash ERROR : %v MSG : %s", err, err.Error()))
+		}
+
+		return nil, err
+	}
+
+	return &refreshToken, nil
+}
+
+// RotateRefreshToken repository layer for executing command revoking old refresh token and storing the replacement
+func (ar *AuthRepository) RotateRefreshToken(oldID uuid.UUID, userID uuid.UUID, tokenHash string, expiredAt time.Time) error {
+	tx, err := ar.DB.Begin(ar.Context)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.RotateRefreshToken Begin ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	q := `INSERT INTO "refresh_tokens" (user_id,token_hash,expired_at) VALUES ($1,$2,$3) RETURNING id`
+
+	var newID uuid.UUID
+
+	err = tx.QueryRow(ar.Context, q, userID, tokenHash, expiredAt).Scan(&newID)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.RotateRefreshToken.QueryRow Scan ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(ar.Context); errRollback != nil {
+			ar.Logger.Error(fmt.Errorf("AuthRepository.RotateRefreshToken.QueryRow Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	// revoked_at IS NULL guard makes concurrent rotation of the same token fail on one side
+	q2 := `UPDATE "refresh_tokens" SET revoked_at = now(), replaced_by = $1 WHERE id = $2 AND revoked_at IS NULL`
+
+	tag, err := tx.Exec(ar.Context, q2, newID, oldID)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = model.ErrInvalidRefreshToken
+	}
+
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.RotateRefreshToken.Exec ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(ar.Context); errRollback != nil {
+			ar.Logger.Error(fmt.Errorf("AuthRepository.RotateRefreshToken.Exec Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	err = tx.Commit(ar.Context)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.RotateRefreshToken Commit ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// RevokeRefreshTokenByHash repository layer for executing command revoking a refresh token owned by user
+func (ar *AuthRepository) RevokeRefreshTokenByHash(userID uuid.UUID, tokenHash string) error {
+	q := `UPDATE "refresh_tokens" SET revoked_at = now() WHERE user_id = $1 AND token_hash = $2 AND revoked_at IS NULL`
+
+	_, err := ar.DB.Exec(ar.Context, q, userID, tokenHash)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.RevokeRefreshTokenByHash Exec ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// RevokeAllRefreshTokenByUser repository layer for executing command revoking every active refresh token of a user
+func (ar *AuthRepository) RevokeAllRefreshTokenByUser(userID uuid.UUID) error {
+	q := `UPDATE "refresh_tokens" SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL`
+
+	_, err := ar.DB.Exec(ar.Context, q, userID)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.RevokeAllRefreshTokenByUser Exec ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// CreatePasswordReset repository layer for executing command storing a hashed reset token, invalidating the previous one
+func (ar *AuthRepository) CreatePasswordReset(userID uuid.UUID, tokenHash string, expiredAt time.Time) error {
+	tx, err := ar.DB.Begin(ar.Context)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.CreatePasswordReset Begin ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	q := `UPDATE "password_resets" SET used_at = now() WHERE user_id = $1 AND used_at IS NULL`
+	_, err = tx.Exec(ar.Context, q, userID)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.CreatePasswordReset.Exec Invalidate ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(ar.Context); errRollback != nil {
+			ar.Logger.Error(fmt.Errorf("AuthRepository.CreatePasswordReset.Exec Invalidate Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	q2 := `INSERT INTO "password_resets" (user_id,token_hash,expired_at) VALUES ($1,$2,$3)`
+	_, err = tx.Exec(ar.Context, q2, userID, tokenHash, expiredAt)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.CreatePasswordReset.Exec Insert ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(ar.Context); errRollback != nil {
+			ar.Logger.Error(fmt.Errorf("AuthRepository.CreatePasswordReset.Exec Insert Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	err = tx.Commit(ar.Context)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.CreatePasswordReset Commit ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// GetPasswordResetByHash repository layer for querying command getting a password reset by its hash
+func (ar *AuthRepository) GetPasswordResetByHash(tokenHash string) (*model.PasswordReset, error) {
+	var passwordReset model.PasswordReset
+
+	q := `SELECT 
+		id,
+		user_id,
+		expired_at,
+		used_at
+		FROM "password_resets"
+		WHERE token_hash = $1
+	`
+
+	row := ar.DB.QueryRow(ar.Context, q, tokenHash)
+	err := row.Scan(&passwordReset.ID, &passwordReset.UserID, &passwordReset.ExpiredAt, &passwordReset.UsedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			ar.Logger.Info(fmt.Errorf("AuthRepository.GetPasswordResetByHash INFO : %v MSG : %s", err, err.Error()))
+		} else {
+			ar.Logger.Error(fmt.Errorf("AuthRepository.GetPasswordResetByHash ERROR : %v MSG : %s", err, err.Error()))
+		}
+
+		return nil, err
+	}
+
+	return &passwordReset, nil
+}
+
+// ResetPassword repository layer for executing command consuming reset token, updating password and revoking sessions
+func (ar *AuthRepository) ResetPassword(resetID uuid.UUID, userID uuid.UUID, password string) error {
+	tx, err := ar.DB.Begin(ar.Context)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.ResetPassword Begin ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	// used_at IS NULL guard makes the token single-use even on concurrent request
+	q := `UPDATE "password_resets" SET used_at = now() WHERE id = $1 AND used_at IS NULL AND expired_at > now()`
+	tag, err := tx.Exec(ar.Context, q, resetID)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = model.ErrInvalidResetToken
+	}
+
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.ResetPassword.Exec Consume Token ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(ar.Context); errRollback != nil {
+			ar.Logger.Error(fmt.Errorf("AuthRepository.ResetPassword.Exec Consume Token Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	q2 := `UPDATE "users" SET password = $1, updated_at = now() WHERE id = $2`
+	_, err = tx.Exec(ar.Context, q2, password, userID)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.ResetPassword.Exec Password ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(ar.Context); errRollback != nil {
+			ar.Logger.Error(fmt.Errorf("AuthRepository.ResetPassword.Exec Password Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	q3 := `UPDATE "refresh_tokens" SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL`
+	_, err = tx.Exec(ar.Context, q3, userID)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.ResetPassword.Exec Refresh Token ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(ar.Context); errRollback != nil {
+			ar.Logger.Error(fmt.Errorf("AuthRepository.ResetPassword.Exec Refresh Token Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	err = tx.Commit(ar.Context)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.ResetPassword Commit ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// CreateEmailVerification repository layer for executing command storing a hashed verification token, invalidating the previous one
+func (ar *AuthRepository) CreateEmailVerification(userID uuid.UUID, tokenHash string, expiredAt time.Time) error {
+	tx, err := ar.DB.Begin(ar.Context)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.CreateEmailVerification Begin ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	q := `UPDATE "email_verifications" SET used_at = now() WHERE user_id = $1 AND used_at IS NULL`
+	_, err = tx.Exec(ar.Context, q, userID)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.CreateEmailVerification.Exec Invalidate ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(ar.Context); errRollback != nil {
+			ar.Logger.Error(fmt.Errorf("AuthRepository.CreateEmailVerification.Exec Invalidate Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	q2 := `INSERT INTO "email_verifications" (user_id,token_hash,expired_at) VALUES ($1,$2,$3)`
+	_, err = tx.Exec(ar.Context, q2, userID, tokenHash, expiredAt)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.CreateEmailVerification.Exec Insert ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(ar.Context); errRollback != nil {
+			ar.Logger.Error(fmt.Errorf("AuthRepository.CreateEmailVerification.Exec Insert Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	err = tx.Commit(ar.Context)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.CreateEmailVerification Commit ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// GetEmailVerificationByHash repository layer for querying command getting an email verification by its hash
+func (ar *AuthRepository) GetEmailVerificationByHash(tokenHash string) (*model.EmailVerification, error) {
+	var emailVerification model.EmailVerification
+
+	q := `SELECT 
+		id,
+		user_id,
+		expired_at,
+		used_at
+		FROM "email_verifications"
+		WHERE token_hash = $1
+	`
+
+	row := ar.DB.QueryRow(ar.Context, q, tokenHash)
+	err := row.Scan(&emailVerification.ID, &emailVerification.UserID, &emailVerification.ExpiredAt, &emailVerification.UsedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			ar.Logger.Info(fmt.Errorf("AuthRepository.GetEmailVerificationByHash INFO : %v MSG : %s", err, err.Error()))
+		} else {
+			ar.Logger.Error(fmt.Errorf("AuthRepository.GetEmailVerificationByHash ERROR : %v MSG : %s", err, err.Error()))
+		}
+
+		return nil, err
+	}
+
+	return &emailVerification, nil
+}
+
+// VerifyEmail repository layer for executing command consuming verification token and marking user email as verified
+func (ar *AuthRepository) VerifyEmail(verificationID uuid.UUID, userID uuid.UUID) error {
+	tx, err := ar.DB.Begin(ar.Context)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.VerifyEmail Begin ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	q := `UPDATE "email_verifications" SET used_at = now() WHERE id = $1 AND used_at IS NULL AND expired_at > now()`
+	tag, err := tx.Exec(ar.Context, q, verificationID)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = model.ErrInvalidVerificationToken
+	}
+
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.VerifyEmail.Exec Consume Token ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(ar.Context); errRollback != nil {
+			ar.Logger.Error(fmt.Errorf("AuthRepository.VerifyEmail.Exec Consume Token Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	q2 := `UPDATE "users" SET email_verified_at = now(), updated_at = now() WHERE id = $1 AND email_verified_at IS NULL`
+	_, err = tx.Exec(ar.Context, q2, userID)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.VerifyEmail.Exec User ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(ar.Context); errRollback != nil {
+			ar.Logger.Error(fmt.Errorf("AuthRepository.VerifyEmail.Exec User Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	err = tx.Commit(ar.Context)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.VerifyEmail Commit ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// GetUserByProviderSubject repository layer for querying command getting any user by oauth provider subject
+func (ar *AuthRepository) GetUserByProviderSubject(subject string) (*model.AuthUserDetails, error) {
+	var authUserDetail model.AuthUserDetails
+
+	q := `SELECT 
+		u.id AS user_id,
+		u.full_name,
+		u.email,
+		u.password,
+		u.email_verified_at,
+		u.login_provider,
+		u.totp_enabled_at,
+		COALESCE(r.require_mfa, false),
+		r.id,
+		r.name FROM "users" u 
+		LEFT JOIN roles r ON r.id = u.role_id 
+		WHERE u.provider_subject = $1 AND u.deleted_at IS NULL
+	`
+
+	row := ar.DB.QueryRow(ar.Context, q, subject)
+	err := row.Scan(&authUserDetail.UserID, &authUserDetail.FullName, &authUserDetail.Email, &authUserDetail.Password, &authUserDetail.EmailVerifiedAt, &authUserDetail.LoginProvider, &authUserDetail.TOTPEnabledAt, &authUserDetail.RequireMFA, &authUserDetail.RoleID, &authUserDetail.RoleName)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			ar.Logger.Info(fmt.Errorf("AuthRepository.GetUserByProviderSubject INFO : %v MSG : %s", err, err.Error()))
+		} else {
+			ar.Logger.Error(fmt.Errorf("AuthRepository.GetUserByProviderSubject ERROR : %v MSG : %s", err, err.Error()))
+		}
+
+		return nil, err
+	}
+
+	return &authUserDetail, nil
+}
+
+// LinkProviderSubject repository layer for executing command linking existing user into oauth provider subject
+func (ar *AuthRepository) LinkProviderSubject(userID uuid.UUID, subject string) error {
+	q := `UPDATE "users" 
+		SET provider_subject = $1,
+		email_verified_at = COALESCE(email_verified_at, now()),
+		updated_at = now()
+		WHERE id = $2
+	`
+
+	_, err := ar.DB.Exec(ar.Context, q, subject, userID)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.LinkProviderSubject Exec ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// CreateOAuthState repository layer for executing command storing oauth state & pkce verifier
+func (ar *AuthRepository) CreateOAuthState(state model.OAuthState) error {
+	q := `INSERT INTO "oauth_states" (state,code_verifier,location_id,telepon,is_seller,expired_at) VALUES ($1,$2,$3,$4,$5,$6)`
+
+	_, err := ar.DB.Exec(ar.Context, q, state.State, state.CodeVerifier, state.LocationID, state.Telepon, state.IsSeller, state.ExpiredAt)
+	if err != nil {
+		ar.Logger.Error(fmt.Errorf("AuthRepository.CreateOAuthState Exec ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// ConsumeOAuthState repository layer for executing command deleting an unexpired oauth state and returning its data
+func (ar *AuthRepository) ConsumeOAuthState(state string) (*model.OAuthState, error) {
+	var oauthState model.OAuthState
+
+	q := `DELETE FROM "oauth_states" 
+		WHERE state = $1 AND expired_at > now()
+		RETURNING state, code_verifier, location_id, COALESCE(telepon, ''), is_seller, expired_at
+	`
+
+	row := ar.DB.QueryRow(ar.Context, q, state)
+	err := row.Scan(&oauthState.State, &oauthState.CodeVerifier, &oauthState.LocationID, &oauthState.Telepon, &oauthState.IsSeller, &oauthState.ExpiredAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			ar.Logger.Info(fmt.Errorf("AuthRepository.ConsumeOAuthState INFO : %v MSG : %s", err, err.Error()))
+		} else {
+			ar.Logger.Error(fmt.Errorf("AuthRepository.ConsumeOAuthState ERROR : %v MSG : %s", err, err.Error()))
+		}
+
+		return nil, err
+	}
+
+	return &oauthState, nil
+}
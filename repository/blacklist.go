@@ -1 +1,202 @@
 package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/model"
+)
+
+const (
+	// activeBlacklistCriteria filtering lapak (aliased l) whose owner is currently blacklisted
+	activeBlacklistCriteria = ` NOT EXISTS (SELECT 1 FROM blacklist b WHERE b.user_id = l.user_id AND b.token_id IS NULL AND (b.expired_at IS NULL OR b.expired_at > now()))`
+)
+
+type (
+	// IBlacklistRepository is an interface that has all the function to be implemented inside blacklist repository
+	IBlacklistRepository interface {
+		RevokeToken(tokenID uuid.UUID, userID uuid.UUID, reason string, expiredAt time.Time) error
+		IsTokenRevoked(tokenID uuid.UUID) (bool, error)
+		BlacklistUser(userID uuid.UUID, reason string, expiredAt *time.Time, createdBy uuid.UUID) error
+		UnblacklistUser(userID uuid.UUID) error
+		GetAllBlacklist() ([]model.Blacklist, error)
+		GetActiveBlacklistByUser(userID uuid.UUID) (*model.Blacklist, error)
+	}
+
+	// BlacklistRepository is an app blacklist struct that consists of all the dependencies needed for blacklist repository
+	BlacklistRepository struct {
+		Context context.Context
+		Config  *config.Configuration
+		Logger  *logrus.Logger
+		DB      *pgx.Conn
+	}
+)
+
+// RevokeToken repository layer for executing command blacklisting a jwt token id
+func (br *BlacklistRepository) RevokeToken(tokenID uuid.UUID, userID uuid.UUID, reason string, expiredAt time.Time) error {
+	q := `INSERT INTO "blacklist" (token_id,user_id,reason,expired_at) VALUES ($1,$2,$3,$4) ON CONFLICT (token_id) DO NOTHING`
+
+	_, err := br.DB.Exec(br.Context, q, tokenID, userID, reason, expiredAt)
+	if err != nil {
+		br.Logger.Error(fmt.Errorf("BlacklistRepository.RevokeToken Exec ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// IsTokenRevoked repository layer for querying command checking a jwt token id is blacklisted or not
+func (br *BlacklistRepository) IsTokenRevoked(tokenID uuid.UUID) (bool, error) {
+	var revoked bool
+
+	q := `SELECT EXISTS (SELECT 1 FROM "blacklist" WHERE token_id = $1)`
+
+	err := br.DB.QueryRow(br.Context, q, tokenID).Scan(&revoked)
+	if err != nil {
+		br.Logger.Error(fmt.Errorf("BlacklistRepository.IsTokenRevoked ERROR : %v MSG : %s", err, err.Error()))
+		return false, err
+	}
+
+	return revoked, nil
+}
+
+// BlacklistUser repository layer for executing command banning a user and closing all of their lapak
+func (br *BlacklistRepository) BlacklistUser(userID uuid.UUID, reason string, expiredAt *time.Time, createdBy uuid.UUID) error {
+	tx, err := br.DB.Begin(br.Context)
+	if err != nil {
+		br.Logger.Error(fmt.Errorf("BlacklistRepository.BlacklistUser Begin ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	q := `INSERT INTO "blacklist" (user_id,reason,expired_at,created_by) VALUES ($1,$2,$3,$4)`
+	_, err = tx.Exec(br.Context, q, userID, reason, expiredAt, createdBy)
+	if err != nil {
+		br.Logger.Error(fmt.Errorf("BlacklistRepository.BlacklistUser.Exec Blacklist ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(br.Context); errRollback != nil {
+			br.Logger.Error(fmt.Errorf("BlacklistRepository.BlacklistUser.Exec Blacklist Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	// banned seller must not keep receiving order
+	q2 := `UPDATE "lapak" SET status = 'closed', updated_at = now() WHERE user_id = $1`
+	_, err = tx.Exec(br.Context, q2, userID)
+	if err != nil {
+		br.Logger.Error(fmt.Errorf("BlacklistRepository.BlacklistUser.Exec Lapak ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(br.Context); errRollback != nil {
+			br.Logger.Error(fmt.Errorf("BlacklistRepository.BlacklistUser.Exec Lapak Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	q3 := `UPDATE "refresh_tokens" SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL`
+	_, err = tx.Exec(br.Context, q3, userID)
+	if err != nil {
+		br.Logger.Error(fmt.Errorf("BlacklistRepository.BlacklistUser.Exec Refresh Token ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(br.Context); errRollback != nil {
+			br.Logger.Error(fmt.Errorf("BlacklistRepository.BlacklistUser.Exec Refresh Token Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	err = tx.Commit(br.Context)
+	if err != nil {
+		br.Logger.Error(fmt.Errorf("BlacklistRepository.BlacklistUser Commit ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// UnblacklistUser repository layer for executing command lifting every ban of a user
+func (br *BlacklistRepository) UnblacklistUser(userID uuid.UUID) error {
+	q := `DELETE FROM "blacklist" WHERE user_id = $1 AND token_id IS NULL`
+
+	_, err := br.DB.Exec(br.Context, q, userID)
+	if err != nil {
+		br.Logger.Error(fmt.Errorf("BlacklistRepository.UnblacklistUser Exec ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// GetAllBlacklist repository layer for querying command getting all active ban
+func (br *BlacklistRepository) GetAllBlacklist() ([]model.Blacklist, error) {
+	q := `SELECT b.id,
+		b.user_id,
+		u.full_name,
+		u.email,
+		b.reason,
+		b.expired_at,
+		b.created_by,
+		b.created_at
+		FROM "blacklist" b
+		LEFT JOIN users u ON u.id = b.user_id
+		WHERE b.token_id IS NULL AND (b.expired_at IS NULL OR b.expired_at > now())
+		ORDER BY b.created_at DESC
+	`
+
+	rows, err := br.DB.Query(br.Context, q)
+	if err != nil {
+		br.Logger.Error(fmt.Errorf("BlacklistRepository.GetAllBlacklist Query ERROR %v MSG %s", err, err.Error()))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var listData []model.Blacklist
+	for rows.Next() {
+		data := &model.Blacklist{}
+		err := rows.Scan(&data.ID, &data.UserID, &data.FullName, &data.Email, &data.Reason, &data.ExpiredAt, &data.CreatedBy, &data.CreatedAt)
+		if err != nil {
+			br.Logger.Error(fmt.Errorf("BlacklistRepository.GetAllBlacklist rows.Next Scan ERROR %v MSG %s", err, err.Error()))
+			return nil, err
+		}
+
+		listData = append(listData, *data)
+	}
+
+	return listData, nil
+}
+
+// GetActiveBlacklistByUser repository layer for querying command getting latest active ban of a user
+func (br *BlacklistRepository) GetActiveBlacklistByUser(userID uuid.UUID) (*model.Blacklist, error) {
+	var blacklist model.Blacklist
+
+	q := `SELECT b.id,
+		b.user_id,
+		u.full_name,
+		u.email,
+		b.reason,
+		b.expired_at,
+		b.created_by,
+		b.created_at
+		FROM "blacklist" b
+		LEFT JOIN users u ON u.id = b.user_id
+		WHERE b.user_id = $1 AND b.token_id IS NULL AND (b.expired_at IS NULL OR b.expired_at > now())
+		ORDER BY b.created_at DESC
+		LIMIT 1
+	`
+
+	row := br.DB.QueryRow(br.Context, q, userID)
+	err := row.Scan(&blacklist.ID, &blacklist.UserID, &blacklist.FullName, &blacklist.Email, &blacklist.Reason, &blacklist.ExpiredAt, &blacklist.CreatedBy, &blacklist.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			br.Logger.Info(fmt.Errorf("BlacklistRepository.GetActiveBlacklistByUser INFO : %v MSG : %s", err, err.Error()))
+		} else {
+			br.Logger.Error(fmt.Errorf("BlacklistRepository.GetActiveBlacklistByUser ERROR : %v MSG : %s", err, err.Error()))
+		}
+
+		return nil, err
+	}
+
+	return &blacklist, nil
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/model"
+)
+
+type (
+	// ICartRepository is an interface that has all the function to be implemented inside cart repository
+	ICartRepository interface {
+		GetCartByUserID(userID uuid.UUID) ([]model.CartItem, error)
+		GetCartItem(userID uuid.UUID, productID uuid.UUID) (*model.CartItem, error)
+		AddCartItem(userID uuid.UUID, productID uuid.UUID, qty int) error
+		UpdateCartItem(userID uuid.UUID, productID uuid.UUID, qty int) error
+		DeleteCartItem(userID uuid.UUID, productID uuid.UUID) error
+	}
+
+	// CartRepository is an app cart struct that consists of all the dependencies needed for cart repository
+	CartRepository struct {
+		Context context.Context
+		Config  *config.Configuration
+		Logger  *logrus.Logger
+		DB      *pgx.Conn
+	}
+)
+
+// GetCartByUserID repository layer for querying command getting every item inside cart of a user
+func (cr *CartRepository) GetCartByUserID(userID uuid.UUID) ([]model.CartItem, error) {
+	q := `SELECT c.product_id,
+		p.name,
+		p.stok,
+		p.lapak_id,
+		l.name,
+		c.qty,
+		COALESCE(p.price, 0),
+		COALESCE(p.price, 0) * c.qty,
+		c.created_at,
+		c.updated_at
+		FROM cart_items c
+		JOIN products p ON p.id = c.product_id
+		JOIN lapak l ON l.id = p.lapak_id
+		WHERE c.user_id = $1
+		ORDER BY l.name ASC, c.created_at ASC
+	`
+
+	rows, err := cr.DB.Query(cr.Context, q, userID)
+	if err != nil {
+		cr.Logger.Error(fmt.Errorf("CartRepository.GetCartByUserID Query ERROR %v MSG %s", err, err.Error()))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var listData []model.CartItem
+	for rows.Next() {
+		data := &model.CartItem{}
+		err := rows.Scan(
+			&data.ProductID,
+			&data.ProductName,
+			&data.Stock,
+			&data.LapakID,
+			&data.LapakName,
+			&data.QTY,
+			&data.Price,
+			&data.Subtotal,
+			&data.CreatedAt,
+			&data.UpdatedAt,
+		)
+		if err != nil {
+			cr.Logger.Error(fmt.Errorf("CartRepository.GetCartByUserID rows.Next Scan ERROR %v MSG %s", err, err.Error()))
+			return nil, err
+		}
+
+		listData = append(listData, *data)
+	}
+
+	return listData, nil
+}
+
+// GetCartItem repository layer for querying command getting one product inside cart of a user
+func (cr *CartRepository) GetCartItem(userID uuid.UUID, productID uuid.UUID) (*model.CartItem, error) {
+	var data model.CartItem
+
+	q := `SELECT c.product_id,
+		p.name,
+		p.stok,
+		p.lapak_id,
+		l.name,
+		c.qty,
+		COALESCE(p.price, 0),
+		COALESCE(p.price, 0) * c.qty,
+		c.created_at,
+		c.updated_at
+		FROM cart_items c
+		JOIN products p ON p.id = c.product_id
+		JOIN lapak l ON l.id = p.lapak_id
+		WHERE c.user_id = $1 AND c.product_id = $2
+	`
+
+	err := cr.DB.QueryRow(cr.Context, q, userID, productID).Scan(
+		&data.ProductID,
+		&data.ProductName,
+		&data.Stock,
+		&data.LapakID,
+		&data.LapakName,
+		&data.QTY,
+		&data.Price,
+		&data.Subtotal,
+		&data.CreatedAt,
+		&data.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			cr.Logger.Info(fmt.Errorf("CartRepository.GetCartItem INFO : %v MSG : %s", err, err.Error()))
+		} else {
+			cr.Logger.Error(fmt.Errorf("CartRepository.GetCartItem ERROR : %v MSG : %s", err, err.Error()))
+		}
+
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// AddCartItem repository layer for executing command adding a product into cart, summing its qty when already inside cart
+func (cr *CartRepository) AddCartItem(userID uuid.UUID, productID uuid.UUID, qty int) error {
+	q := `INSERT INTO cart_items (user_id,product_id,qty) VALUES ($1,$2,$3)
+		ON CONFLICT (user_id, product_id) DO UPDATE SET qty = cart_items.qty + EXCLUDED.qty, updated_at = now()
+	`
+
+	_, err := cr.DB.Exec(cr.Context, q, userID, productID, qty)
+	if err != nil {
+		cr.Logger.Error(fmt.Errorf("CartRepository.AddCartItem ERROR : %v MSG : %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// UpdateCartItem repository layer for executing command replacing qty of a product inside cart
+func (cr *CartRepository) UpdateCartItem(userID uuid.UUID, productID uuid.UUID, qty int) error {
+	q := `UPDATE cart_items SET qty = $1, updated_at = now() WHERE user_id = $2 AND product_id = $3`
+
+	tag, err := cr.DB.Exec(cr.Context, q, qty, userID, productID)
+	if err != nil {
+		cr.Logger.Error(fmt.Errorf("CartRepository.UpdateCartItem ERROR : %v MSG : %s", err, err.Error()))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return model.ErrCartItemNotFound
+	}
+
+	return nil
+}
+
+// DeleteCartItem repository layer for executing command removing a product from cart
+func (cr *CartRepository) DeleteCartItem(userID uuid.UUID, productID uuid.UUID) error {
+	q := `DELETE FROM cart_items WHERE user_id = $1 AND product_id = $2`
+
+	tag, err := cr.DB.Exec(cr.Context, q, userID, productID)
+	if err != nil {
+		cr.Logger.Error(fmt.Errorf("CartRepository.DeleteCartItem ERROR : %v MSG : %s", err, err.Error()))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return model.ErrCartItemNotFound
+	}
+
+	return nil
+}
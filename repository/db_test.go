@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+)
+
+// testDatabaseEnv names the DSN of a disposable, fully migrated database used by db-backed test,
+// such test is skipped when it is not set since purge & stock test change rows outside their own fixture
+const testDatabaseEnv = "TEST_DATABASE_URL"
+
+// testDSN responsible to returning test database DSN, skipping the test when it is not set
+func testDSN(t *testing.T) string {
+	t.Helper()
+
+	dsn := os.Getenv(testDatabaseEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set, skipping db-backed test", testDatabaseEnv)
+	}
+
+	return dsn
+}
+
+// testConnect responsible to opening a new connection into test database which is closed on cleanup
+func testConnect(t *testing.T, dsn string) *pgx.Conn {
+	t.Helper()
+
+	conn, err := pgx.Connect(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("connect test database: %v", err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close(context.Background())
+	})
+
+	return conn
+}
+
+// testLogger responsible to returning logger discarding every entry
+func testLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	return logger
+}
+
+// testFixture holds ids of rows inserted by a test so they are removed on cleanup
+type testFixture struct {
+	t    *testing.T
+	conn *pgx.Conn
+	rows map[string][]uuid.UUID
+}
+
+// newTestFixture responsible to creating fixture whose rows are deleted on cleanup
+func newTestFixture(t *testing.T, conn *pgx.Conn) *testFixture {
+	f := &testFixture{t: t, conn: conn, rows: map[string][]uuid.UUID{}}
+
+	t.Cleanup(func() {
+		// pemesanan created by code under test is not tracked, it is found through fixture lapak instead
+		if lapakIDs := f.rows["lapak"]; len(lapakIDs) > 0 {
+			for _, q := range []string{
+				`DELETE FROM pemesanan_status_history WHERE pemesanan_id IN (SELECT id FROM pemesanan WHERE lapak_id = ANY($1))`,
+				`DELETE FROM pemesanan_items WHERE pemesanan_id IN (SELECT id FROM pemesanan WHERE lapak_id = ANY($1))`,
+				`DELETE FROM pemesanan WHERE lapak_id = ANY($1)`,
+			} {
+				_, _ = conn.Exec(context.Background(), q, lapakIDs)
+			}
+		}
+
+		for _, table := range []string{"pemesanan_items", "pemesanan", "products", "lapak", "refresh_tokens", "users_profile", "users", "roles", "lokasi"} {
+			if ids := f.rows[table]; len(ids) > 0 {
+				_, _ = conn.Exec(context.Background(), `DELETE FROM "`+table+`" WHERE id = ANY($1)`, ids)
+			}
+		}
+	})
+
+	return f
+}
+
+// exec responsible to inserting a fixture row with RETURNING id, remembering it for cleanup
+func (f *testFixture) exec(table string, q string, args ...interface{}) uuid.UUID {
+	f.t.Helper()
+
+	var id uuid.UUID
+	if err := f.conn.QueryRow(context.Background(), q, args...).Scan(&id); err != nil {
+		f.t.Fatalf("insert %s fixture: %v", table, err)
+	}
+
+	f.rows[table] = append(f.rows[table], id)
+
+	return id
+}
+
+// user responsible to inserting a user, soft deleted when deletedDaysAgo is positive
+func (f *testFixture) user(deletedDaysAgo int) uuid.UUID {
+	return f.exec("users", `INSERT INTO "users" (full_name,email,password,role_id,deleted_at)
+		VALUES ('Fixture', $1, '-', $2, CASE WHEN $3 > 0 THEN now() - make_interval(days => $3) END) RETURNING id`,
+		uuid.NewString()+"@fixture.test", uuid.New(), deletedDaysAgo)
+}
+
+// profile responsible to inserting profile of user placed at location, pinned at coordinate when both are given
+func (f *testFixture) profile(userID uuid.UUID, locationID uuid.UUID, latitude *float64, longitude *float64) uuid.UUID {
+	return f.exec("users_profile", `INSERT INTO users_profile (telepon,user_id,location_id,latitude,longitude)
+		VALUES ('081234567890', $1, $2, $3, $4) RETURNING id`, userID, locationID, latitude, longitude)
+}
+
+// role responsible to inserting a role without any permission
+func (f *testFixture) role() uuid.UUID {
+	return f.exec("roles", `INSERT INTO roles (name) VALUES ($1) RETURNING id`, "Fixture "+uuid.NewString())
+}
+
+// refreshToken responsible to inserting an active refresh token of user
+func (f *testFixture) refreshToken(userID uuid.UUID) uuid.UUID {
+	return f.exec("refresh_tokens", `INSERT INTO refresh_tokens (user_id,token_hash,expired_at) VALUES ($1, $2, now() + interval '1 day') RETURNING id`,
+		userID, uuid.NewString())
+}
+
+// lapak responsible to inserting a lapak of user, soft deleted when deletedDaysAgo is positive
+func (f *testFixture) lapak(userID uuid.UUID, deletedDaysAgo int) uuid.UUID {
+	return f.exec("lapak", `INSERT INTO lapak (name,user_id,location_id,deleted_at)
+		VALUES ('Fixture', $1, $2, CASE WHEN $3 > 0 THEN now() - make_interval(days => $3) END) RETURNING id`,
+		userID, uuid.New(), deletedDaysAgo)
+}
+
+// product responsible to inserting a product of lapak with given stock, soft deleted when deletedDaysAgo is positive
+func (f *testFixture) product(lapakID uuid.UUID, stock int, deletedDaysAgo int) uuid.UUID {
+	return f.exec("products", `INSERT INTO products (name,stok,price,product_kategori,lapak_id,deleted_at)
+		VALUES ('Fixture', $1, 1000, 'makanan', $2, CASE WHEN $3 > 0 THEN now() - make_interval(days => $3) END) RETURNING id`,
+		stock, lapakID, deletedDaysAgo)
+}
+
+// location responsible to inserting a lokasi whose provinsi, kota & daerah are all Fixture
+func (f *testFixture) location() uuid.UUID {
+	return f.exec("lokasi", `INSERT INTO lokasi (provinsi,kota,daerah) VALUES ('Fixture', 'Fixture', 'Fixture') RETURNING id`)
+}
+
+// pemesanan responsible to inserting a done pemesanan of user holding a single item of product
+func (f *testFixture) pemesanan(userID uuid.UUID, lapakID uuid.UUID, productID uuid.UUID) uuid.UUID {
+	id := f.exec("pemesanan", `INSERT INTO pemesanan (name,status,user_id,lapak_id,qty)
+		VALUES ('Fixture', 'done', $1, $2, 1) RETURNING id`, userID, lapakID)
+
+	f.exec("pemesanan_items", `INSERT INTO pemesanan_items (pemesanan_id,product_id,qty) VALUES ($1, $2, 1) RETURNING id`, id, productID)
+
+	return id
+}
+
+// exists responsible to checking whether a row is still inside table
+func (f *testFixture) exists(table string, id uuid.UUID) bool {
+	f.t.Helper()
+
+	var found bool
+	if err := f.conn.QueryRow(context.Background(), `SELECT EXISTS (SELECT 1 FROM "`+table+`" WHERE id = $1)`, id).Scan(&found); err != nil {
+		f.t.Fatalf("check %s: %v", table, err)
+	}
+
+	return found
+}
+
+// testRepositoryDeps responsible to returning shared dependencies of repository under test
+func testRepositoryDeps() (context.Context, *config.Configuration, *logrus.Logger) {
+	return context.Background(), &config.Configuration{Const: &config.Constants{}}, testLogger()
+}
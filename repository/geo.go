@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/wiormiw/GrowBaks/model"
+)
+
+// earthRadiusKM is mean earth radius used by haversine distance
+const earthRadiusKM = 6371.0
+
+// haversineKM responsible to building SQL expression of distance in kilometer between given coordinate columns & a point,
+// the expression holds 3 placeholder bound to latitude, latitude & longitude of the point in order
+func haversineKM(latColumn string, lngColumn string) string {
+	return `(2 * ` + strconv.FormatFloat(earthRadiusKM, 'f', -1, 64) + ` * ASIN(LEAST(1.0, SQRT(
+		POWER(SIN(RADIANS(` + latColumn + ` - ?) / 2), 2) +
+		COS(RADIANS(?)) * COS(RADIANS(` + latColumn + `)) * POWER(SIN(RADIANS(` + lngColumn + ` - ?) / 2), 2)))))`
+}
+
+// haversineArgs responsible to returning args of haversineKM expression for given point
+func haversineArgs(near model.GeoRadius) []interface{} {
+	return []interface{}{near.Latitude, near.Latitude, near.Longitude}
+}
+
+// boundingBox responsible to returning min & max latitude and longitude enclosing the radius, used for index friendly prefilter
+func boundingBox(near model.GeoRadius) (minLat float64, maxLat float64, minLng float64, maxLng float64) {
+	latDelta := near.RadiusKM / earthRadiusKM * 180 / math.Pi
+
+	// longitude degree shrinks toward the pole, box covers every longitude when too close to it
+	lngDelta := 180.0
+	if cos := math.Cos(near.Latitude * math.Pi / 180); cos > 0.01 {
+		lngDelta = math.Min(180, latDelta/cos)
+	}
+
+	return near.Latitude - latDelta, near.Latitude + latDelta, near.Longitude - lngDelta, near.Longitude + lngDelta
+}
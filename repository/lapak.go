@@ -3,22 +3,32 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4"
 	"github.com/sirupsen/logrus"
 	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/helper"
 	"github.com/wiormiw/GrowBaks/model"
 )
 
 type (
 	ILapakRepository interface {
-		GetAllLapak(search string) ([]model.Lapak, error)
-		GetAllLapakByLocation(search string, daerah string) ([]model.Lapak, error)
+		GetAllLapak(search string, includeDeleted bool, page model.PageRequest) ([]model.Lapak, int, error)
+		GetAllLapakByLocation(search string, daerah string, includeDeleted bool, page model.PageRequest) ([]model.Lapak, int, error)
 		GetLapakByID(id uuid.UUID) (*model.Lapak, error)
-		UpdateByID(id uuid.UUID, nama_lapak string, status string) error
+		GetLapakByUser(userID uuid.UUID) ([]model.Lapak, error)
+		CreateLapak(userID uuid.UUID, req model.LapakCreateRequest, locationID uuid.UUID, maxLapak *int) (uuid.UUID, error)
+		UpdateByID(id uuid.UUID, fields map[string]interface{}) error
+		GetProductCounts(id uuid.UUID) (int, int, error)
+		GetNearbyLapak(near model.GeoRadius, page model.PageRequest) ([]model.Lapak, int, error)
 		UpdateStatusByID(id uuid.UUID, status string) error
 		DeleteByID(id uuid.UUID) error
+		RestoreByID(id uuid.UUID) error
+		RelocateByID(id uuid.UUID, locationID uuid.UUID, latitude *float64, longitude *float64, actorID uuid.UUID, actorRole string) error
+		GetLocationHistory(id uuid.UUID) ([]model.LapakLocationHistory, error)
+		GetOpenOrderBuyers(id uuid.UUID) ([]model.LapakBuyer, error)
 	}
 
 	LapakRepository struct {
@@ -29,42 +39,60 @@ type (
 	}
 )
 
+// lapakSortColumns whitelist sortable column of lapak list
+var lapakSortColumns = map[string]string{
+	"name":       "l.name",
+	"status":     "l.status",
+	"created_at": "l.created_at",
+}
+
 // GetAllLapak
-func (lapr *LapakRepository) GetAllLapak(search string) ([]model.Lapak, error) {
+func (lapr *LapakRepository) GetAllLapak(search string, includeDeleted bool, page model.PageRequest) ([]model.Lapak, int, error) {
 	q := `SELECT u.id AS user_id, 
 		u.full_name, 
 		l.id as lapak_id,
 		l.name as lapak_name,
 		l.status, 
 		loc.id as lokasi_id,
-		loc.daerah 
+		loc.daerah,
+		l.deleted_at,
+		l.latitude,
+		l.longitude,
+		COALESCE(l.description, '') AS description
 		FROM "lapak" l 
 		LEFT JOIN users u ON l.user_id = u.id 
 		LEFT JOIN lokasi loc ON loc.id = l.location_id
 	`
 
-	criteria := ""
-	criteria = ""
+	qb := helper.NewQuerySelectBuilder(q).Where(activeBlacklistCriteria)
+
+	if !includeDeleted {
+		qb.Where("l.deleted_at IS NULL")
+	}
 
 	if len(search) > 0 {
-		criteria += " l.name ILIKE '%" + search + "%'"
+		qb.Where("l.name ILIKE ?", helper.LikeContains(search))
 	}
 
-	cr := ""
-	if len(criteria) > 0 {
-		cr = " WHERE " + criteria
+	total, err := countRows(lapr.Context, lapr.DB, qb)
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.GetAllLapak Count ERROR %v MSG %s", err, err.Error()))
+		return nil, 0, err
 	}
 
-	if cr != "" {
-		q += cr
+	err = paginate(qb, page, lapakSortColumns, "l.id")
+	if err != nil {
+		return nil, 0, err
 	}
 
+	q, args := qb.Build()
+
 	lapr.Logger.Info(fmt.Sprintf("Query : %s", q))
 
-	rows, err := lapr.DB.Query(lapr.Context, q)
+	rows, err := lapr.DB.Query(lapr.Context, q, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	var listData []model.Lapak
@@ -76,64 +104,72 @@ func (lapr *LapakRepository) GetAllLapak(search string) ([]model.Lapak, error) {
 			&data.LapakName,
 			&data.Status,
 			&data.LocationID,
-			&data.Daerah)
+			&data.Daerah,
+			&data.DeletedAt,
+			&data.Latitude,
+			&data.Longitude,
+			&data.Description)
 		if err != nil {
 			lapr.Logger.Error(fmt.Errorf("LapakRepository.GetAllLapak rows.Next Scan ERROR %v MSG %s", err, err.Error()))
-			return nil, err
+			return nil, 0, err
 		}
 
 		listData = append(listData, *data)
 	}
 
-	return listData, nil
+	return listData, total, nil
 }
 
-func (lapr *LapakRepository) GetAllLapakByLocation(search string, daerah string) ([]model.Lapak, error) {
+func (lapr *LapakRepository) GetAllLapakByLocation(search string, daerah string, includeDeleted bool, page model.PageRequest) ([]model.Lapak, int, error) {
 	q := `SELECT u.id AS user_id, 
 		u.full_name, 
 		l.id as lapak_id,
 		l.name as lapak_name,
 		l.status, 
 		loc.id as lokasi_id,
-		loc.daerah 
+		loc.daerah,
+		l.deleted_at,
+		l.latitude,
+		l.longitude,
+		COALESCE(l.description, '') AS description
 		FROM "lapak" l 
 		LEFT JOIN users u ON l.user_id = u.id 
 		LEFT JOIN lokasi loc ON loc.id = l.location_id
 	`
 
-	criteria := ""
-	criteria = ""
+	qb := helper.NewQuerySelectBuilder(q).Where(activeBlacklistCriteria)
 
-	if len(search) > 0 {
-		criteria += " l.name ILIKE '%" + search + "%'"
+	if !includeDeleted {
+		qb.Where("l.deleted_at IS NULL")
 	}
 
-	cr := ""
-	filterDaerah := ""
-	if len(criteria) > 0 {
-		cr = " WHERE " + criteria
+	if len(search) > 0 {
+		qb.Where("l.name ILIKE ?", helper.LikeContains(search))
 	}
 
-	if len(criteria) > 0 && daerah != "" {
-		filterDaerah = " AND daerah = '" + daerah + "'"
-	} else if len(criteria) < 1 && daerah != "" {
-		filterDaerah = " WHERE daerah = '" + daerah + "'"
+	if daerah != "" {
+		qb.Where("loc.daerah = ?", daerah)
 	}
 
-	if cr != "" {
-		q += cr
+	total, err := countRows(lapr.Context, lapr.DB, qb)
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.GetAllLapakByLocation Count ERROR %v MSG %s", err, err.Error()))
+		return nil, 0, err
 	}
 
-	if filterDaerah != "" {
-		q += filterDaerah
+	err = paginate(qb, page, lapakSortColumns, "l.id")
+	if err != nil {
+		return nil, 0, err
 	}
 
+	q, args := qb.Build()
+
 	lapr.Logger.Info(fmt.Sprintf("Query : %s", q))
 
-	rows, err := lapr.DB.Query(lapr.Context, q)
+	rows, err := lapr.DB.Query(lapr.Context, q, args...)
 
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	var listData []model.Lapak
@@ -145,16 +181,90 @@ func (lapr *LapakRepository) GetAllLapakByLocation(search string, daerah string)
 			&data.LapakName,
 			&data.Status,
 			&data.LocationID,
-			&data.Daerah)
+			&data.Daerah,
+			&data.DeletedAt,
+			&data.Latitude,
+			&data.Longitude,
+			&data.Description)
 		if err != nil {
 			lapr.Logger.Error(fmt.Errorf("LapakRepository.GetAllLapak rows.Next Scan ERROR %v MSG %s", err, err.Error()))
-			return nil, err
+			return nil, 0, err
 		}
 
 		listData = append(listData, *data)
 	}
 
-	return listData, nil
+	return listData, total, nil
+}
+
+// GetNearbyLapak repository layer for querying command getting lapak inside radius of a point, nearest first
+func (lapr *LapakRepository) GetNearbyLapak(near model.GeoRadius, page model.PageRequest) ([]model.Lapak, int, error) {
+	minLat, maxLat, minLng, maxLng := boundingBox(near)
+
+	q := `SELECT * FROM (
+		SELECT u.id AS user_id,
+		u.full_name,
+		l.id as lapak_id,
+		l.name as lapak_name,
+		l.status,
+		loc.id as lokasi_id,
+		loc.daerah,
+		l.deleted_at,
+		l.latitude,
+		l.longitude,
+		COALESCE(l.description, '') AS description,
+		` + haversineKM("l.latitude", "l.longitude") + ` AS distance_km
+		FROM "lapak" l
+		LEFT JOIN users u ON l.user_id = u.id
+		LEFT JOIN lokasi loc ON loc.id = l.location_id
+		WHERE l.deleted_at IS NULL AND` + activeBlacklistCriteria + `
+		AND l.latitude BETWEEN ? AND ? AND l.longitude BETWEEN ? AND ?
+	) AS nearby
+	`
+
+	args := append(haversineArgs(near), minLat, maxLat, minLng, maxLng)
+
+	qb := helper.NewQuerySelectBuilder(q, args...).Where("nearby.distance_km <= ?", near.RadiusKM)
+
+	total, err := countRows(lapr.Context, lapr.DB, qb)
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.GetNearbyLapak Count ERROR %v MSG %s", err, err.Error()))
+		return nil, 0, err
+	}
+
+	q, args = qb.OrderBy("nearby.distance_km ASC, nearby.lapak_id ASC").Limit(page.Size, (page.Page-1)*page.Size).Build()
+
+	rows, err := lapr.DB.Query(lapr.Context, q, args...)
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.GetNearbyLapak Query ERROR %v MSG %s", err, err.Error()))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var listData []model.Lapak
+	for rows.Next() {
+		data := &model.Lapak{}
+		err := rows.Scan(&data.UserID,
+			&data.FullName,
+			&data.LapakID,
+			&data.LapakName,
+			&data.Status,
+			&data.LocationID,
+			&data.Daerah,
+			&data.DeletedAt,
+			&data.Latitude,
+			&data.Longitude,
+			&data.Description,
+			&data.DistanceKM)
+		if err != nil {
+			lapr.Logger.Error(fmt.Errorf("LapakRepository.GetNearbyLapak rows.Next Scan ERROR %v MSG %s", err, err.Error()))
+			return nil, 0, err
+		}
+
+		listData = append(listData, *data)
+	}
+
+	return listData, total, nil
 }
 
 // GetLapakById
@@ -167,11 +277,20 @@ func (lapr *LapakRepository) GetLapakByID(id uuid.UUID) (*model.Lapak, error) {
 		l.name as lapak_name,
 		l.status, 
 		loc.id as lokasi_id,
-		loc.daerah 
+		loc.daerah,
+		l.deleted_at,
+		l.latitude,
+		l.longitude,
+		COALESCE(l.description, '') AS description,
+		COALESCE(l.banner_url, ''),
+		COALESCE(l.logo_url, ''),
+		COALESCE(l.whatsapp, ''),
+		COALESCE(l.address, ''),
+		COALESCE(l.landmark, '')
 		FROM "lapak" l 
 		LEFT JOIN users u ON l.user_id = u.id 
 		LEFT JOIN lokasi loc ON loc.id = l.location_id
-		WHERE l.id = $1
+		WHERE l.id = $1 AND l.deleted_at IS NULL
 	`
 
 	row := lapr.DB.QueryRow(lapr.Context, q, id)
@@ -181,7 +300,16 @@ func (lapr *LapakRepository) GetLapakByID(id uuid.UUID) (*model.Lapak, error) {
 		&lapak.LapakName,
 		&lapak.Status,
 		&lapak.LocationID,
-		&lapak.Daerah)
+		&lapak.Daerah,
+		&lapak.DeletedAt,
+		&lapak.Latitude,
+		&lapak.Longitude,
+		&lapak.Description,
+		&lapak.BannerURL,
+		&lapak.LogoURL,
+		&lapak.WhatsApp,
+		&lapak.Address,
+		&lapak.Landmark)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			lapr.Logger.Info(fmt.Errorf("LapakRepository.GetLapakByID INFO : %v MSG : %s", err, err.Error()))
@@ -195,27 +323,158 @@ func (lapr *LapakRepository) GetLapakByID(id uuid.UUID) (*model.Lapak, error) {
 	return &lapak, nil
 }
 
-// Edit Lapak
-func (lapr *LapakRepository) UpdateByID(id uuid.UUID, nama_lapak string, status string) error {
-	q := ` UPDATE lapak
-		SET name = $1,
-		status = $2
-	    WHERE id = $3
-	`
-	_, err := lapr.DB.Exec(lapr.Context, q, nama_lapak, status, id)
+// Edit Lapak, updating only given columns
+func (lapr *LapakRepository) UpdateByID(id uuid.UUID, fields map[string]interface{}) error {
+	fields["id"] = id
+	fields["updated_at"] = time.Now()
+
+	q, args, err := helper.QueryUpdateBuilder("lapak", fields, []string{"id"})
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.UpdateByID QueryUpdateBuilder ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	q += " AND deleted_at IS NULL"
+
+	tag, err := lapr.DB.Exec(lapr.Context, q, args...)
 	if err != nil {
 		lapr.Logger.Error(fmt.Errorf("LapakRepository.UpdateByID Lapak ERROR : %v MSG : %s", err, err.Error()))
 		return err
 	}
 
+	if tag.RowsAffected() == 0 {
+		return model.ErrLapakNotFound
+	}
+
 	return nil
 }
 
+// GetProductCounts repository layer for querying command counting every product & in stock product of a lapak
+func (lapr *LapakRepository) GetProductCounts(id uuid.UUID) (int, int, error) {
+	var total, inStock int
+
+	q := `SELECT COUNT(*), COUNT(*) FILTER (WHERE stok > 0) FROM products WHERE lapak_id = $1 AND deleted_at IS NULL`
+
+	err := lapr.DB.QueryRow(lapr.Context, q, id).Scan(&total, &inStock)
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.GetProductCounts ERROR : %v MSG : %s", err, err.Error()))
+		return 0, 0, err
+	}
+
+	return total, inStock, nil
+}
+
+// GetLapakByUser repository layer for querying command getting every lapak owned by a user, oldest first
+func (lapr *LapakRepository) GetLapakByUser(userID uuid.UUID) ([]model.Lapak, error) {
+	q := `SELECT u.id AS user_id,
+		u.full_name,
+		l.id as lapak_id,
+		l.name as lapak_name,
+		l.status,
+		loc.id as lokasi_id,
+		loc.daerah,
+		l.deleted_at,
+		l.latitude,
+		l.longitude,
+		COALESCE(l.description, '') AS description
+		FROM "lapak" l
+		LEFT JOIN users u ON l.user_id = u.id
+		LEFT JOIN lokasi loc ON loc.id = l.location_id
+		WHERE l.user_id = $1 AND l.deleted_at IS NULL
+		ORDER BY l.created_at ASC, l.id ASC
+	`
+
+	rows, err := lapr.DB.Query(lapr.Context, q, userID)
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.GetLapakByUser Query ERROR %v MSG %s", err, err.Error()))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	listData := []model.Lapak{}
+	for rows.Next() {
+		data := &model.Lapak{}
+		err := rows.Scan(&data.UserID,
+			&data.FullName,
+			&data.LapakID,
+			&data.LapakName,
+			&data.Status,
+			&data.LocationID,
+			&data.Daerah,
+			&data.DeletedAt,
+			&data.Latitude,
+			&data.Longitude,
+			&data.Description)
+		if err != nil {
+			lapr.Logger.Error(fmt.Errorf("LapakRepository.GetLapakByUser rows.Next Scan ERROR %v MSG %s", err, err.Error()))
+			return nil, err
+		}
+
+		listData = append(listData, *data)
+	}
+
+	return listData, nil
+}
+
+// CreateLapak repository layer for executing command creating a lapak, returning pgx.ErrNoRows when user already owns maxLapak lapak
+func (lapr *LapakRepository) CreateLapak(userID uuid.UUID, req model.LapakCreateRequest, locationID uuid.UUID, maxLapak *int) (uuid.UUID, error) {
+	var lapakID uuid.UUID
+
+	tx, err := lapr.DB.Begin(lapr.Context)
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.CreateLapak Begin ERROR : %v MSG : %s", err, err.Error()))
+		return uuid.Nil, err
+	}
+
+	// under READ COMMITTED two requests could both count below the cap, locking the owner row serializes
+	// lapak creation per user so the count below sees every lapak committed by the previous request
+	q := `SELECT 1 FROM "users" WHERE id = $1 FOR UPDATE`
+	_, err = tx.Exec(lapr.Context, q, userID)
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.CreateLapak Exec Lock ERROR : %v MSG : %s", err, err.Error()))
+		if errRollback := tx.Rollback(lapr.Context); errRollback != nil {
+			lapr.Logger.Error(fmt.Errorf("LapakRepository.CreateLapak Exec Lock Rollback ERROR : %v MSG : %s", errRollback, errRollback.Error()))
+		}
+
+		return uuid.Nil, err
+	}
+
+	q2 := `INSERT INTO "lapak" (name,description,status,user_id,location_id,latitude,longitude)
+		SELECT $1, NULLIF($2, ''), $3, $4, $5, $6, $7
+		WHERE $8::INTEGER IS NULL OR (SELECT COUNT(*) FROM lapak WHERE user_id = $4 AND deleted_at IS NULL) < $8
+		RETURNING id
+	`
+
+	err = tx.QueryRow(lapr.Context, q2, req.Name, req.Description, req.Status, userID, locationID, req.Latitude, req.Longitude, maxLapak).Scan(&lapakID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			lapr.Logger.Info(fmt.Errorf("LapakRepository.CreateLapak INFO : %v MSG : %s", err, err.Error()))
+		} else {
+			lapr.Logger.Error(fmt.Errorf("LapakRepository.CreateLapak ERROR : %v MSG : %s", err, err.Error()))
+		}
+
+		if errRollback := tx.Rollback(lapr.Context); errRollback != nil {
+			lapr.Logger.Error(fmt.Errorf("LapakRepository.CreateLapak Rollback ERROR : %v MSG : %s", errRollback, errRollback.Error()))
+		}
+
+		return uuid.Nil, err
+	}
+
+	err = tx.Commit(lapr.Context)
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.CreateLapak Commit ERROR : %v MSG : %s", err, err.Error()))
+		return uuid.Nil, err
+	}
+
+	return lapakID, nil
+}
+
 // Edit Status Lapak
 func (lapr *LapakRepository) UpdateStatusByID(id uuid.UUID, status string) error {
 	q := ` UPDATE lapak
 		SET status = $1
-	    WHERE id = $2
+	    WHERE id = $2 AND deleted_at IS NULL
 	`
 	_, err := lapr.DB.Exec(lapr.Context, q, status, id)
 	if err != nil {
@@ -226,23 +485,252 @@ func (lapr *LapakRepository) UpdateStatusByID(id uuid.UUID, status string) error
 	return nil
 }
 
-// Delete Lapak By ID
+// Delete Lapak By ID, soft deleting the lapak along with its products
 func (lapr *LapakRepository) DeleteByID(id uuid.UUID) error {
-	q := `DELETE FROM lapak WHERE id = $1`
+	tx, err := lapr.DB.Begin(lapr.Context)
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.DeleteByID Begin ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	// now() is fixed for the whole transaction, restore matches cascaded products by this exact deleted_at
+	q := `UPDATE lapak SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL`
+	tag, err := tx.Exec(lapr.Context, q, id)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = model.ErrLapakNotFound
+	}
+
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.DeleteByID Exec Lapak ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(lapr.Context); errRollback != nil {
+			lapr.Logger.Error(fmt.Errorf("LapakRepository.DeleteByID Exec Lapak Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
 
-	_, err := lapr.DB.Exec(lapr.Context, q, id)
+	q2 := `UPDATE products SET deleted_at = now() WHERE lapak_id = $1 AND deleted_at IS NULL`
+	_, err = tx.Exec(lapr.Context, q2, id)
 	if err != nil {
-		lapr.Logger.Error(fmt.Errorf("LapakRepository.DeleteByID Exec ERROR %v MSG %s", err, err.Error()))
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.DeleteByID Exec Products ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(lapr.Context); errRollback != nil {
+			lapr.Logger.Error(fmt.Errorf("LapakRepository.DeleteByID Exec Products Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
 		return err
 	}
 
-	q2 := `DELETE FROM products WHERE lapak_id = $1`
+	q3 := `DELETE FROM cart_items WHERE product_id IN (SELECT id FROM products WHERE lapak_id = $1)`
+	_, err = tx.Exec(lapr.Context, q3, id)
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.DeleteByID Exec Cart ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(lapr.Context); errRollback != nil {
+			lapr.Logger.Error(fmt.Errorf("LapakRepository.DeleteByID Exec Cart Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
 
-	_, err = lapr.DB.Exec(lapr.Context, q2, id)
+	err = tx.Commit(lapr.Context)
 	if err != nil {
-		lapr.Logger.Error(fmt.Errorf("LapakRepository.DeleteByID Exec ERROR %v MSG %s", err, err.Error()))
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.DeleteByID Commit ERROR %v MSG %s", err, err.Error()))
 		return err
 	}
 
 	return nil
 }
+
+// RestoreByID repository layer for executing command restoring a soft deleted lapak along with products deleted together with it
+func (lapr *LapakRepository) RestoreByID(id uuid.UUID) error {
+	tx, err := lapr.DB.Begin(lapr.Context)
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.RestoreByID Begin ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	var deletedAt time.Time
+
+	// lapak of a deleted owner is restored through its owner instead
+	q := `SELECT l.deleted_at FROM lapak l
+		JOIN users u ON u.id = l.user_id AND u.deleted_at IS NULL
+		WHERE l.id = $1 AND l.deleted_at IS NOT NULL
+		FOR UPDATE OF l
+	`
+	err = tx.QueryRow(lapr.Context, q, id).Scan(&deletedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = model.ErrLapakNotFound
+		}
+
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.RestoreByID QueryRow Lapak ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(lapr.Context); errRollback != nil {
+			lapr.Logger.Error(fmt.Errorf("LapakRepository.RestoreByID QueryRow Lapak Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	q2 := `UPDATE lapak SET deleted_at = NULL WHERE id = $1`
+	_, err = tx.Exec(lapr.Context, q2, id)
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.RestoreByID Exec Lapak ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(lapr.Context); errRollback != nil {
+			lapr.Logger.Error(fmt.Errorf("LapakRepository.RestoreByID Exec Lapak Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	q3 := `UPDATE products SET deleted_at = NULL WHERE lapak_id = $1 AND deleted_at = $2`
+	_, err = tx.Exec(lapr.Context, q3, id, deletedAt)
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.RestoreByID Exec Products ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(lapr.Context); errRollback != nil {
+			lapr.Logger.Error(fmt.Errorf("LapakRepository.RestoreByID Exec Products Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	err = tx.Commit(lapr.Context)
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.RestoreByID Commit ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// RelocateByID repository layer for executing command moving a lapak into other location & recording its history
+func (lapr *LapakRepository) RelocateByID(id uuid.UUID, locationID uuid.UUID, latitude *float64, longitude *float64, actorID uuid.UUID, actorRole string) error {
+	tx, err := lapr.DB.Begin(lapr.Context)
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.RelocateByID Begin ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	// history is written first so from_location_id still holds the old location
+	q := `INSERT INTO lapak_location_history (lapak_id,from_location_id,to_location_id,actor_id,actor_role)
+		SELECT id, location_id, $2, $3, $4 FROM lapak WHERE id = $1 AND deleted_at IS NULL
+	`
+	tag, err := tx.Exec(lapr.Context, q, id, locationID, actorID, actorRole)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = model.ErrLapakNotFound
+	}
+
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.RelocateByID Exec History ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(lapr.Context); errRollback != nil {
+			lapr.Logger.Error(fmt.Errorf("LapakRepository.RelocateByID Exec History Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	q2 := ` UPDATE lapak
+		SET location_id = $1,
+		latitude = $2,
+		longitude = $3,
+		updated_at = now()
+	    WHERE id = $4 AND deleted_at IS NULL
+	`
+	_, err = tx.Exec(lapr.Context, q2, locationID, latitude, longitude, id)
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.RelocateByID Exec Lapak ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(lapr.Context); errRollback != nil {
+			lapr.Logger.Error(fmt.Errorf("LapakRepository.RelocateByID Exec Lapak Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	err = tx.Commit(lapr.Context)
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.RelocateByID Commit ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// GetLocationHistory repository layer for querying command getting every relocation of a lapak, newest first
+func (lapr *LapakRepository) GetLocationHistory(id uuid.UUID) ([]model.LapakLocationHistory, error) {
+	q := `SELECT
+		h.id,
+		h.from_location_id,
+		COALESCE(fl.daerah, ''),
+		h.to_location_id,
+		COALESCE(tl.daerah, ''),
+		h.actor_id,
+		h.actor_role,
+		h.created_at
+		FROM lapak_location_history h
+		LEFT JOIN lokasi fl ON fl.id = h.from_location_id
+		LEFT JOIN lokasi tl ON tl.id = h.to_location_id
+		WHERE h.lapak_id = $1
+		ORDER BY h.created_at DESC, h.id DESC
+	`
+
+	rows, err := lapr.DB.Query(lapr.Context, q, id)
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.GetLocationHistory Query ERROR %v MSG %s", err, err.Error()))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	listData := []model.LapakLocationHistory{}
+	for rows.Next() {
+		history := &model.LapakLocationHistory{}
+		err := rows.Scan(
+			&history.ID,
+			&history.FromLocationID,
+			&history.FromDaerah,
+			&history.ToLocationID,
+			&history.ToDaerah,
+			&history.ActorID,
+			&history.ActorRole,
+			&history.CreatedAt)
+		if err != nil {
+			lapr.Logger.Error(fmt.Errorf("LapakRepository.GetLocationHistory rows.Next Scan ERROR %v MSG %s", err, err.Error()))
+			return nil, err
+		}
+
+		listData = append(listData, *history)
+	}
+
+	return listData, nil
+}
+
+// GetOpenOrderBuyers repository layer for querying command getting every buyer having reserve or pending pemesanan on a lapak
+func (lapr *LapakRepository) GetOpenOrderBuyers(id uuid.UUID) ([]model.LapakBuyer, error) {
+	q := `SELECT DISTINCT
+		u.email,
+		u.full_name
+		FROM pemesanan pe
+		JOIN users u ON u.id = pe.user_id
+		WHERE pe.lapak_id = $1 AND pe.status IN ('reserve', 'pending') AND u.deleted_at IS NULL
+	`
+
+	rows, err := lapr.DB.Query(lapr.Context, q, id)
+	if err != nil {
+		lapr.Logger.Error(fmt.Errorf("LapakRepository.GetOpenOrderBuyers Query ERROR %v MSG %s", err, err.Error()))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var listData []model.LapakBuyer
+	for rows.Next() {
+		buyer := &model.LapakBuyer{}
+		err := rows.Scan(&buyer.Email, &buyer.FullName)
+		if err != nil {
+			lapr.Logger.Error(fmt.Errorf("LapakRepository.GetOpenOrderBuyers rows.Next Scan ERROR %v MSG %s", err, err.Error()))
+			return nil, err
+		}
+
+		listData = append(listData, *buyer)
+	}
+
+	return listData, nil
+}
@@ -8,14 +8,21 @@ import (
 	"github.com/jackc/pgx/v4"
 	"github.com/sirupsen/logrus"
 	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/helper"
 	"github.com/wiormiw/GrowBaks/model"
 )
 
 type (
 	// IAuthRepository is an interface that has all the function to be implemented inside auth repository
 	ILocationRepository interface {
-		GetAllLocation() ([]model.Location, error)
+		GetAllLocation(filter model.LocationFilter, page model.PageRequest) ([]model.Location, int, error)
+		GetAllProvinsi() ([]string, error)
+		GetAllKota(provinsi string) ([]string, error)
 		GetLocationByID(id uuid.UUID) (*model.Location, error)
+		CreateLocation(req model.LocationRequest) (uuid.UUID, error)
+		UpdateLocation(id uuid.UUID, req model.LocationRequest) error
+		DeleteLocation(id uuid.UUID) error
+		ImportLocation(rows []model.LocationRequest) (int, error)
 	}
 
 	// AuthRepository is an app auth struct that consists of all the dependencies needed for auth repository
@@ -27,32 +34,123 @@ type (
 	}
 )
 
+// locationSortColumns is whitelist of sort query into location column
+var locationSortColumns = map[string]string{
+	"provinsi": "provinsi",
+	"kota":     "kota",
+	"daerah":   "daerah",
+}
+
 // GetAllLocation
-func (lr *LocationRepository) GetAllLocation() ([]model.Location, error) {
+func (lr *LocationRepository) GetAllLocation(filter model.LocationFilter, page model.PageRequest) ([]model.Location, int, error) {
 	q := `SELECT 
 		id,
+		provinsi,
+		kota,
 		daerah
 		FROM "lokasi"
 	`
 
-	rows, err := lr.DB.Query(lr.Context, q)
+	qb := helper.NewQuerySelectBuilder(q)
+
+	if filter.Provinsi != "" {
+		qb.Where("lower(provinsi) = lower(?)", filter.Provinsi)
+	}
+
+	if filter.Kota != "" {
+		qb.Where("lower(kota) = lower(?)", filter.Kota)
+	}
+
+	if filter.Search != "" {
+		search := helper.LikeContains(filter.Search)
+		qb.Where("(daerah ILIKE ? OR kota ILIKE ? OR provinsi ILIKE ?)", search, search, search)
+	}
+
+	total, err := countRows(lr.Context, lr.DB, qb)
+	if err != nil {
+		lr.Logger.Error(fmt.Errorf("LocationRepository.GetAll Count ERROR %v MSG %s", err, err.Error()))
+		return nil, 0, err
+	}
+
+	if err := paginate(qb, page, locationSortColumns, "id"); err != nil {
+		return nil, 0, err
+	}
+
+	q, args := qb.Build()
+	rows, err := lr.DB.Query(lr.Context, q, args...)
 	if err != nil {
 		lr.Logger.Error(fmt.Errorf("LocationRepository.GetAll Query ERROR %v MSG %s", err, err.Error()))
-		return nil, err
+		return nil, 0, err
 	}
 
-	var listData []model.Location
+	defer rows.Close()
+
+	listData := []model.Location{}
 	for rows.Next() {
 		data := &model.Location{}
-		err := rows.Scan(&data.ID, &data.Daerah)
+		err := rows.Scan(&data.ID, &data.Provinsi, &data.Kota, &data.Daerah)
 		if err != nil {
 			lr.Logger.Error(fmt.Errorf("LocationRepository.GetAll rows.Next Scan ERROR %v MSG %s", err, err.Error()))
-			return nil, err
+			return nil, 0, err
 		}
 
 		listData = append(listData, *data)
 	}
 
+	return listData, total, nil
+}
+
+// GetAllProvinsi repository layer for querying command getting every distinct provinsi
+func (lr *LocationRepository) GetAllProvinsi() ([]string, error) {
+	q := `SELECT DISTINCT provinsi FROM "lokasi" ORDER BY provinsi ASC`
+
+	rows, err := lr.DB.Query(lr.Context, q)
+	if err != nil {
+		lr.Logger.Error(fmt.Errorf("LocationRepository.GetAllProvinsi Query ERROR %v MSG %s", err, err.Error()))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	listData := []string{}
+	for rows.Next() {
+		var provinsi string
+		err := rows.Scan(&provinsi)
+		if err != nil {
+			lr.Logger.Error(fmt.Errorf("LocationRepository.GetAllProvinsi rows.Next Scan ERROR %v MSG %s", err, err.Error()))
+			return nil, err
+		}
+
+		listData = append(listData, provinsi)
+	}
+
+	return listData, nil
+}
+
+// GetAllKota repository layer for querying command getting every distinct kota inside a provinsi
+func (lr *LocationRepository) GetAllKota(provinsi string) ([]string, error) {
+	q := `SELECT DISTINCT kota FROM "lokasi" WHERE lower(provinsi) = lower($1) ORDER BY kota ASC`
+
+	rows, err := lr.DB.Query(lr.Context, q, provinsi)
+	if err != nil {
+		lr.Logger.Error(fmt.Errorf("LocationRepository.GetAllKota Query ERROR %v MSG %s", err, err.Error()))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	listData := []string{}
+	for rows.Next() {
+		var kota string
+		err := rows.Scan(&kota)
+		if err != nil {
+			lr.Logger.Error(fmt.Errorf("LocationRepository.GetAllKota rows.Next Scan ERROR %v MSG %s", err, err.Error()))
+			return nil, err
+		}
+
+		listData = append(listData, kota)
+	}
+
 	return listData, nil
 }
 
@@ -62,13 +160,15 @@ func (lr *LocationRepository) GetLocationByID(id uuid.UUID) (*model.Location, er
 
 	q := `SELECT 
 		id,
+		provinsi,
+		kota,
 		daerah
 		FROM "lokasi" 
 		WHERE id = $1
 	`
 
 	row := lr.DB.QueryRow(lr.Context, q, id)
-	err := row.Scan(&location.ID, &location.Daerah)
+	err := row.Scan(&location.ID, &location.Provinsi, &location.Kota, &location.Daerah)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			lr.Logger.Info(fmt.Errorf("LocationRepository.GetLocationByID INFO : %v MSG : %s", err, err.Error()))
@@ -81,3 +181,100 @@ func (lr *LocationRepository) GetLocationByID(id uuid.UUID) (*model.Location, er
 
 	return &location, nil
 }
+
+// CreateLocation repository layer for executing command creating a location, returning pgx.ErrNoRows when location already existed
+func (lr *LocationRepository) CreateLocation(req model.LocationRequest) (uuid.UUID, error) {
+	var locationID uuid.UUID
+
+	q := `INSERT INTO "lokasi" (provinsi, kota, daerah) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING RETURNING id`
+
+	err := lr.DB.QueryRow(lr.Context, q, req.Provinsi, req.Kota, req.Daerah).Scan(&locationID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			lr.Logger.Info(fmt.Errorf("LocationRepository.CreateLocation INFO : %v MSG : %s", err, err.Error()))
+		} else {
+			lr.Logger.Error(fmt.Errorf("LocationRepository.CreateLocation ERROR : %v MSG : %s", err, err.Error()))
+		}
+
+		return uuid.Nil, err
+	}
+
+	return locationID, nil
+}
+
+// UpdateLocation repository layer for executing command updating a location which name is not used by other location
+func (lr *LocationRepository) UpdateLocation(id uuid.UUID, req model.LocationRequest) error {
+	q := `UPDATE "lokasi" SET provinsi = $1, kota = $2, daerah = $3
+		WHERE id = $4 AND NOT EXISTS (
+			SELECT 1 FROM "lokasi" o
+			WHERE o.id <> $4 AND lower(o.provinsi) = lower($1) AND lower(o.kota) = lower($2) AND lower(o.daerah) = lower($3)
+		)
+	`
+
+	tag, err := lr.DB.Exec(lr.Context, q, req.Provinsi, req.Kota, req.Daerah, id)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = model.ErrLocationExisted
+	}
+
+	if err != nil {
+		lr.Logger.Error(fmt.Errorf("LocationRepository.UpdateLocation Exec ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// DeleteLocation repository layer for executing command deleting a location which is not used by users or lapak
+func (lr *LocationRepository) DeleteLocation(id uuid.UUID) error {
+	// soft deleted lapak & users still point into location, so they are counted as well
+	q := `DELETE FROM "lokasi" WHERE id = $1
+		AND NOT EXISTS (SELECT 1 FROM lapak l WHERE l.location_id = $1)
+		AND NOT EXISTS (SELECT 1 FROM users_profile up WHERE up.location_id = $1)
+	`
+
+	tag, err := lr.DB.Exec(lr.Context, q, id)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = model.ErrLocationInUse
+	}
+
+	if err != nil {
+		lr.Logger.Error(fmt.Errorf("LocationRepository.DeleteLocation Exec ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// ImportLocation repository layer for executing command creating many location at once, existed location is skipped, returning number of created location
+func (lr *LocationRepository) ImportLocation(rows []model.LocationRequest) (int, error) {
+	tx, err := lr.DB.Begin(lr.Context)
+	if err != nil {
+		lr.Logger.Error(fmt.Errorf("LocationRepository.ImportLocation Begin ERROR %v MSG %s", err, err.Error()))
+		return 0, err
+	}
+
+	q := `INSERT INTO "lokasi" (provinsi, kota, daerah) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+
+	created := 0
+	for _, row := range rows {
+		tag, err := tx.Exec(lr.Context, q, row.Provinsi, row.Kota, row.Daerah)
+		if err != nil {
+			lr.Logger.Error(fmt.Errorf("LocationRepository.ImportLocation Exec ERROR %v MSG %s", err, err.Error()))
+			if errRollback := tx.Rollback(lr.Context); errRollback != nil {
+				lr.Logger.Error(fmt.Errorf("LocationRepository.ImportLocation Exec Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+			}
+
+			return 0, err
+		}
+
+		created += int(tag.RowsAffected())
+	}
+
+	err = tx.Commit(lr.Context)
+	if err != nil {
+		lr.Logger.Error(fmt.Errorf("LocationRepository.ImportLocation Commit ERROR %v MSG %s", err, err.Error()))
+		return 0, err
+	}
+
+	return created, nil
+}
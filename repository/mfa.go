@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/model"
+)
+
+type (
+	// IMFARepository is an interface that has all the function to be implemented inside mfa repository
+	IMFARepository interface {
+		GetUserMFA(userID uuid.UUID) (*model.UserMFA, error)
+		SetTOTPSecret(userID uuid.UUID, secret string) error
+		EnableTOTP(userID uuid.UUID, step int64, codeHashes []string) error
+		DisableTOTP(userID uuid.UUID) error
+		ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error
+		UseTOTPStep(userID uuid.UUID, step int64) (bool, error)
+		UseRecoveryCode(userID uuid.UUID, codeHash string) (bool, error)
+		CreateMFAChallenge(userID uuid.UUID, tokenHash string, expiredAt time.Time) error
+		GetMFAChallengeByHash(tokenHash string) (*model.MFAChallenge, error)
+		ConsumeMFAChallenge(challengeID uuid.UUID) error
+		IncrementMFAChallengeAttempt(challengeID uuid.UUID) error
+	}
+
+	// MFARepository is an app mfa struct that consists of all the dependencies needed for mfa repository
+	MFARepository struct {
+		Context context.Context
+		Config  *config.Configuration
+		Logger  *logrus.Logger
+		DB      *pgx.Conn
+	}
+)
+
+// GetUserMFA repository layer for querying command getting two-factor state of a user
+func (mr *MFARepository) GetUserMFA(userID uuid.UUID) (*model.UserMFA, error) {
+	var userMFA model.UserMFA
+
+	q := `SELECT u.id,
+		u.email,
+		u.totp_secret,
+		u.totp_enabled_at,
+		u.totp_last_step,
+		COALESCE(r.require_mfa, false)
+		FROM "users" u
+		LEFT JOIN roles r ON r.id = u.role_id
+		WHERE u.id = $1
+	`
+
+	row := mr.DB.QueryRow(mr.Context, q, userID)
+	err := row.Scan(&userMFA.UserID, &userMFA.Email, &userMFA.TOTPSecret, &userMFA.TOTPEnabledAt, &userMFA.TOTPLastStep, &userMFA.RequireMFA)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			mr.Logger.Info(fmt.Errorf("MFARepository.GetUserMFA INFO : %v MSG : %s", err, err.Error()))
+		} else {
+			mr.Logger.Error(fmt.Errorf("MFARepository.GetUserMFA ERROR : %v MSG : %s", err, err.Error()))
+		}
+
+		return nil, err
+	}
+
+	return &userMFA, nil
+}
+
+// SetTOTPSecret repository layer for executing command storing pending TOTP secret of a user
+func (mr *MFARepository) SetTOTPSecret(userID uuid.UUID, secret string) error {
+	q := `UPDATE "users" SET totp_secret = $1, updated_at = now() WHERE id = $2 AND totp_enabled_at IS NULL`
+
+	tag, err := mr.DB.Exec(mr.Context, q, secret, userID)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = model.ErrMFAAlreadyEnabled
+	}
+
+	if err != nil {
+		mr.Logger.Error(fmt.Errorf("MFARepository.SetTOTPSecret Exec ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// EnableTOTP repository layer for executing command enabling TOTP and storing its recovery codes
+func (mr *MFARepository) EnableTOTP(userID uuid.UUID, step int64, codeHashes []string) error {
+	tx, err := mr.DB.Begin(mr.Context)
+	if err != nil {
+		mr.Logger.Error(fmt.Errorf("MFARepository.EnableTOTP Begin ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	q := `UPDATE "users" SET totp_enabled_at = now(), totp_last_step = $1, updated_at = now() WHERE id = $2 AND totp_enabled_at IS NULL AND totp_secret IS NOT NULL`
+	tag, err := tx.Exec(mr.Context, q, step, userID)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = model.ErrMFAAlreadyEnabled
+	}
+
+	if err != nil {
+		mr.Logger.Error(fmt.Errorf("MFARepository.EnableTOTP.Exec Users ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(mr.Context); errRollback != nil {
+			mr.Logger.Error(fmt.Errorf("MFARepository.EnableTOTP.Exec Users Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	err = mr.replaceRecoveryCodes(tx, userID, codeHashes)
+	if err != nil {
+		mr.Logger.Error(fmt.Errorf("MFARepository.EnableTOTP.Exec Recovery Codes ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(mr.Context); errRollback != nil {
+			mr.Logger.Error(fmt.Errorf("MFARepository.EnableTOTP.Exec Recovery Codes Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	err = tx.Commit(mr.Context)
+	if err != nil {
+		mr.Logger.Error(fmt.Errorf("MFARepository.EnableTOTP Commit ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// DisableTOTP repository layer for executing command removing TOTP secret and recovery codes of a user
+func (mr *MFARepository) DisableTOTP(userID uuid.UUID) error {
+	tx, err := mr.DB.Begin(mr.Context)
+	if err != nil {
+		mr.Logger.Error(fmt.Errorf("MFARepository.DisableTOTP Begin ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	q := `UPDATE "users" SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = NULL, updated_at = now() WHERE id = $1`
+	_, err = tx.Exec(mr.Context, q, userID)
+	if err != nil {
+		mr.Logger.Error(fmt.Errorf("MFARepository.DisableTOTP.Exec Users ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(mr.Context); errRollback != nil {
+			mr.Logger.Error(fmt.Errorf("MFARepository.DisableTOTP.Exec Users Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	q2 := `DELETE FROM "recovery_codes" WHERE user_id = $1`
+	_, err = tx.Exec(mr.Context, q2, userID)
+	if err != nil {
+		mr.Logger.Error(fmt.Errorf("MFARepository.DisableTOTP.Exec Recovery Codes ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(mr.Context); errRollback != nil {
+			mr.Logger.Error(fmt.Errorf("MFARepository.DisableTOTP.Exec Recovery Codes Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	err = tx.Commit(mr.Context)
+	if err != nil {
+		mr.Logger.Error(fmt.Errorf("MFARepository.DisableTOTP Commit ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// ReplaceRecoveryCodes repository layer for executing command regenerating recovery codes of a user
+func (mr *MFARepository) ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error {
+	tx, err := mr.DB.Begin(mr.Context)
+	if err != nil {
+		mr.Logger.Error(fmt.Errorf("MFARepository.ReplaceRecoveryCodes Begin ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	err = mr.replaceRecoveryCodes(tx, userID, codeHashes)
+	if err != nil {
+		mr.Logger.Error(fmt.Errorf("MFARepository.ReplaceRecoveryCodes.Exec ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(mr.Context); errRollback != nil {
+			mr.Logger.Error(fmt.Errorf("MFARepository.ReplaceRecoveryCodes.Exec Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	err = tx.Commit(mr.Context)
+	if err != nil {
+		mr.Logger.Error(fmt.Errorf("MFARepository.ReplaceRecoveryCodes Commit ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// UseTOTPStep repository layer for executing command marking a TOTP time step as used, rejecting replayed code
+func (mr *MFARepository) UseTOTPStep(userID uuid.UUID, step int64) (bool, error) {
+	q := `UPDATE "users" SET totp_last_step = $1 WHERE id = $2 AND (totp_last_step IS NULL OR totp_last_step < $1)`
+
+	tag, err := mr.DB.Exec(mr.Context, q, step, userID)
+	if err != nil {
+		mr.Logger.Error(fmt.Errorf("MFARepository.UseTOTPStep Exec ERROR %v MSG %s", err, err.Error()))
+		return false, err
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// UseRecoveryCode repository layer for executing command consuming an unused recovery code
+func (mr *MFARepository) UseRecoveryCode(userID uuid.UUID, codeHash string) (bool, error) {
+	q := `UPDATE "recovery_codes" SET used_at = now() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+
+	tag, err := mr.DB.Exec(mr.Context, q, userID, codeHash)
+	if err != nil {
+		mr.Logger.Error(fmt.Errorf("MFARepository.UseRecoveryCode Exec ERROR %v MSG %s", err, err.Error()))
+		return false, err
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// CreateMFAChallenge repository layer for executing command storing a hashed login mfa challenge token
+func (mr *MFARepository) CreateMFAChallenge(userID uuid.UUID, tokenHash string, expiredAt time.Time) error {
+	q := `INSERT INTO "mfa_challenges" (user_id,token_hash,expired_at) VALUES ($1,$2,$3)`
+
+	_, err := mr.DB.Exec(mr.Context, q, userID, tokenHash, expiredAt)
+	if err != nil {
+		mr.Logger.Error(fmt.Errorf("MFARepository.CreateMFAChallenge Exec ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// GetMFAChallengeByHash repository layer for querying command getting login mfa challenge by token hash
+func (mr *MFARepository) GetMFAChallengeByHash(tokenHash string) (*model.MFAChallenge, error) {
+	var challenge model.MFAChallenge
+
+	q := `SELECT id,
+		user_id,
+		attempts,
+		expired_at,
+		used_at
+		FROM "mfa_challenges"
+		WHERE token_hash = $1
+	`
+
+	row := mr.DB.QueryRow(mr.Context, q, tokenHash)
+	err := row.Scan(&challenge.ID, &challenge.UserID, &challenge.Attempts, &challenge.ExpiredAt, &challenge.UsedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			mr.Logger.Info(fmt.Errorf("MFARepository.GetMFAChallengeByHash INFO : %v MSG : %s", err, err.Error()))
+		} else {
+			mr.Logger.Error(fmt.Errorf("MFARepository.GetMFAChallengeByHash ERROR : %v MSG : %s", err, err.Error()))
+		}
+
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+// ConsumeMFAChallenge repository layer for executing command marking login mfa challenge as used
+func (mr *MFARepository) ConsumeMFAChallenge(challengeID uuid.UUID) error {
+	// used_at IS NULL guard makes the challenge single-use even on concurrent request
+	q := `UPDATE "mfa_challenges" SET used_at = now() WHERE id = $1 AND used_at IS NULL AND expired_at > now()`
+
+	tag, err := mr.DB.Exec(mr.Context, q, challengeID)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = model.ErrInvalidMFAToken
+	}
+
+	if err != nil {
+		mr.Logger.Error(fmt.Errorf("MFARepository.ConsumeMFAChallenge Exec ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// IncrementMFAChallengeAttempt repository layer for executing command counting failed code of login mfa challenge
+func (mr *MFARepository) IncrementMFAChallengeAttempt(challengeID uuid.UUID) error {
+	q := `UPDATE "mfa_challenges" SET attempts = attempts + 1 WHERE id = $1`
+
+	_, err := mr.DB.Exec(mr.Context, q, challengeID)
+	if err != nil {
+		mr.Logger.Error(fmt.Errorf("MFARepository.IncrementMFAChallengeAttempt Exec ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// replaceRecoveryCodes will deleting old recovery codes and inserting new one inside given transaction
+func (mr *MFARepository) replaceRecoveryCodes(tx pgx.Tx, userID uuid.UUID, codeHashes []string) error {
+	q := `DELETE FROM "recovery_codes" WHERE user_id = $1`
+	_, err := tx.Exec(mr.Context, q, userID)
+	if err != nil {
+		return err
+	}
+
+	q2 := `INSERT INTO "recovery_codes" (user_id,code_hash) VALUES ($1,$2)`
+	for _, codeHash := range codeHashes {
+		_, err = tx.Exec(mr.Context, q2, userID, codeHash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
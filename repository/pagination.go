@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/wiormiw/GrowBaks/helper"
+	"github.com/wiormiw/GrowBaks/model"
+)
+
+// paginate responsible to applying whitelisted sort & page of request into select builder, tie broken by id to keep page stable
+func paginate(qb *helper.QuerySelectBuilder, page model.PageRequest, columns map[string]string, idColumn string) error {
+	column, ok := columns[page.Sort]
+	if !ok {
+		return model.ErrInvalidRequest
+	}
+
+	direction := " ASC"
+	if page.Order == "desc" {
+		direction = " DESC"
+	}
+
+	qb.OrderBy(column + direction + ", " + idColumn + direction)
+	qb.Limit(page.Size, (page.Page-1)*page.Size)
+
+	return nil
+}
+
+// countRows responsible to counting every row matched by select builder filter
+func countRows(ctx context.Context, db *pgx.Conn, qb *helper.QuerySelectBuilder) (int, error) {
+	var total int
+
+	q, args := qb.BuildCount()
+	err := db.QueryRow(ctx, q, args...).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
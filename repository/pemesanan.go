@@ -9,17 +9,27 @@ import (
 	"github.com/jackc/pgx/v4"
 	"github.com/sirupsen/logrus"
 	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/helper"
 	"github.com/wiormiw/GrowBaks/model"
 )
 
+// qRestockPemesananItems returns every item qty of a pemesanan into product stock
+const qRestockPemesananItems = `UPDATE products p SET stok = p.stok + i.qty
+	FROM pemesanan_items i
+	WHERE i.pemesanan_id = $1 AND p.id = i.product_id`
+
 type (
 	IPemesananRepository interface {
-		CreatePemesanan(id uuid.UUID, product_id uuid.UUID, old_stok int, req model.CreatePemesananRequest, product *model.GetAllProductRequest) error
-		GetAllPemesanan() ([]model.PemesananResponse, error)
+		CreatePemesanan(id uuid.UUID, product_id uuid.UUID, req model.CreatePemesananRequest, product *model.GetAllProductRequest) error
+		GetAllPemesanan(page model.PageRequest) ([]model.PemesananResponse, int, error)
 		GetAllPemesananPribadi(id uuid.UUID) ([]model.PemesananResponse, error)
+		GetAllPemesananByLapak(lapakID uuid.UUID, filter model.PemesananLapakFilter) ([]model.PemesananResponse, error)
 		GetPemesananByID(id uuid.UUID) (*model.PemesananResponse, error)
-		UpdatePemesanan(id uuid.UUID, status string) error
-		DeleteByID(pemesanan *model.PemesananResponse, product *model.GetAllProductRequest) error
+		UpdatePemesananStatus(pemesanan *model.PemesananResponse, status string, actorID uuid.UUID, actorRole string) error
+		GetPemesananStatusHistory(id uuid.UUID) ([]model.PemesananStatusHistory, error)
+		GetPemesananItems(id uuid.UUID) ([]model.PemesananItem, error)
+		CheckoutCart(userID uuid.UUID) ([]uuid.UUID, error)
+		DeleteByID(pemesanan *model.PemesananResponse) error
 	}
 
 	PemesananRepository struct {
@@ -30,51 +40,70 @@ type (
 	}
 )
 
-func (pemr *PemesananRepository) CreatePemesanan(id uuid.UUID, product_id uuid.UUID, old_stok int, req model.CreatePemesananRequest, product *model.GetAllProductRequest) error {
+// CreatePemesanan repository layer for executing command reserving product stock & creating a pemesanan in one transaction
+func (pemr *PemesananRepository) CreatePemesanan(id uuid.UUID, product_id uuid.UUID, req model.CreatePemesananRequest, product *model.GetAllProductRequest) error {
+	tx, err := pemr.DB.Begin(pemr.Context)
+	if err != nil {
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.CreatePemesanan Begin ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	unitPrice, err := pemr.reserveStock(tx, product_id, req.QTY)
+	if err != nil {
+		if errRollback := tx.Rollback(pemr.Context); errRollback != nil {
+			pemr.Logger.Error(fmt.Errorf("PemesananRepository.CreatePemesanan.Exec Stock Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
 
-	q := `INSERT INTO pemesanan (
+	q2 := `INSERT INTO pemesanan (
 		"name",
 		status,
 		user_id,
 		product_id,
-		qty
+		qty,
+		lapak_id,
+		total_price
 	) 
-	VALUES ($1,$2,$3,$4,$5) RETURNING id
+	VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING id
 	`
 
-	tx, err := pemr.DB.Begin(pemr.Context)
-	if err != nil {
-		pemr.Logger.Error(fmt.Sprintf("Error 13: %s", err))
-		return err
-	}
-
 	var pemesananID uuid.UUID
 	sQTY := strconv.Itoa(req.QTY)
 	pemesananName := "Pesanan:  " + sQTY + " " + product.ProductName
 
-	err = tx.QueryRow(pemr.Context, q, pemesananName, req.Status, id, product_id, req.QTY).Scan(&pemesananID)
+	subtotal := unitPrice * int64(req.QTY)
+
+	err = tx.QueryRow(pemr.Context, q2, pemesananName, req.Status, id, product_id, req.QTY, product.LapakID, subtotal).Scan(&pemesananID)
 	if err != nil {
-		err = tx.Rollback(pemr.Context)
-		if err != nil {
-			pemr.Logger.Error(fmt.Errorf("PemesananRepository.CreatePemesanan.QueryRow.Scan Rollback ERROR %v MSG %s", err, err.Error()))
-			return err
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.CreatePemesanan.QueryRow Scan ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(pemr.Context); errRollback != nil {
+			pemr.Logger.Error(fmt.Errorf("PemesananRepository.CreatePemesanan.QueryRow.Scan Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
 		}
 
-		pemr.Logger.Error(fmt.Errorf("PemesananRepository.CreatePemesanan.QueryRow Scan ERROR %v MSG %s", err, err.Error()))
 		return err
 	}
 
-	q2 := `UPDATE "products" set stok = $1 WHERE id = $2`
-	old_stok -= req.QTY
-	_, err = tx.Exec(pemr.Context, q2, old_stok, product_id)
+	q4 := `INSERT INTO pemesanan_items (pemesanan_id,product_id,qty,unit_price,subtotal) VALUES ($1,$2,$3,$4,$5)`
+	_, err = tx.Exec(pemr.Context, q4, pemesananID, product_id, req.QTY, unitPrice, subtotal)
 	if err != nil {
-		err = tx.Rollback(pemr.Context)
-		if err != nil {
-			pemr.Logger.Error(fmt.Errorf("PemesananRepository.ChangeStock.Exec Rollback ERROR %v MSG %s", err, err.Error()))
-			return err
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.CreatePemesanan.Exec Item ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(pemr.Context); errRollback != nil {
+			pemr.Logger.Error(fmt.Errorf("PemesananRepository.CreatePemesanan.Exec Item Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	q3 := `INSERT INTO pemesanan_status_history (pemesanan_id,from_status,to_status,actor_id,actor_role) VALUES ($1,NULL,$2,$3,$4)`
+	_, err = tx.Exec(pemr.Context, q3, pemesananID, req.Status, id, model.PemesananActorBuyer)
+	if err != nil {
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.CreatePemesanan.Exec History ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(pemr.Context); errRollback != nil {
+			pemr.Logger.Error(fmt.Errorf("PemesananRepository.CreatePemesanan.Exec History Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
 		}
 
-		pemr.Logger.Error(fmt.Errorf("PemesananRepository.ChangeStock.Exec ERROR %v MSG %s", err, err.Error()))
 		return err
 	}
 
@@ -87,25 +116,49 @@ func (pemr *PemesananRepository) CreatePemesanan(id uuid.UUID, product_id uuid.U
 	return nil
 }
 
+// pemesananSortColumns whitelist sortable column of pemesanan list
+var pemesananSortColumns = map[string]string{
+	"status":      "pem.status",
+	"total_price": "pem.total_price",
+	"created_at":  "pem.created_at",
+}
+
 // GetAllPemesanan
-func (pemr *PemesananRepository) GetAllPemesanan() ([]model.PemesananResponse, error) {
+func (pemr *PemesananRepository) GetAllPemesanan(page model.PageRequest) ([]model.PemesananResponse, int, error) {
 	q := `SELECT pem.id, 
 		pem.name,
 		pem.status, 
 		pem.qty,
+		pem.total_price,
 		pem.created_at,
 		pem.updated_at,
 		u.id as user_id,
-		p.id as product_id
+		COALESCE(pem.product_id::text, '') as product_id,
+		COALESCE(pem.lapak_id::text, '') as lapak_id
 		FROM pemesanan pem
 		LEFT JOIN users u ON pem.user_id = u.id
 		LEFT JOIN products p on pem.product_id = p.id 
 	`
 
-	rows, err := pemr.DB.Query(pemr.Context, q)
+	qb := helper.NewQuerySelectBuilder(q)
 
+	total, err := countRows(pemr.Context, pemr.DB, qb)
 	if err != nil {
-		return nil, err
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.GetAllPemesanan Count ERROR %v MSG %s", err, err.Error()))
+		return nil, 0, err
+	}
+
+	err = paginate(qb, page, pemesananSortColumns, "pem.id")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	q, args := qb.Build()
+
+	rows, err := pemr.DB.Query(pemr.Context, q, args...)
+
+	if err != nil {
+		return nil, 0, err
 	}
 
 	var listData []model.PemesananResponse
@@ -117,20 +170,22 @@ func (pemr *PemesananRepository) GetAllPemesanan() ([]model.PemesananResponse, e
 			&pemesanan.PemesananName,
 			&pemesanan.Status,
 			&pemesanan.QTY,
+			&pemesanan.TotalPrice,
 			&pemesanan.CreatedAt,
 			&pemesanan.UpdatedAt,
 			&pemesanan.UserID,
-			&pemesanan.ProductID)
+			&pemesanan.ProductID,
+			&pemesanan.LapakID)
 
 		if err != nil {
 			pemr.Logger.Error(fmt.Errorf("PemesananRepository.GetAllPemesanan rows.Next Scan ERROR %v MSG %s", err, err.Error()))
-			return nil, err
+			return nil, 0, err
 		}
 
 		listData = append(listData, *pemesanan)
 	}
 
-	return listData, nil
+	return listData, total, nil
 }
 
 func (pemr *PemesananRepository) GetAllPemesananPribadi(id uuid.UUID) ([]model.PemesananResponse, error) {
@@ -138,10 +193,12 @@ func (pemr *PemesananRepository) GetAllPemesananPribadi(id uuid.UUID) ([]model.P
 		pem.name,
 		pem.status, 
 		pem.qty,
+		pem.total_price,
 		pem.created_at,
 		pem.updated_at,
 		u.id as user_id,
-		p.id as product_id
+		COALESCE(pem.product_id::text, '') as product_id,
+		COALESCE(pem.lapak_id::text, '') as lapak_id
 		FROM pemesanan pem
 		LEFT JOIN users u ON pem.user_id = u.id
 		LEFT JOIN products p on pem.product_id = p.id 
@@ -163,10 +220,12 @@ func (pemr *PemesananRepository) GetAllPemesananPribadi(id uuid.UUID) ([]model.P
 			&pemesanan.PemesananName,
 			&pemesanan.Status,
 			&pemesanan.QTY,
+			&pemesanan.TotalPrice,
 			&pemesanan.CreatedAt,
 			&pemesanan.UpdatedAt,
 			&pemesanan.UserID,
-			&pemesanan.ProductID)
+			&pemesanan.ProductID,
+			&pemesanan.LapakID)
 
 		if err != nil {
 			pemr.Logger.Error(fmt.Errorf("PemesananRepository.GetAllPemesanan rows.Next Scan ERROR %v MSG %s", err, err.Error()))
@@ -179,21 +238,171 @@ func (pemr *PemesananRepository) GetAllPemesananPribadi(id uuid.UUID) ([]model.P
 	return listData, nil
 }
 
-// Edit Pemesanan
-func (pemr *PemesananRepository) UpdatePemesanan(id uuid.UUID, status string) error {
-	q := ` UPDATE pemesanan
-		SET status = $1
-		WHERE id = $2
+// GetAllPemesananByLapak repository layer for querying command getting all pemesanan placed on products of a lapak
+func (pemr *PemesananRepository) GetAllPemesananByLapak(lapakID uuid.UUID, filter model.PemesananLapakFilter) ([]model.PemesananResponse, error) {
+	q := `SELECT pem.id,
+		pem.name,
+		pem.status,
+		pem.qty,
+		pem.total_price,
+		pem.created_at,
+		pem.updated_at,
+		pem.user_id,
+		COALESCE(pem.product_id::text, '') as product_id,
+		COALESCE(pem.lapak_id::text, '') as lapak_id
+		FROM pemesanan pem
 	`
-	_, err := pemr.DB.Exec(pemr.Context, q, status, id)
+
+	qb := helper.NewQuerySelectBuilder(q).Where("pem.lapak_id = ?", lapakID)
+
+	if filter.Status != "" {
+		qb.Where("pem.status = ?", filter.Status)
+	}
+
+	if filter.From != nil {
+		qb.Where("pem.created_at >= ?", *filter.From)
+	}
+
+	if filter.To != nil {
+		qb.Where("pem.created_at < ?", *filter.To)
+	}
+
+	q, args := qb.Build()
+	q += " ORDER BY pem.created_at DESC"
+
+	rows, err := pemr.DB.Query(pemr.Context, q, args...)
 	if err != nil {
-		pemr.Logger.Error(fmt.Errorf("PemesananRepository.UpdateByPemesanan ERROR : %v MSG : %s", err, err.Error()))
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.GetAllPemesananByLapak Query ERROR %v MSG %s", err, err.Error()))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var listData []model.PemesananResponse
+
+	for rows.Next() {
+		pemesanan := &model.PemesananResponse{}
+		err := rows.Scan(
+			&pemesanan.ID,
+			&pemesanan.PemesananName,
+			&pemesanan.Status,
+			&pemesanan.QTY,
+			&pemesanan.TotalPrice,
+			&pemesanan.CreatedAt,
+			&pemesanan.UpdatedAt,
+			&pemesanan.UserID,
+			&pemesanan.ProductID,
+			&pemesanan.LapakID)
+
+		if err != nil {
+			pemr.Logger.Error(fmt.Errorf("PemesananRepository.GetAllPemesananByLapak rows.Next Scan ERROR %v MSG %s", err, err.Error()))
+			return nil, err
+		}
+
+		listData = append(listData, *pemesanan)
+	}
+
+	return listData, nil
+}
+
+// UpdatePemesananStatus repository layer for executing command moving a pemesanan into next status, recording its history & restocking on cancel
+func (pemr *PemesananRepository) UpdatePemesananStatus(pemesanan *model.PemesananResponse, status string, actorID uuid.UUID, actorRole string) error {
+	tx, err := pemr.DB.Begin(pemr.Context)
+	if err != nil {
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.UpdatePemesananStatus Begin ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	// guarded by current status, so a concurrent transition from the same status only succeeds once
+	q := `UPDATE pemesanan SET status = $1, updated_at = now() WHERE id = $2 AND status = $3`
+	tag, err := tx.Exec(pemr.Context, q, status, pemesanan.ID, pemesanan.Status)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = model.ErrInvalidStatusTransition
+	}
+
+	if err != nil {
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.UpdatePemesananStatus.Exec Update ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(pemr.Context); errRollback != nil {
+			pemr.Logger.Error(fmt.Errorf("PemesananRepository.UpdatePemesananStatus.Exec Update Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	q2 := `INSERT INTO pemesanan_status_history (pemesanan_id,from_status,to_status,actor_id,actor_role) VALUES ($1,$2,$3,$4,$5)`
+	_, err = tx.Exec(pemr.Context, q2, pemesanan.ID, pemesanan.Status, status, actorID, actorRole)
+	if err != nil {
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.UpdatePemesananStatus.Exec History ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(pemr.Context); errRollback != nil {
+			pemr.Logger.Error(fmt.Errorf("PemesananRepository.UpdatePemesananStatus.Exec History Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	if status == model.PemesananStatusCancel {
+		_, err = tx.Exec(pemr.Context, qRestockPemesananItems, pemesanan.ID)
+		if err != nil {
+			pemr.Logger.Error(fmt.Errorf("PemesananRepository.UpdatePemesananStatus.Exec Restock ERROR %v MSG %s", err, err.Error()))
+			if errRollback := tx.Rollback(pemr.Context); errRollback != nil {
+				pemr.Logger.Error(fmt.Errorf("PemesananRepository.UpdatePemesananStatus.Exec Restock Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+			}
+
+			return err
+		}
+	}
+
+	err = tx.Commit(pemr.Context)
+	if err != nil {
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.UpdatePemesananStatus Commit ERROR %v MSG %s", err, err.Error()))
 		return err
 	}
 
 	return nil
 }
 
+// GetPemesananStatusHistory repository layer for querying command getting every status transition of a pemesanan
+func (pemr *PemesananRepository) GetPemesananStatusHistory(id uuid.UUID) ([]model.PemesananStatusHistory, error) {
+	q := `SELECT id,
+		from_status,
+		to_status,
+		actor_id,
+		actor_role,
+		created_at
+		FROM pemesanan_status_history
+		WHERE pemesanan_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := pemr.DB.Query(pemr.Context, q, id)
+	if err != nil {
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.GetPemesananStatusHistory Query ERROR %v MSG %s", err, err.Error()))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var listData []model.PemesananStatusHistory
+
+	for rows.Next() {
+		history := &model.PemesananStatusHistory{}
+		err := rows.Scan(
+			&history.ID,
+			&history.FromStatus,
+			&history.ToStatus,
+			&history.ActorID,
+			&history.ActorRole,
+			&history.CreatedAt)
+
+		if err != nil {
+			pemr.Logger.Error(fmt.Errorf("PemesananRepository.GetPemesananStatusHistory rows.Next Scan ERROR %v MSG %s", err, err.Error()))
+			return nil, err
+		}
+
+		listData = append(listData, *history)
+	}
+
+	return listData, nil
+}
+
 func (pemr *PemesananRepository) GetPemesananByID(id uuid.UUID) (*model.PemesananResponse, error) {
 	var pemesanan model.PemesananResponse
 
@@ -201,10 +410,12 @@ func (pemr *PemesananRepository) GetPemesananByID(id uuid.UUID) (*model.Pemesana
 		pem.name,
 		pem.status, 
 		pem.qty,
+		pem.total_price,
 		pem.created_at,
 		pem.updated_at,
 		u.id as user_id,
-		p.id as product_id
+		COALESCE(pem.product_id::text, '') as product_id,
+		COALESCE(pem.lapak_id::text, '') as lapak_id
 		FROM pemesanan pem
 		LEFT JOIN users u ON pem.user_id = u.id
 		LEFT JOIN products p on pem.product_id = p.id 
@@ -217,10 +428,12 @@ func (pemr *PemesananRepository) GetPemesananByID(id uuid.UUID) (*model.Pemesana
 		&pemesanan.PemesananName,
 		&pemesanan.Status,
 		&pemesanan.QTY,
+		&pemesanan.TotalPrice,
 		&pemesanan.CreatedAt,
 		&pemesanan.UpdatedAt,
 		&pemesanan.UserID,
 		&pemesanan.ProductID,
+		&pemesanan.LapakID,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -235,24 +448,275 @@ func (pemr *PemesananRepository) GetPemesananByID(id uuid.UUID) (*model.Pemesana
 	return &pemesanan, nil
 }
 
-// Delete Pemesanan
-func (pemr *PemesananRepository) DeleteByID(pemesanan *model.PemesananResponse, product *model.GetAllProductRequest) error {
+// DeleteByID repository layer for executing command deleting a pemesanan along with its items, status history is kept as audit trail
+func (pemr *PemesananRepository) DeleteByID(pemesanan *model.PemesananResponse) error {
+	tx, err := pemr.DB.Begin(pemr.Context)
+	if err != nil {
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.DeleteByID Begin ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	// cancelled pemesanan was already restocked, done pemesanan is sold
+	if pemesanan.Status != model.PemesananStatusCancel && pemesanan.Status != model.PemesananStatusDone {
+		_, err = tx.Exec(pemr.Context, qRestockPemesananItems, pemesanan.ID)
+		if err != nil {
+			pemr.Logger.Error(fmt.Errorf("PemesananRepository.DeleteByID Exec Restock ERROR %v MSG %s", err, err.Error()))
+			if errRollback := tx.Rollback(pemr.Context); errRollback != nil {
+				pemr.Logger.Error(fmt.Errorf("PemesananRepository.DeleteByID Exec Restock Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+			}
+
+			return err
+		}
+	}
+
 	q := `DELETE FROM "pemesanan" WHERE id = $1`
 
-	_, err := pemr.DB.Exec(pemr.Context, q, pemesanan.ID)
+	_, err = tx.Exec(pemr.Context, q, pemesanan.ID)
 	if err != nil {
 		pemr.Logger.Error(fmt.Errorf("PemesananRepository.DeleteByID Exec Pemesanan ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(pemr.Context); errRollback != nil {
+			pemr.Logger.Error(fmt.Errorf("PemesananRepository.DeleteByID Exec Pemesanan Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
 		return err
 	}
 
-	newStock := product.Stock + pemesanan.QTY
+	q2 := `DELETE FROM pemesanan_items WHERE pemesanan_id = $1`
+	_, err = tx.Exec(pemr.Context, q2, pemesanan.ID)
+	if err != nil {
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.DeleteByID Exec Item ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(pemr.Context); errRollback != nil {
+			pemr.Logger.Error(fmt.Errorf("PemesananRepository.DeleteByID Exec Item Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
 
-	q2 := `UPDATE products SET stok = $1 WHERE id = $2`
-	_, err = pemr.DB.Exec(pemr.Context, q2, newStock, product.ID)
+	err = tx.Commit(pemr.Context)
 	if err != nil {
-		pemr.Logger.Error(fmt.Errorf("PemesananRepository.DeleteByID Exec Pemesanan ERROR %v MSG %s", err, err.Error()))
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.DeleteByID Commit ERROR %v MSG %s", err, err.Error()))
 		return err
 	}
 
 	return nil
 }
+
+// GetPemesananItems repository layer for querying command getting every ordered product of a pemesanan
+func (pemr *PemesananRepository) GetPemesananItems(id uuid.UUID) ([]model.PemesananItem, error) {
+	q := `SELECT i.id,
+		i.product_id,
+		COALESCE(p.name, ''),
+		i.qty,
+		i.unit_price,
+		i.subtotal
+		FROM pemesanan_items i
+		LEFT JOIN products p ON p.id = i.product_id
+		WHERE i.pemesanan_id = $1
+		ORDER BY i.created_at ASC
+	`
+
+	rows, err := pemr.DB.Query(pemr.Context, q, id)
+	if err != nil {
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.GetPemesananItems Query ERROR %v MSG %s", err, err.Error()))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var listData []model.PemesananItem
+
+	for rows.Next() {
+		item := &model.PemesananItem{}
+		err := rows.Scan(&item.ID, &item.ProductID, &item.ProductName, &item.QTY, &item.UnitPrice, &item.Subtotal)
+		if err != nil {
+			pemr.Logger.Error(fmt.Errorf("PemesananRepository.GetPemesananItems rows.Next Scan ERROR %v MSG %s", err, err.Error()))
+			return nil, err
+		}
+
+		listData = append(listData, *item)
+	}
+
+	return listData, nil
+}
+
+// CheckoutCart repository layer for executing command turning cart of a user into one pemesanan per lapak, reserving every item stock in one transaction
+func (pemr *PemesananRepository) CheckoutCart(userID uuid.UUID) ([]uuid.UUID, error) {
+	tx, err := pemr.DB.Begin(pemr.Context)
+	if err != nil {
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.CheckoutCart Begin ERROR %v MSG %s", err, err.Error()))
+		return nil, err
+	}
+
+	cartItems, err := pemr.getCartItemsForCheckout(tx, userID)
+	if err == nil && len(cartItems) == 0 {
+		err = model.ErrCartEmpty
+	}
+
+	if err != nil {
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.CheckoutCart getCartItemsForCheckout ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(pemr.Context); errRollback != nil {
+			pemr.Logger.Error(fmt.Errorf("PemesananRepository.CheckoutCart getCartItemsForCheckout Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return nil, err
+	}
+
+	// cart items are sorted by lapak, so every lapak is a contiguous group turned into one pemesanan
+	var pemesananIDs []uuid.UUID
+	for start := 0; start < len(cartItems); {
+		end := start
+		for end < len(cartItems) && cartItems[end].LapakID == cartItems[start].LapakID {
+			end++
+		}
+
+		pemesananID, err := pemr.createPemesananFromCart(tx, userID, cartItems[start:end])
+		if err != nil {
+			if errRollback := tx.Rollback(pemr.Context); errRollback != nil {
+				pemr.Logger.Error(fmt.Errorf("PemesananRepository.CheckoutCart createPemesananFromCart Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+			}
+
+			return nil, err
+		}
+
+		pemesananIDs = append(pemesananIDs, pemesananID)
+		start = end
+	}
+
+	q := `DELETE FROM cart_items WHERE user_id = $1`
+	_, err = tx.Exec(pemr.Context, q, userID)
+	if err != nil {
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.CheckoutCart.Exec Clear Cart ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(pemr.Context); errRollback != nil {
+			pemr.Logger.Error(fmt.Errorf("PemesananRepository.CheckoutCart.Exec Clear Cart Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return nil, err
+	}
+
+	err = tx.Commit(pemr.Context)
+	if err != nil {
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.CheckoutCart Commit ERROR %v MSG %s", err, err.Error()))
+		return nil, err
+	}
+
+	return pemesananIDs, nil
+}
+
+// getCartItemsForCheckout responsible to reading cart of a user inside checkout transaction, sorted by lapak then product to keep row lock order stable
+func (pemr *PemesananRepository) getCartItemsForCheckout(tx pgx.Tx, userID uuid.UUID) ([]model.CartItem, error) {
+	q := `SELECT c.product_id,
+		p.name,
+		p.lapak_id,
+		l.name,
+		c.qty
+		FROM cart_items c
+		JOIN products p ON p.id = c.product_id
+		JOIN lapak l ON l.id = p.lapak_id
+		WHERE c.user_id = $1
+		ORDER BY p.lapak_id, p.id
+	`
+
+	rows, err := tx.Query(pemr.Context, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var listData []model.CartItem
+	for rows.Next() {
+		item := &model.CartItem{}
+		err := rows.Scan(&item.ProductID, &item.ProductName, &item.LapakID, &item.LapakName, &item.QTY)
+		if err != nil {
+			return nil, err
+		}
+
+		listData = append(listData, *item)
+	}
+
+	return listData, rows.Err()
+}
+
+// createPemesananFromCart responsible to creating a pemesanan of one lapak along with its items, reserving each item stock
+func (pemr *PemesananRepository) createPemesananFromCart(tx pgx.Tx, userID uuid.UUID, items []model.CartItem) (uuid.UUID, error) {
+	var (
+		pemesananID   uuid.UUID
+		productID     *uuid.UUID
+		totalQTY      int
+		totalPrice    int64
+		pemesananName string
+	)
+
+	// price is snapshotted while reserving, later price edit never changes this pemesanan
+	unitPrices := make([]int64, len(items))
+	for i, item := range items {
+		unitPrice, err := pemr.reserveStock(tx, item.ProductID, item.QTY)
+		if err != nil {
+			return uuid.Nil, err
+		}
+
+		unitPrices[i] = unitPrice
+		totalQTY += item.QTY
+		totalPrice += unitPrice * int64(item.QTY)
+	}
+
+	// single item pemesanan keeps product_id, so it reads the same as one created through POST pemesanan/:product_id
+	if len(items) == 1 {
+		productID = &items[0].ProductID
+		pemesananName = "Pesanan:  " + strconv.Itoa(items[0].QTY) + " " + items[0].ProductName
+	} else {
+		pemesananName = "Pesanan:  " + strconv.Itoa(len(items)) + " item dari " + items[0].LapakName
+	}
+
+	q := `INSERT INTO pemesanan ("name",status,user_id,product_id,qty,lapak_id,total_price) VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING id`
+	err := tx.QueryRow(pemr.Context, q, pemesananName, model.PemesananStatusReserve, userID, productID, totalQTY, items[0].LapakID, totalPrice).Scan(&pemesananID)
+	if err != nil {
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.createPemesananFromCart.QueryRow Scan ERROR %v MSG %s", err, err.Error()))
+		return uuid.Nil, err
+	}
+
+	q2 := `INSERT INTO pemesanan_items (pemesanan_id,product_id,qty,unit_price,subtotal) VALUES ($1,$2,$3,$4,$5)`
+	for i, item := range items {
+		_, err = tx.Exec(pemr.Context, q2, pemesananID, item.ProductID, item.QTY, unitPrices[i], unitPrices[i]*int64(item.QTY))
+		if err != nil {
+			pemr.Logger.Error(fmt.Errorf("PemesananRepository.createPemesananFromCart.Exec Item ERROR %v MSG %s", err, err.Error()))
+			return uuid.Nil, err
+		}
+	}
+
+	q3 := `INSERT INTO pemesanan_status_history (pemesanan_id,from_status,to_status,actor_id,actor_role) VALUES ($1,NULL,$2,$3,$4)`
+	_, err = tx.Exec(pemr.Context, q3, pemesananID, model.PemesananStatusReserve, userID, model.PemesananActorBuyer)
+	if err != nil {
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.createPemesananFromCart.Exec History ERROR %v MSG %s", err, err.Error()))
+		return uuid.Nil, err
+	}
+
+	return pemesananID, nil
+}
+
+// reserveStock responsible to decrementing product stock inside a transaction, returning current product price as unit price snapshot
+func (pemr *PemesananRepository) reserveStock(tx pgx.Tx, productID uuid.UUID, qty int) (int64, error) {
+	var unitPrice int64
+
+	// conditional decrement takes the product row lock, a concurrent order re-checks stok after this one commits.
+	// product not priced yet by its seller is never ordered, otherwise its 0 rupiah snapshot would stick forever
+	q := `UPDATE "products" SET stok = stok - $1 WHERE id = $2 AND stok >= $1 AND price > 0 AND deleted_at IS NULL RETURNING price`
+	err := tx.QueryRow(pemr.Context, q, qty, productID).Scan(&unitPrice)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = model.ErrInsufficientStock
+
+			var priced bool
+			errPrice := tx.QueryRow(pemr.Context, `SELECT price IS NOT NULL FROM "products" WHERE id = $1`, productID).Scan(&priced)
+			if errPrice == nil && !priced {
+				err = model.ErrProductNotPriced
+			}
+
+			pemr.Logger.Info(fmt.Errorf("PemesananRepository.reserveStock INFO product %s MSG %s", productID, err.Error()))
+			return 0, err
+		}
+
+		pemr.Logger.Error(fmt.Errorf("PemesananRepository.reserveStock ERROR %v MSG %s", err, err.Error()))
+		return 0, err
+	}
+
+	return unitPrice, nil
+}
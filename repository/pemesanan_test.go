@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/wiormiw/GrowBaks/model"
+)
+
+func TestCreatePemesananConcurrentNeverOversells(t *testing.T) {
+	const (
+		stock  = 5
+		orders = 20
+	)
+
+	dsn := testDSN(t)
+	conn := testConnect(t, dsn)
+	ctx, cfg, logger := testRepositoryDeps()
+	fixture := newTestFixture(t, conn)
+
+	var (
+		buyer   = fixture.user(0)
+		seller  = fixture.user(0)
+		lapak   = fixture.lapak(seller, 0)
+		product = fixture.product(lapak, stock, 0)
+	)
+
+	// single pgx.Conn is not safe for concurrent use, so every buyer gets its own connection
+	repos := make([]*PemesananRepository, orders)
+	for i := range repos {
+		repos[i] = &PemesananRepository{Context: ctx, Config: cfg, Logger: logger, DB: testConnect(t, dsn)}
+	}
+
+	var (
+		wg         sync.WaitGroup
+		start      = make(chan struct{})
+		errs       = make(chan error, orders)
+		productRow = &model.GetAllProductRequest{ProductName: "Fixture", LapakID: lapak}
+	)
+
+	for _, repo := range repos {
+		wg.Add(1)
+
+		go func(repo *PemesananRepository) {
+			defer wg.Done()
+			<-start
+
+			errs <- repo.CreatePemesanan(buyer, product, model.CreatePemesananRequest{Status: model.PemesananStatusReserve, QTY: 1}, productRow)
+		}(repo)
+	}
+
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	var succeeded, rejected int
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, model.ErrInsufficientStock):
+			rejected++
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	if succeeded != stock || rejected != orders-stock {
+		t.Errorf("succeeded %d & rejected %d, want %d & %d", succeeded, rejected, stock, orders-stock)
+	}
+
+	var remaining int
+	if err := conn.QueryRow(context.Background(), `SELECT stok FROM products WHERE id = $1`, product).Scan(&remaining); err != nil {
+		t.Fatalf("read stock: %v", err)
+	}
+
+	if remaining != 0 {
+		t.Errorf("remaining stock %d, want 0", remaining)
+	}
+}
+
+func TestCreatePemesananRejectsUnpricedProduct(t *testing.T) {
+	conn := testConnect(t, testDSN(t))
+	ctx, cfg, logger := testRepositoryDeps()
+	fixture := newTestFixture(t, conn)
+
+	var (
+		buyer   = fixture.user(0)
+		lapak   = fixture.lapak(fixture.user(0), 0)
+		product = fixture.product(lapak, 5, 0)
+		repo    = &PemesananRepository{Context: ctx, Config: cfg, Logger: logger, DB: conn}
+	)
+
+	// product created before price was introduced is left unpriced by migration
+	if _, err := conn.Exec(ctx, `UPDATE products SET price = NULL WHERE id = $1`, product); err != nil {
+		t.Fatalf("unprice fixture product: %v", err)
+	}
+
+	req := model.CreatePemesananRequest{Status: model.PemesananStatusReserve, QTY: 1}
+	err := repo.CreatePemesanan(buyer, product, req, &model.GetAllProductRequest{ProductName: "Fixture", LapakID: lapak})
+	if !errors.Is(err, model.ErrProductNotPriced) {
+		t.Fatalf("order unpriced product: %v, want ErrProductNotPriced", err)
+	}
+
+	var stock int
+	if err := conn.QueryRow(ctx, `SELECT stok FROM products WHERE id = $1`, product).Scan(&stock); err != nil || stock != 5 {
+		t.Errorf("stock %d with error %v, want untouched 5", stock, err)
+	}
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/model"
+)
+
+type (
+	// IPermissionRepository is an interface that has all the function to be implemented inside permission repository
+	IPermissionRepository interface {
+		GetAllPermission() ([]model.Permission, error)
+		CreatePermission(req model.CreatePermissionRequest) (uuid.UUID, error)
+		GetPermissionNamesByRoleName(roleName string) ([]string, error)
+		ReplaceRolePermissions(roleID uuid.UUID, permissions []string) error
+	}
+
+	// PermissionRepository is an app permission struct that consists of all the dependencies needed for permission repository
+	PermissionRepository struct {
+		Context context.Context
+		Config  *config.Configuration
+		Logger  *logrus.Logger
+		DB      *pgx.Conn
+	}
+)
+
+// GetAllPermission repository layer for querying command getting all permission
+func (pr *PermissionRepository) GetAllPermission() ([]model.Permission, error) {
+	q := `SELECT
+		id,
+		name,
+		COALESCE(description, '')
+		FROM "permissions"
+		ORDER BY name ASC
+	`
+
+	rows, err := pr.DB.Query(pr.Context, q)
+	if err != nil {
+		pr.Logger.Error(fmt.Errorf("PermissionRepository.GetAllPermission Query ERROR %v MSG %s", err, err.Error()))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var listData []model.Permission
+	for rows.Next() {
+		data := &model.Permission{}
+		err := rows.Scan(&data.ID, &data.Name, &data.Description)
+		if err != nil {
+			pr.Logger.Error(fmt.Errorf("PermissionRepository.GetAllPermission rows.Next Scan ERROR %v MSG %s", err, err.Error()))
+			return nil, err
+		}
+
+		listData = append(listData, *data)
+	}
+
+	return listData, nil
+}
+
+// CreatePermission repository layer for executing command creating a permission, returning pgx.ErrNoRows when name already used
+func (pr *PermissionRepository) CreatePermission(req model.CreatePermissionRequest) (uuid.UUID, error) {
+	var permissionID uuid.UUID
+
+	q := `INSERT INTO "permissions" (name,description) VALUES ($1,NULLIF($2,'')) ON CONFLICT (name) DO NOTHING RETURNING id`
+
+	err := pr.DB.QueryRow(pr.Context, q, req.Name, req.Description).Scan(&permissionID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			pr.Logger.Info(fmt.Errorf("PermissionRepository.CreatePermission INFO : %v MSG : %s", err, err.Error()))
+		} else {
+			pr.Logger.Error(fmt.Errorf("PermissionRepository.CreatePermission ERROR : %v MSG : %s", err, err.Error()))
+		}
+
+		return uuid.Nil, err
+	}
+
+	return permissionID, nil
+}
+
+// GetPermissionNamesByRoleName repository layer for querying command getting every permission name granted into a role
+func (pr *PermissionRepository) GetPermissionNamesByRoleName(roleName string) ([]string, error) {
+	q := `SELECT p.name
+		FROM "permissions" p
+		JOIN role_permissions rp ON rp.permission_id = p.id
+		JOIN roles r ON r.id = rp.role_id
+		WHERE r.name = $1
+	`
+
+	rows, err := pr.DB.Query(pr.Context, q, roleName)
+	if err != nil {
+		pr.Logger.Error(fmt.Errorf("PermissionRepository.GetPermissionNamesByRoleName Query ERROR %v MSG %s", err, err.Error()))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var listData []string
+	for rows.Next() {
+		var name string
+		err := rows.Scan(&name)
+		if err != nil {
+			pr.Logger.Error(fmt.Errorf("PermissionRepository.GetPermissionNamesByRoleName rows.Next Scan ERROR %v MSG %s", err, err.Error()))
+			return nil, err
+		}
+
+		listData = append(listData, name)
+	}
+
+	return listData, nil
+}
+
+// ReplaceRolePermissions repository layer for executing command replacing every permission of a role by permission names
+func (pr *PermissionRepository) ReplaceRolePermissions(roleID uuid.UUID, permissions []string) error {
+	tx, err := pr.DB.Begin(pr.Context)
+	if err != nil {
+		pr.Logger.Error(fmt.Errorf("PermissionRepository.ReplaceRolePermissions Begin ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	q := `DELETE FROM "role_permissions" WHERE role_id = $1`
+	_, err = tx.Exec(pr.Context, q, roleID)
+	if err != nil {
+		pr.Logger.Error(fmt.Errorf("PermissionRepository.ReplaceRolePermissions.Exec Delete ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(pr.Context); errRollback != nil {
+			pr.Logger.Error(fmt.Errorf("PermissionRepository.ReplaceRolePermissions.Exec Delete Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	// every name must resolve into a permission, otherwise the whole replacement is cancelled
+	q2 := `INSERT INTO "role_permissions" (role_id,permission_id) SELECT $1, id FROM "permissions" WHERE name = ANY($2)`
+	tag, err := tx.Exec(pr.Context, q2, roleID, permissions)
+	if err == nil && tag.RowsAffected() != int64(len(permissions)) {
+		err = model.ErrPermissionNotFound
+	}
+
+	if err != nil {
+		pr.Logger.Error(fmt.Errorf("PermissionRepository.ReplaceRolePermissions.Exec Insert ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(pr.Context); errRollback != nil {
+			pr.Logger.Error(fmt.Errorf("PermissionRepository.ReplaceRolePermissions.Exec Insert Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	err = tx.Commit(pr.Context)
+	if err != nil {
+		pr.Logger.Error(fmt.Errorf("PermissionRepository.ReplaceRolePermissions Commit ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
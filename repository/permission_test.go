@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var (
+	// grantPairPattern matches ('Role', 'permission') pair of a VALUES mapping
+	grantPairPattern = regexp.MustCompile(`\(\s*'([^']+)'\s*,\s*'([^']+)'\s*\)`)
+	// grantRolePattern & grantPermissionPattern match r.name / p.name filter of a cross joined mapping
+	grantRolePattern       = regexp.MustCompile(`r\.name\s*(?:=\s*'[^']+'|IN\s*\([^)]*\))`)
+	grantPermissionPattern = regexp.MustCompile(`p\.name\s*(?:=\s*'[^']+'|IN\s*\([^)]*\))`)
+	grantQuotedPattern     = regexp.MustCompile(`'([^']+)'`)
+)
+
+// roleGrants responsible to collecting every role & permission pair granted by INSERT INTO role_permissions of sql files
+func roleGrants(t *testing.T, files ...string) []string {
+	t.Helper()
+
+	granted := map[string]bool{}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("read %s: %v", file, err)
+		}
+
+		for _, stmt := range strings.Split(string(content), ";") {
+			if !strings.Contains(stmt, "INSERT INTO role_permissions") {
+				continue
+			}
+
+			if strings.Contains(stmt, "(VALUES") {
+				for _, pair := range grantPairPattern.FindAllStringSubmatch(stmt, -1) {
+					granted[pair[1]+" "+pair[2]] = true
+				}
+
+				continue
+			}
+
+			roles := grantQuotedPattern.FindAllStringSubmatch(grantRolePattern.FindString(stmt), -1)
+			permissions := grantQuotedPattern.FindAllStringSubmatch(grantPermissionPattern.FindString(stmt), -1)
+			if len(roles) == 0 || len(permissions) == 0 {
+				t.Fatalf("%s: can not read role permission mapping of\n%s", file, stmt)
+			}
+
+			for _, role := range roles {
+				for _, permission := range permissions {
+					granted[role[1]+" "+permission[1]] = true
+				}
+			}
+		}
+	}
+
+	grants := make([]string, 0, len(granted))
+	for grant := range granted {
+		grants = append(grants, grant)
+	}
+
+	sort.Strings(grants)
+
+	return grants
+}
+
+// fresh database runs every migration before roles are seeded, so db/data alone must grant what migration grants an existing one
+func TestRolePermissionSeedMatchesMigration(t *testing.T) {
+	migrations, err := filepath.Glob("../db/migration/*.up.sql")
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("list migration: %v", err)
+	}
+
+	sort.Strings(migrations)
+
+	var (
+		migrated    = roleGrants(t, migrations...)
+		seeded      = roleGrants(t, "../db/data/000004_insert_data_role_permission.sql")
+		inSeed      = map[string]bool{}
+		inMigration = map[string]bool{}
+	)
+
+	for _, grant := range seeded {
+		inSeed[grant] = true
+	}
+
+	for _, grant := range migrated {
+		inMigration[grant] = true
+
+		if !inSeed[grant] {
+			t.Errorf("%s is granted by migration but not seeded", grant)
+		}
+	}
+
+	for _, grant := range seeded {
+		if !inMigration[grant] {
+			t.Errorf("%s is seeded but not granted by migration", grant)
+		}
+	}
+}
@@ -3,22 +3,28 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4"
 	"github.com/sirupsen/logrus"
 	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/helper"
 	"github.com/wiormiw/GrowBaks/model"
 )
 
 type (
 	IProductRepository interface {
 		Create(req model.CreateProductRequest) error
-		GetAllProduct(search string, daerah string) ([]model.GetAllProductRequest, error)
-		GetAllProductByLapak(lapak_id uuid.UUID) ([]model.GetAllProductRequest, error)
+		GetAllProduct(filter model.ProductFilter, page model.PageRequest) ([]model.GetAllProductRequest, int, error)
+		GetProductFeed(filter model.ProductFilter, size int, after *model.ProductCursor) ([]model.GetAllProductRequest, int, error)
+		GetProductFacets(filter model.ProductFilter) (map[string][]model.FacetCount, error)
+		SuggestProduct(query string, limit int) ([]string, error)
+		GetAllProductByLapak(lapak_id uuid.UUID, includeDeleted bool) ([]model.GetAllProductRequest, error)
 		GetProductByID(id uuid.UUID) (*model.GetAllProductRequest, error)
-		// UpdateByID(id uuid.UUID, nama_produk string, kategori_produk string) error
-		// DeleteByID(id uuid.UUID) error
+		UpdateByID(id uuid.UUID, fields map[string]interface{}) error
+		DeleteByID(id uuid.UUID) error
+		RestoreByID(id uuid.UUID) error
 	}
 
 	ProductRepository struct {
@@ -34,14 +40,15 @@ func (pr *ProductRepository) Create(req model.CreateProductRequest) error {
 	q := `INSERT INTO products (
 		"name",
 		stok,
+		price,
 		product_kategori,
 		product_img,
 		lapak_id
 	) 
-	VALUES ($1,$2,$3,$4,$5)
+	VALUES ($1,$2,$3,$4,$5,$6)
 	`
 
-	_, err := pr.DB.Exec(pr.Context, q, req.ProductName, req.Stock, req.ProductCategory, req.ProductImg, req.LapakID)
+	_, err := pr.DB.Exec(pr.Context, q, req.ProductName, req.Stock, req.Price, req.ProductCategory, req.ProductImg, req.LapakID)
 	if err != nil {
 		pr.Logger.Error(fmt.Errorf("ProductRepository.Create Exec ERROR %v MSG %s", err, err.Error()))
 		return err
@@ -50,12 +57,137 @@ func (pr *ProductRepository) Create(req model.CreateProductRequest) error {
 	return nil
 }
 
+// productSortColumns whitelist sortable column of product list
+var productSortColumns = map[string]string{
+	"name":       "p.name",
+	"price":      "p.price",
+	"stok":       "p.stok",
+	"created_at": "p.created_at",
+}
+
+const (
+	// productSearchQuery parse buyer search text using product_search text search configuration, stemming indonesian word when available
+	productSearchQuery = "websearch_to_tsquery('product_search', ?)"
+	// productSearchMatch is full text match or trigram match of product name or its lapak name, typo like "bakso" still matches "Baso Malang".
+	// <% compares against pg_trgm.word_similarity_threshold set by migration, each branch is served by its own index
+	productSearchMatch = "(p.search_vector @@ " + productSearchQuery + " OR ? <% p.name OR p.lapak_id = ANY(ARRAY(SELECT id FROM lapak WHERE ? <% name)))"
+	// productSearchText is text ranked by trigram similarity once matched
+	productSearchText = "p.name || ' ' || COALESCE(l.name, '')"
+)
+
 // GetAllProduct
-func (pr *ProductRepository) GetAllProduct(search string, daerah string) ([]model.GetAllProductRequest, error) {
+func (pr *ProductRepository) GetAllProduct(filter model.ProductFilter, page model.PageRequest) ([]model.GetAllProductRequest, int, error) {
+	qb := pr.productListQuery(filter)
+
+	total, err := countRows(pr.Context, pr.DB, qb)
+	if err != nil {
+		pr.Logger.Error(fmt.Errorf("ProductRepository.GetAllProduct Count ERROR %v MSG %s", err, err.Error()))
+		return nil, 0, err
+	}
+
+	if page.Sort == "relevance" {
+		err = paginateByRelevance(qb, page, filter.Search)
+	} else {
+		err = paginate(qb, page, productSortColumns, "p.id")
+	}
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	q, args := qb.Build()
+
+	pr.Logger.Info(fmt.Sprintf("Query : %s", q))
+
+	listData, err := pr.queryProducts("GetAllProduct", q, args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return listData, total, nil
+}
+
+// GetProductFeed repository layer for querying command getting newest product after given cursor, nil cursor means first page
+func (pr *ProductRepository) GetProductFeed(filter model.ProductFilter, size int, after *model.ProductCursor) ([]model.GetAllProductRequest, int, error) {
+	qb := pr.productListQuery(filter)
+
+	total, err := countRows(pr.Context, pr.DB, qb)
+	if err != nil {
+		pr.Logger.Error(fmt.Errorf("ProductRepository.GetProductFeed Count ERROR %v MSG %s", err, err.Error()))
+		return nil, 0, err
+	}
+
+	if after != nil {
+		qb.Where("(p.created_at, p.id) < (?, ?)", after.CreatedAt, after.ID)
+	}
+
+	q, args := qb.OrderBy("p.created_at DESC, p.id DESC").Limit(size, 0).Build()
+
+	pr.Logger.Info(fmt.Sprintf("Query : %s", q))
+
+	listData, err := pr.queryProducts("GetProductFeed", q, args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return listData, total, nil
+}
 
+// GetProductFacets repository layer for querying command counting filtered product per category & daerah, each facet ignores its own filter so every chip keeps its number
+func (pr *ProductRepository) GetProductFacets(filter model.ProductFilter) (map[string][]model.FacetCount, error) {
+	categoryFilter := filter
+	categoryFilter.Categories = nil
+
+	daerahFilter := filter
+	daerahFilter.Daerah = ""
+	daerahFilter.LocationID = nil
+
+	facets := []struct {
+		name   string
+		column string
+		filter model.ProductFilter
+	}{
+		{"kategori", "product_kategori::text", categoryFilter},
+		{"daerah", "COALESCE(daerah, '')", daerahFilter},
+	}
+
+	result := make(map[string][]model.FacetCount)
+
+	for _, facet := range facets {
+		q, args := pr.productListQuery(facet.filter).BuildGroupCount(facet.column)
+
+		rows, err := pr.DB.Query(pr.Context, q, args...)
+		if err != nil {
+			pr.Logger.Error(fmt.Errorf("ProductRepository.GetProductFacets Query %s ERROR %v MSG %s", facet.name, err, err.Error()))
+			return nil, err
+		}
+
+		counts := []model.FacetCount{}
+		for rows.Next() {
+			var count model.FacetCount
+			err := rows.Scan(&count.Value, &count.Count)
+			if err != nil {
+				rows.Close()
+				pr.Logger.Error(fmt.Errorf("ProductRepository.GetProductFacets rows.Next Scan %s ERROR %v MSG %s", facet.name, err, err.Error()))
+				return nil, err
+			}
+
+			counts = append(counts, count)
+		}
+		rows.Close()
+
+		result[facet.name] = counts
+	}
+
+	return result, nil
+}
+
+// productListQuery responsible to building product list query applying every given filter
+func (pr *ProductRepository) productListQuery(filter model.ProductFilter) *helper.QuerySelectBuilder {
 	q := `SELECT p.id AS product_id,
 		p.name as product_name,
 		p.stok,
+		COALESCE(p.price, 0) AS price,
 		p.product_kategori,
 		p.product_img,
 		p.created_at,
@@ -63,46 +195,125 @@ func (pr *ProductRepository) GetAllProduct(search string, daerah string) ([]mode
 		l.id as lapak_id,
 		l.name as lapak_name,
 		loc.id as location_id,
-		loc.daerah as daerah
+		loc.daerah as daerah,
+		COALESCE(l.status::text, 'closed') AS lapak_status,
+		p.deleted_at
 		FROM "products" p
 		LEFT JOIN lapak l ON p.lapak_id = l.id
 		LEFT JOIN lokasi loc on l.location_id = loc.id
 	`
 
-	criteria := ""
-	criteria = ""
+	qb := helper.NewQuerySelectBuilder(q).Where(activeBlacklistCriteria)
 
-	if len(search) > 0 {
-		criteria += " p.name ILIKE '%" + search + "%'"
+	if !filter.IncludeDeleted {
+		qb.Where("p.deleted_at IS NULL")
 	}
 
-	cr := ""
-	filterDaerah := ""
-	if len(criteria) > 0 {
-		cr = " WHERE " + criteria
+	if len(filter.Search) > 0 {
+		qb.Where(productSearchMatch, filter.Search, filter.Search, filter.Search)
 	}
 
-	if len(criteria) > 0 && daerah != "" {
-		filterDaerah = " AND daerah = '" + daerah + "'"
-	} else if len(criteria) < 1 && daerah != "" {
-		filterDaerah = " WHERE daerah = '" + daerah + "'"
+	if len(filter.Categories) > 0 {
+		qb.Where("p.product_kategori::text = ANY(?)", filter.Categories)
 	}
 
-	if cr != "" {
-		q += cr
+	if filter.InStock {
+		qb.Where("p.stok > 0")
 	}
 
-	if filterDaerah != "" {
-		q += filterDaerah
+	if filter.OpenLapakOnly {
+		qb.Where("l.status = 'open'")
 	}
 
-	pr.Logger.Info(fmt.Sprintf("Query : %s", q))
+	if filter.PriceMin != nil {
+		qb.Where("p.price >= ?", *filter.PriceMin)
+	}
+
+	if filter.PriceMax != nil {
+		qb.Where("p.price <= ?", *filter.PriceMax)
+	}
+
+	if filter.LapakID != nil {
+		qb.Where("p.lapak_id = ?", *filter.LapakID)
+	}
+
+	if filter.LocationID != nil {
+		qb.Where("l.location_id = ?", *filter.LocationID)
+	}
+
+	if filter.Daerah != "" {
+		qb.Where("loc.daerah = ?", filter.Daerah)
+	}
+
+	if filter.Near != nil {
+		minLat, maxLat, minLng, maxLng := boundingBox(*filter.Near)
+
+		qb.Where("l.latitude BETWEEN ? AND ? AND l.longitude BETWEEN ? AND ?", minLat, maxLat, minLng, maxLng)
+		qb.Where(haversineKM("l.latitude", "l.longitude")+" <= ?", append(haversineArgs(*filter.Near), filter.Near.RadiusKM)...)
+	}
+
+	return qb
+}
+
+// paginateByRelevance responsible to ordering searched product by full text rank followed by trigram similarity
+func paginateByRelevance(qb *helper.QuerySelectBuilder, page model.PageRequest, search string) error {
+	if len(search) == 0 {
+		return model.ErrInvalidRequest
+	}
+
+	direction := " DESC"
+	if page.Order == "asc" {
+		direction = " ASC"
+	}
+
+	qb.OrderBy("ts_rank(p.search_vector, "+productSearchQuery+")"+direction+", word_similarity(?, "+productSearchText+")"+direction+", p.id"+direction, search, search)
+	qb.Limit(page.Size, (page.Page-1)*page.Size)
 
-	rows, err := pr.DB.Query(pr.Context, q)
+	return nil
+}
+
+// SuggestProduct repository layer for querying command getting product name autocompletion of given query
+func (pr *ProductRepository) SuggestProduct(query string, limit int) ([]string, error) {
+	q := `SELECT p.name
+		FROM products p
+		JOIN lapak l ON l.id = p.lapak_id
+		WHERE p.deleted_at IS NULL AND l.deleted_at IS NULL AND` + activeBlacklistCriteria + `
+		AND (p.name ILIKE $1 OR $2 <% p.name)
+		GROUP BY p.name
+		ORDER BY bool_or(p.name ILIKE $1) DESC, max(word_similarity($2, p.name)) DESC, p.name
+		LIMIT $3
+	`
+
+	rows, err := pr.DB.Query(pr.Context, q, helper.LikeContains(query), query, limit)
+	if err != nil {
+		pr.Logger.Error(fmt.Errorf("ProductRepository.SuggestProduct Query ERROR %v MSG %s", err, err.Error()))
+		return nil, err
+	}
+	defer rows.Close()
+
+	suggestions := []string{}
+	for rows.Next() {
+		var name string
+		err := rows.Scan(&name)
+		if err != nil {
+			pr.Logger.Error(fmt.Errorf("ProductRepository.SuggestProduct rows.Next Scan ERROR %v MSG %s", err, err.Error()))
+			return nil, err
+		}
+
+		suggestions = append(suggestions, name)
+	}
+
+	return suggestions, nil
+}
 
+// queryProducts responsible to querying & scanning rows of product list query
+func (pr *ProductRepository) queryProducts(method string, q string, args []interface{}) ([]model.GetAllProductRequest, error) {
+	rows, err := pr.DB.Query(pr.Context, q, args...)
 	if err != nil {
+		pr.Logger.Error(fmt.Errorf("ProductRepository.%s Query ERROR %v MSG %s", method, err, err.Error()))
 		return nil, err
 	}
+	defer rows.Close()
 
 	var listData []model.GetAllProductRequest
 	for rows.Next() {
@@ -110,6 +321,7 @@ func (pr *ProductRepository) GetAllProduct(search string, daerah string) ([]mode
 		err := rows.Scan(&data.ID,
 			&data.ProductName,
 			&data.Stock,
+			&data.Price,
 			&data.ProductCategory,
 			&data.ProductImg,
 			&data.CreatedAt,
@@ -117,9 +329,11 @@ func (pr *ProductRepository) GetAllProduct(search string, daerah string) ([]mode
 			&data.LapakID,
 			&data.LapakName,
 			&data.LocationID,
-			&data.Daerah)
+			&data.Daerah,
+			&data.LapakStatus,
+			&data.DeletedAt)
 		if err != nil {
-			pr.Logger.Error(fmt.Errorf("ProductRepository.GetAllProduct rows.Next Scan ERROR %v MSG %s", err, err.Error()))
+			pr.Logger.Error(fmt.Errorf("ProductRepository.%s rows.Next Scan ERROR %v MSG %s", method, err, err.Error()))
 			return nil, err
 		}
 
@@ -129,10 +343,11 @@ func (pr *ProductRepository) GetAllProduct(search string, daerah string) ([]mode
 	return listData, nil
 }
 
-func (pr *ProductRepository) GetAllProductByLapak(lapak_id uuid.UUID) ([]model.GetAllProductRequest, error) {
+func (pr *ProductRepository) GetAllProductByLapak(lapak_id uuid.UUID, includeDeleted bool) ([]model.GetAllProductRequest, error) {
 	q := `SELECT p.id AS product_id,
 		p.name as product_name,
 		p.stok,
+		COALESCE(p.price, 0) AS price,
 		p.product_kategori,
 		p.product_img,
 		p.created_at,
@@ -140,16 +355,17 @@ func (pr *ProductRepository) GetAllProductByLapak(lapak_id uuid.UUID) ([]model.G
 		l.id as lapak_id,
 		l.name as lapak_name,
 		loc.id as location_id,
-		loc.daerah as daerah
+		loc.daerah as daerah,
+		p.deleted_at
 		FROM "products" p
 		LEFT JOIN lapak l ON p.lapak_id = l.id
 		LEFT JOIN lokasi loc on l.location_id = loc.id
-		WHERE lapak_id = $1
+		WHERE lapak_id = $1 AND ($2 OR p.deleted_at IS NULL)
 	`
 
 	pr.Logger.Info(fmt.Sprintf("Query : %s", q))
 
-	rows, err := pr.DB.Query(pr.Context, q, lapak_id)
+	rows, err := pr.DB.Query(pr.Context, q, lapak_id, includeDeleted)
 
 	if err != nil {
 		return nil, err
@@ -161,6 +377,7 @@ func (pr *ProductRepository) GetAllProductByLapak(lapak_id uuid.UUID) ([]model.G
 		err := rows.Scan(&data.ID,
 			&data.ProductName,
 			&data.Stock,
+			&data.Price,
 			&data.ProductCategory,
 			&data.ProductImg,
 			&data.CreatedAt,
@@ -168,7 +385,8 @@ func (pr *ProductRepository) GetAllProductByLapak(lapak_id uuid.UUID) ([]model.G
 			&data.LapakID,
 			&data.LapakName,
 			&data.LocationID,
-			&data.Daerah)
+			&data.Daerah,
+			&data.DeletedAt)
 		if err != nil {
 			pr.Logger.Error(fmt.Errorf("ProductRepository.GetAllProductByLapak rows.Next Scan ERROR %v MSG %s", err, err.Error()))
 			return nil, err
@@ -187,6 +405,7 @@ func (pr *ProductRepository) GetProductByID(id uuid.UUID) (*model.GetAllProductR
 	q := `SELECT p.id AS product_id,
 		p.name as product_name,
 		p.stok,
+		COALESCE(p.price, 0) AS price,
 		p.product_kategori,
 		p.product_img,
 		p.created_at,
@@ -194,17 +413,19 @@ func (pr *ProductRepository) GetProductByID(id uuid.UUID) (*model.GetAllProductR
 		l.id as lapak_id,
 		l.name as lapak_name,
 		loc.id as location_id,
-		loc.daerah as daerah
+		loc.daerah as daerah,
+		p.deleted_at
 		FROM "products" p
 		LEFT JOIN lapak l ON p.lapak_id = l.id
 		LEFT JOIN lokasi loc on l.location_id = loc.id
-		WHERE p.id = $1
+		WHERE p.id = $1 AND p.deleted_at IS NULL
 	`
 
 	row := pr.DB.QueryRow(pr.Context, q, id)
 	err := row.Scan(&product.ID,
 		&product.ProductName,
 		&product.Stock,
+		&product.Price,
 		&product.ProductCategory,
 		&product.ProductImg,
 		&product.CreatedAt,
@@ -212,7 +433,8 @@ func (pr *ProductRepository) GetProductByID(id uuid.UUID) (*model.GetAllProductR
 		&product.LapakID,
 		&product.LapakName,
 		&product.LocationID,
-		&product.Daerah)
+		&product.Daerah,
+		&product.DeletedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			pr.Logger.Info(fmt.Errorf("LapakRepository.GetLapakByID INFO : %v MSG : %s", err, err.Error()))
@@ -269,3 +491,93 @@ func (pr *ProductRepository) GetProductByID(id uuid.UUID) (*model.GetAllProductR
 
 // 	return nil
 // }
+
+// UpdateByID repository layer for executing command updating given columns of a product by id
+func (pr *ProductRepository) UpdateByID(id uuid.UUID, fields map[string]interface{}) error {
+	fields["id"] = id
+	fields["updated_at"] = time.Now()
+
+	q, args, err := helper.QueryUpdateBuilder("products", fields, []string{"id"})
+	if err != nil {
+		pr.Logger.Error(fmt.Errorf("ProductRepository.UpdateByID QueryUpdateBuilder ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	q += " AND deleted_at IS NULL"
+
+	tag, err := pr.DB.Exec(pr.Context, q, args...)
+	if err != nil {
+		pr.Logger.Error(fmt.Errorf("ProductRepository.UpdateByID Exec ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return model.ErrProductNotFound
+	}
+
+	return nil
+}
+
+// DeleteByID repository layer for executing command soft deleting a product by id, existing pemesanan still resolve it
+func (pr *ProductRepository) DeleteByID(id uuid.UUID) error {
+	tx, err := pr.DB.Begin(pr.Context)
+	if err != nil {
+		pr.Logger.Error(fmt.Errorf("ProductRepository.DeleteByID Begin ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	q := `UPDATE products SET deleted_at = now(), updated_at = now() WHERE id = $1 AND deleted_at IS NULL`
+	tag, err := tx.Exec(pr.Context, q, id)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = model.ErrProductNotFound
+	}
+
+	if err != nil {
+		pr.Logger.Error(fmt.Errorf("ProductRepository.DeleteByID.Exec Product ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(pr.Context); errRollback != nil {
+			pr.Logger.Error(fmt.Errorf("ProductRepository.DeleteByID.Exec Product Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	// deleted product can no longer be checked out
+	q2 := `DELETE FROM cart_items WHERE product_id = $1`
+	_, err = tx.Exec(pr.Context, q2, id)
+	if err != nil {
+		pr.Logger.Error(fmt.Errorf("ProductRepository.DeleteByID.Exec Cart ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(pr.Context); errRollback != nil {
+			pr.Logger.Error(fmt.Errorf("ProductRepository.DeleteByID.Exec Cart Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	err = tx.Commit(pr.Context)
+	if err != nil {
+		pr.Logger.Error(fmt.Errorf("ProductRepository.DeleteByID Commit ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// RestoreByID repository layer for executing command restoring a soft deleted product whose lapak is not deleted
+func (pr *ProductRepository) RestoreByID(id uuid.UUID) error {
+	q := `UPDATE products p SET deleted_at = NULL, updated_at = now()
+		FROM lapak l
+		WHERE p.id = $1 AND p.deleted_at IS NOT NULL AND l.id = p.lapak_id AND l.deleted_at IS NULL
+	`
+
+	tag, err := pr.DB.Exec(pr.Context, q, id)
+	if err != nil {
+		pr.Logger.Error(fmt.Errorf("ProductRepository.RestoreByID Exec ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return model.ErrProductNotFound
+	}
+
+	return nil
+}
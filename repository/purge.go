@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/model"
+)
+
+type (
+	// IPurgeRepository is an interface that has all the function to be implemented inside purge repository
+	IPurgeRepository interface {
+		PurgeDeletedBefore(before time.Time) (model.PurgeResult, error)
+	}
+
+	// PurgeRepository is an app purge struct that consists of all the dependencies needed for purge repository
+	PurgeRepository struct {
+		Context context.Context
+		Config  *config.Configuration
+		Logger  *logrus.Logger
+		DB      *pgx.Conn
+	}
+)
+
+// record still referenced by pemesanan is kept soft deleted so order views keep showing its name,
+// lapak is kept while it still has product & user is kept while they still have lapak, each step runs after the previous one
+const (
+	purgeableProducts = `SELECT p.id FROM products p WHERE p.deleted_at < $1
+		AND NOT EXISTS (SELECT 1 FROM pemesanan_items i WHERE i.product_id = p.id)
+		AND NOT EXISTS (SELECT 1 FROM pemesanan pe WHERE pe.product_id = p.id)`
+	purgeableLapak = `SELECT l.id FROM lapak l WHERE l.deleted_at < $1
+		AND NOT EXISTS (SELECT 1 FROM pemesanan pe WHERE pe.lapak_id = l.id)
+		AND NOT EXISTS (SELECT 1 FROM products p WHERE p.lapak_id = l.id)`
+	purgeableUsers = `SELECT u.id FROM "users" u WHERE u.deleted_at < $1
+		AND NOT EXISTS (SELECT 1 FROM pemesanan pe WHERE pe.user_id = u.id)
+		AND NOT EXISTS (SELECT 1 FROM lapak l WHERE l.user_id = u.id)`
+)
+
+// PurgeDeletedBefore repository layer for executing command permanently deleting products, lapak & users soft deleted before given time
+func (pur *PurgeRepository) PurgeDeletedBefore(before time.Time) (model.PurgeResult, error) {
+	var result model.PurgeResult
+
+	tx, err := pur.DB.Begin(pur.Context)
+	if err != nil {
+		pur.Logger.Error(fmt.Errorf("PurgeRepository.PurgeDeletedBefore Begin ERROR %v MSG %s", err, err.Error()))
+		return result, err
+	}
+
+	// pemesanan & blacklist rows are kept as history, only rows owned by purged users are removed along with them
+	queries := []struct {
+		name     string
+		query    string
+		affected *int64
+	}{
+		{"Products", `DELETE FROM products WHERE id IN (` + purgeableProducts + `)`, &result.Products},
+		{"Opening Hours", `DELETE FROM lapak_opening_hours WHERE lapak_id IN (` + purgeableLapak + `)`, nil},
+		{"Holidays", `DELETE FROM lapak_holidays WHERE lapak_id IN (` + purgeableLapak + `)`, nil},
+		{"Lapak", `DELETE FROM lapak WHERE id IN (` + purgeableLapak + `)`, &result.Lapak},
+		{"Users Profile", `DELETE FROM users_profile WHERE user_id IN (` + purgeableUsers + `)`, nil},
+		{"Refresh Token", `DELETE FROM refresh_tokens WHERE user_id IN (` + purgeableUsers + `)`, nil},
+		{"Password Reset", `DELETE FROM password_resets WHERE user_id IN (` + purgeableUsers + `)`, nil},
+		{"Email Verification", `DELETE FROM email_verifications WHERE user_id IN (` + purgeableUsers + `)`, nil},
+		{"Recovery Code", `DELETE FROM recovery_codes WHERE user_id IN (` + purgeableUsers + `)`, nil},
+		{"MFA Challenge", `DELETE FROM mfa_challenges WHERE user_id IN (` + purgeableUsers + `)`, nil},
+		{"Users", `DELETE FROM "users" WHERE id IN (` + purgeableUsers + `)`, &result.Users},
+	}
+
+	for _, q := range queries {
+		tag, err := tx.Exec(pur.Context, q.query, before)
+		if err != nil {
+			pur.Logger.Error(fmt.Errorf("PurgeRepository.PurgeDeletedBefore Exec %s ERROR %v MSG %s", q.name, err, err.Error()))
+			if errRollback := tx.Rollback(pur.Context); errRollback != nil {
+				pur.Logger.Error(fmt.Errorf("PurgeRepository.PurgeDeletedBefore Exec %s Rollback ERROR %v MSG %s", q.name, errRollback, errRollback.Error()))
+			}
+
+			return model.PurgeResult{}, err
+		}
+
+		if q.affected != nil {
+			*q.affected = tag.RowsAffected()
+		}
+	}
+
+	err = tx.Commit(pur.Context)
+	if err != nil {
+		pur.Logger.Error(fmt.Errorf("PurgeRepository.PurgeDeletedBefore Commit ERROR %v MSG %s", err, err.Error()))
+		return model.PurgeResult{}, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestPurgeDeletedBeforeKeepsReferencedRecord(t *testing.T) {
+	conn := testConnect(t, testDSN(t))
+	ctx, cfg, logger := testRepositoryDeps()
+	fixture := newTestFixture(t, conn)
+
+	var (
+		buyer = fixture.user(0)
+
+		// seller, lapak & product are all soft deleted long ago but product is still inside a pemesanan
+		seller     = fixture.user(60)
+		lapak      = fixture.lapak(seller, 60)
+		referenced = fixture.product(lapak, 0, 60)
+		pemesanan  = fixture.pemesanan(buyer, lapak, referenced)
+
+		// nothing points into this seller, lapak & product
+		lonelySeller  = fixture.user(60)
+		lonelyLapak   = fixture.lapak(lonelySeller, 60)
+		lonelyProduct = fixture.product(lonelyLapak, 0, 60)
+	)
+
+	repo := &PurgeRepository{Context: ctx, Config: cfg, Logger: logger, DB: conn}
+
+	_, err := repo.PurgeDeletedBefore(time.Now().AddDate(0, 0, -30))
+	if err != nil {
+		t.Fatalf("PurgeDeletedBefore: %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		table string
+		id    uuid.UUID
+		kept  bool
+	}{
+		{"referenced product", "products", referenced, true},
+		{"lapak of referenced product", "lapak", lapak, true},
+		{"seller of referenced lapak", "users", seller, true},
+		{"pemesanan", "pemesanan", pemesanan, true},
+		{"unreferenced product", "products", lonelyProduct, false},
+		{"unreferenced lapak", "lapak", lonelyLapak, false},
+		{"unreferenced seller", "users", lonelySeller, false},
+	}
+
+	for _, tc := range cases {
+		if got := fixture.exists(tc.table, tc.id); got != tc.kept {
+			t.Errorf("%s kept = %v, want %v", tc.name, got, tc.kept)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4"
 	"github.com/sirupsen/logrus"
 	"github.com/wiormiw/GrowBaks/config"
@@ -14,6 +15,13 @@ type (
 	// IAuthRepository is an interface that has all the function to be implemented inside auth repository
 	IRoleRepository interface {
 		GetRoleByName(name string) (*model.Role, error)
+		GetRoleByID(id uuid.UUID) (*model.Role, error)
+		GetAllRole() ([]model.Role, error)
+		CreateRole(name string) (uuid.UUID, error)
+		DeleteRole(id uuid.UUID) error
+		UpdateRoleRequireMFA(id uuid.UUID, requireMFA bool) error
+		UpdateRoleMaxLapak(id uuid.UUID, maxLapak *int) error
+		AssignUserRole(userID uuid.UUID, roleID uuid.UUID) error
 	}
 
 	// AuthRepository is an app auth struct that consists of all the dependencies needed for auth repository
@@ -31,13 +39,15 @@ func (rr *RoleRepository) GetRoleByName(name string) (*model.Role, error) {
 
 	q := `SELECT 
 		id,
-		name
+		name,
+		require_mfa,
+		max_lapak
 		FROM "roles"
 		WHERE name = $1
 	`
 
 	row := rr.DB.QueryRow(rr.Context, q, name)
-	err := row.Scan(&role.ID, &role.Name)
+	err := row.Scan(&role.ID, &role.Name, &role.RequireMFA, &role.MaxLapak)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			rr.Logger.Info(fmt.Errorf("RoleRepository.GetRoleByName INFO : %v MSG : %s", err, err.Error()))
@@ -50,3 +60,185 @@ func (rr *RoleRepository) GetRoleByName(name string) (*model.Role, error) {
 
 	return &role, nil
 }
+
+// GetRoleByID repository layer for querying command getting any role by id along with its permissions
+func (rr *RoleRepository) GetRoleByID(id uuid.UUID) (*model.Role, error) {
+	var role model.Role
+
+	q := `SELECT
+		r.id,
+		r.name,
+		r.require_mfa,
+		r.max_lapak,
+		COALESCE(array_agg(p.name ORDER BY p.name) FILTER (WHERE p.name IS NOT NULL), '{}') AS permissions
+		FROM "roles" r
+		LEFT JOIN role_permissions rp ON rp.role_id = r.id
+		LEFT JOIN permissions p ON p.id = rp.permission_id
+		WHERE r.id = $1
+		GROUP BY r.id
+	`
+
+	row := rr.DB.QueryRow(rr.Context, q, id)
+	err := row.Scan(&role.ID, &role.Name, &role.RequireMFA, &role.MaxLapak, &role.Permissions)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			rr.Logger.Info(fmt.Errorf("RoleRepository.GetRoleByID INFO : %v MSG : %s", err, err.Error()))
+		} else {
+			rr.Logger.Error(fmt.Errorf("RoleRepository.GetRoleByID ERROR : %v MSG : %s", err, err.Error()))
+		}
+
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+// GetAllRole repository layer for querying command getting all role along with their permissions
+func (rr *RoleRepository) GetAllRole() ([]model.Role, error) {
+	q := `SELECT
+		r.id,
+		r.name,
+		r.require_mfa,
+		r.max_lapak,
+		COALESCE(array_agg(p.name ORDER BY p.name) FILTER (WHERE p.name IS NOT NULL), '{}') AS permissions
+		FROM "roles" r
+		LEFT JOIN role_permissions rp ON rp.role_id = r.id
+		LEFT JOIN permissions p ON p.id = rp.permission_id
+		GROUP BY r.id
+		ORDER BY r.name ASC
+	`
+
+	rows, err := rr.DB.Query(rr.Context, q)
+	if err != nil {
+		rr.Logger.Error(fmt.Errorf("RoleRepository.GetAllRole Query ERROR %v MSG %s", err, err.Error()))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var listData []model.Role
+	for rows.Next() {
+		data := &model.Role{}
+		err := rows.Scan(&data.ID, &data.Name, &data.RequireMFA, &data.MaxLapak, &data.Permissions)
+		if err != nil {
+			rr.Logger.Error(fmt.Errorf("RoleRepository.GetAllRole rows.Next Scan ERROR %v MSG %s", err, err.Error()))
+			return nil, err
+		}
+
+		listData = append(listData, *data)
+	}
+
+	return listData, nil
+}
+
+// CreateRole repository layer for executing command creating a role, returning pgx.ErrNoRows when name already used
+func (rr *RoleRepository) CreateRole(name string) (uuid.UUID, error) {
+	var roleID uuid.UUID
+
+	q := `INSERT INTO "roles" (name) VALUES ($1) ON CONFLICT (name) DO NOTHING RETURNING id`
+
+	err := rr.DB.QueryRow(rr.Context, q, name).Scan(&roleID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			rr.Logger.Info(fmt.Errorf("RoleRepository.CreateRole INFO : %v MSG : %s", err, err.Error()))
+		} else {
+			rr.Logger.Error(fmt.Errorf("RoleRepository.CreateRole ERROR : %v MSG : %s", err, err.Error()))
+		}
+
+		return uuid.Nil, err
+	}
+
+	return roleID, nil
+}
+
+// DeleteRole repository layer for executing command deleting a role which has no user
+func (rr *RoleRepository) DeleteRole(id uuid.UUID) error {
+	// NOT EXISTS guard keeps users from pointing into deleted role even on concurrent request
+	q := `DELETE FROM "roles" WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM users u WHERE u.role_id = $1)`
+
+	tag, err := rr.DB.Exec(rr.Context, q, id)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = model.ErrRoleInUse
+	}
+
+	if err != nil {
+		rr.Logger.Error(fmt.Errorf("RoleRepository.DeleteRole Exec ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// UpdateRoleRequireMFA repository layer for executing command toggling mfa requirement of a role
+func (rr *RoleRepository) UpdateRoleRequireMFA(id uuid.UUID, requireMFA bool) error {
+	q := `UPDATE "roles" SET require_mfa = $1, updated_at = now() WHERE id = $2`
+
+	_, err := rr.DB.Exec(rr.Context, q, requireMFA, id)
+	if err != nil {
+		rr.Logger.Error(fmt.Errorf("RoleRepository.UpdateRoleRequireMFA Exec ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// UpdateRoleMaxLapak repository layer for executing command capping lapak owned by each user of a role
+func (rr *RoleRepository) UpdateRoleMaxLapak(id uuid.UUID, maxLapak *int) error {
+	q := `UPDATE "roles" SET max_lapak = $1, updated_at = now() WHERE id = $2`
+
+	_, err := rr.DB.Exec(rr.Context, q, maxLapak, id)
+	if err != nil {
+		rr.Logger.Error(fmt.Errorf("RoleRepository.UpdateRoleMaxLapak Exec ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// AssignUserRole repository layer for executing command moving a user into another role, revoking their refresh token
+// so the next session is issued with permissions of the new role
+func (rr *RoleRepository) AssignUserRole(userID uuid.UUID, roleID uuid.UUID) error {
+	tx, err := rr.DB.Begin(rr.Context)
+	if err != nil {
+		rr.Logger.Error(fmt.Errorf("RoleRepository.AssignUserRole Begin ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	q := `UPDATE "users" SET role_id = $1, updated_at = now() WHERE id = $2 AND deleted_at IS NULL`
+	tag, err := tx.Exec(rr.Context, q, roleID, userID)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = model.ErrUserNotFound
+	}
+
+	if err != nil {
+		if err == model.ErrUserNotFound {
+			rr.Logger.Info(fmt.Errorf("RoleRepository.AssignUserRole.Exec User INFO %v MSG %s", err, err.Error()))
+		} else {
+			rr.Logger.Error(fmt.Errorf("RoleRepository.AssignUserRole.Exec User ERROR %v MSG %s", err, err.Error()))
+		}
+
+		if errRollback := tx.Rollback(rr.Context); errRollback != nil {
+			rr.Logger.Error(fmt.Errorf("RoleRepository.AssignUserRole.Exec User Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	q2 := `UPDATE "refresh_tokens" SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL`
+	_, err = tx.Exec(rr.Context, q2, userID)
+	if err != nil {
+		rr.Logger.Error(fmt.Errorf("RoleRepository.AssignUserRole.Exec Refresh Token ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(rr.Context); errRollback != nil {
+			rr.Logger.Error(fmt.Errorf("RoleRepository.AssignUserRole.Exec Refresh Token Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	err = tx.Commit(rr.Context)
+	if err != nil {
+		rr.Logger.Error(fmt.Errorf("RoleRepository.AssignUserRole Commit ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/wiormiw/GrowBaks/model"
+)
+
+func TestAssignUserRoleRevokesRefreshToken(t *testing.T) {
+	conn := testConnect(t, testDSN(t))
+	ctx, cfg, logger := testRepositoryDeps()
+	fixture := newTestFixture(t, conn)
+
+	var (
+		user     = fixture.user(0)
+		role     = fixture.role()
+		token    = fixture.refreshToken(user)
+		roleRepo = &RoleRepository{Context: ctx, Config: cfg, Logger: logger, DB: conn}
+	)
+
+	if err := roleRepo.AssignUserRole(user, role); err != nil {
+		t.Fatalf("assign role: %v", err)
+	}
+
+	var (
+		roleID  uuid.UUID
+		revoked bool
+	)
+
+	if err := conn.QueryRow(ctx, `SELECT role_id FROM users WHERE id = $1`, user).Scan(&roleID); err != nil || roleID != role {
+		t.Errorf("user role %s with error %v, want %s", roleID, err, role)
+	}
+
+	if err := conn.QueryRow(ctx, `SELECT revoked_at IS NOT NULL FROM refresh_tokens WHERE id = $1`, token).Scan(&revoked); err != nil || !revoked {
+		t.Errorf("refresh token revoked %v with error %v, want revoked", revoked, err)
+	}
+
+	if err := roleRepo.AssignUserRole(fixture.user(1), role); !errors.Is(err, model.ErrUserNotFound) {
+		t.Errorf("assign role of deleted user: %v, want ErrUserNotFound", err)
+	}
+}
@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/model"
+)
+
+type (
+	// IScheduleRepository is an interface that has all the function to be implemented inside schedule repository
+	IScheduleRepository interface {
+		GetScheduleByLapakIDs(ids []uuid.UUID) ([]model.LapakSchedule, error)
+		GetActiveSchedules() ([]model.LapakSchedule, error)
+		ReplaceOpeningHours(lapakID uuid.UUID, timezone string, hours []model.OpeningHours) error
+		UpsertHoliday(lapakID uuid.UUID, holiday model.LapakHoliday) error
+		DeleteHoliday(lapakID uuid.UUID, date string) error
+		ApplyScheduledStatus(ids []uuid.UUID, status string, requireEmailVerified bool) (int64, error)
+	}
+
+	// ScheduleRepository is an app schedule struct that consists of all the dependencies needed for schedule repository
+	ScheduleRepository struct {
+		Context context.Context
+		Config  *config.Configuration
+		Logger  *logrus.Logger
+		DB      *pgx.Conn
+	}
+)
+
+// GetScheduleByLapakIDs repository layer for querying command getting schedule of every given lapak, lapak without opening hours is included
+func (sr *ScheduleRepository) GetScheduleByLapakIDs(ids []uuid.UUID) ([]model.LapakSchedule, error) {
+	q := `SELECT id, timezone, status, scheduled_status FROM lapak WHERE id = ANY($1) AND deleted_at IS NULL`
+
+	return sr.querySchedules("GetScheduleByLapakIDs", q, ids)
+}
+
+// GetActiveSchedules repository layer for querying command getting schedule of every lapak having opening hours
+func (sr *ScheduleRepository) GetActiveSchedules() ([]model.LapakSchedule, error) {
+	q := `SELECT l.id, l.timezone, l.status, l.scheduled_status FROM lapak l
+		WHERE l.deleted_at IS NULL AND EXISTS (SELECT 1 FROM lapak_opening_hours h WHERE h.lapak_id = l.id)
+	`
+
+	return sr.querySchedules("GetActiveSchedules", q)
+}
+
+// querySchedules responsible to querying lapak of schedule query & loading their opening hours along with holidays from yesterday onward
+func (sr *ScheduleRepository) querySchedules(method string, q string, args ...interface{}) ([]model.LapakSchedule, error) {
+	rows, err := sr.DB.Query(sr.Context, q, args...)
+	if err != nil {
+		sr.Logger.Error(fmt.Errorf("ScheduleRepository.%s Query ERROR %v MSG %s", method, err, err.Error()))
+		return nil, err
+	}
+
+	var (
+		listData []model.LapakSchedule
+		lapakIDs []uuid.UUID
+		indexes  = map[uuid.UUID]int{}
+	)
+
+	for rows.Next() {
+		data := model.LapakSchedule{Hours: []model.OpeningHours{}, Holidays: []model.LapakHoliday{}}
+		err := rows.Scan(&data.LapakID, &data.Timezone, &data.Status, &data.ScheduledStatus)
+		if err != nil {
+			rows.Close()
+			sr.Logger.Error(fmt.Errorf("ScheduleRepository.%s rows.Next Scan ERROR %v MSG %s", method, err, err.Error()))
+			return nil, err
+		}
+
+		indexes[data.LapakID] = len(listData)
+		lapakIDs = append(lapakIDs, data.LapakID)
+		listData = append(listData, data)
+	}
+
+	rows.Close()
+
+	if len(listData) == 0 {
+		return listData, nil
+	}
+
+	q2 := `SELECT lapak_id, day_of_week, to_char(open_time, 'HH24:MI'), to_char(close_time, 'HH24:MI')
+		FROM lapak_opening_hours
+		WHERE lapak_id = ANY($1)
+		ORDER BY day_of_week ASC, open_time ASC
+	`
+
+	rows, err = sr.DB.Query(sr.Context, q2, lapakIDs)
+	if err != nil {
+		sr.Logger.Error(fmt.Errorf("ScheduleRepository.%s Query Opening Hours ERROR %v MSG %s", method, err, err.Error()))
+		return nil, err
+	}
+
+	for rows.Next() {
+		var (
+			lapakID uuid.UUID
+			hours   model.OpeningHours
+		)
+
+		err := rows.Scan(&lapakID, &hours.DayOfWeek, &hours.OpenTime, &hours.CloseTime)
+		if err != nil {
+			rows.Close()
+			sr.Logger.Error(fmt.Errorf("ScheduleRepository.%s rows.Next Scan Opening Hours ERROR %v MSG %s", method, err, err.Error()))
+			return nil, err
+		}
+
+		schedule := &listData[indexes[lapakID]]
+		schedule.Hours = append(schedule.Hours, hours)
+	}
+
+	rows.Close()
+
+	// yesterday is kept since overnight window of yesterday may still be running
+	q3 := `SELECT lapak_id, to_char(holiday_date, 'YYYY-MM-DD'), COALESCE(note, '')
+		FROM lapak_holidays
+		WHERE lapak_id = ANY($1) AND holiday_date >= current_date - 1
+		ORDER BY holiday_date ASC
+	`
+
+	rows, err = sr.DB.Query(sr.Context, q3, lapakIDs)
+	if err != nil {
+		sr.Logger.Error(fmt.Errorf("ScheduleRepository.%s Query Holidays ERROR %v MSG %s", method, err, err.Error()))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			lapakID uuid.UUID
+			holiday model.LapakHoliday
+		)
+
+		err := rows.Scan(&lapakID, &holiday.Date, &holiday.Note)
+		if err != nil {
+			sr.Logger.Error(fmt.Errorf("ScheduleRepository.%s rows.Next Scan Holidays ERROR %v MSG %s", method, err, err.Error()))
+			return nil, err
+		}
+
+		schedule := &listData[indexes[lapakID]]
+		schedule.Holidays = append(schedule.Holidays, holiday)
+	}
+
+	return listData, nil
+}
+
+// ReplaceOpeningHours repository layer for executing command replacing timezone & every opening hours of a lapak
+func (sr *ScheduleRepository) ReplaceOpeningHours(lapakID uuid.UUID, timezone string, hours []model.OpeningHours) error {
+	tx, err := sr.DB.Begin(sr.Context)
+	if err != nil {
+		sr.Logger.Error(fmt.Errorf("ScheduleRepository.ReplaceOpeningHours Begin ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	// scheduled_status is reset so the scheduler applies the new hours on its next run
+	q := `UPDATE lapak SET timezone = $1, scheduled_status = NULL WHERE id = $2 AND deleted_at IS NULL`
+	tag, err := tx.Exec(sr.Context, q, timezone, lapakID)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = model.ErrLapakNotFound
+	}
+
+	if err != nil {
+		sr.Logger.Error(fmt.Errorf("ScheduleRepository.ReplaceOpeningHours Exec Lapak ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(sr.Context); errRollback != nil {
+			sr.Logger.Error(fmt.Errorf("ScheduleRepository.ReplaceOpeningHours Exec Lapak Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	q2 := `DELETE FROM lapak_opening_hours WHERE lapak_id = $1`
+	_, err = tx.Exec(sr.Context, q2, lapakID)
+	if err != nil {
+		sr.Logger.Error(fmt.Errorf("ScheduleRepository.ReplaceOpeningHours Exec Delete ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(sr.Context); errRollback != nil {
+			sr.Logger.Error(fmt.Errorf("ScheduleRepository.ReplaceOpeningHours Exec Delete Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	q3 := `INSERT INTO lapak_opening_hours (lapak_id,day_of_week,open_time,close_time) VALUES ($1,$2,$3::TIME,$4::TIME)`
+	for _, hour := range hours {
+		_, err = tx.Exec(sr.Context, q3, lapakID, hour.DayOfWeek, hour.OpenTime, hour.CloseTime)
+		if err != nil {
+			sr.Logger.Error(fmt.Errorf("ScheduleRepository.ReplaceOpeningHours Exec Insert ERROR %v MSG %s", err, err.Error()))
+			if errRollback := tx.Rollback(sr.Context); errRollback != nil {
+				sr.Logger.Error(fmt.Errorf("ScheduleRepository.ReplaceOpeningHours Exec Insert Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+			}
+
+			return err
+		}
+	}
+
+	err = tx.Commit(sr.Context)
+	if err != nil {
+		sr.Logger.Error(fmt.Errorf("ScheduleRepository.ReplaceOpeningHours Commit ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// UpsertHoliday repository layer for executing command adding a holiday into lapak, note is replaced when the date already exists
+func (sr *ScheduleRepository) UpsertHoliday(lapakID uuid.UUID, holiday model.LapakHoliday) error {
+	q := `INSERT INTO lapak_holidays (lapak_id,holiday_date,note) VALUES ($1,$2::DATE,NULLIF($3,''))
+		ON CONFLICT (lapak_id, holiday_date) DO UPDATE SET note = EXCLUDED.note
+	`
+
+	_, err := sr.DB.Exec(sr.Context, q, lapakID, holiday.Date, holiday.Note)
+	if err != nil {
+		sr.Logger.Error(fmt.Errorf("ScheduleRepository.UpsertHoliday Exec ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// DeleteHoliday repository layer for executing command removing a holiday of lapak
+func (sr *ScheduleRepository) DeleteHoliday(lapakID uuid.UUID, date string) error {
+	q := `DELETE FROM lapak_holidays WHERE lapak_id = $1 AND holiday_date = $2::DATE`
+
+	tag, err := sr.DB.Exec(sr.Context, q, lapakID, date)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = model.ErrHolidayNotFound
+	}
+
+	if err != nil {
+		sr.Logger.Error(fmt.Errorf("ScheduleRepository.DeleteHoliday Exec ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// ApplyScheduledStatus repository layer for executing command moving every given lapak into scheduled status,
+// lapak of blacklisted or unverified seller is left untouched when opening so it is retried on the next run
+func (sr *ScheduleRepository) ApplyScheduledStatus(ids []uuid.UUID, status string, requireEmailVerified bool) (int64, error) {
+	q := `UPDATE lapak l
+		SET scheduled_status = $1, status = $1, updated_at = now()
+		WHERE l.id = ANY($2) AND l.deleted_at IS NULL
+		AND ($1 = 'closed' OR (` + activeBlacklistCriteria + `
+			AND (NOT $3 OR EXISTS (SELECT 1 FROM users u WHERE u.id = l.user_id AND u.email_verified_at IS NOT NULL))))
+	`
+
+	tag, err := sr.DB.Exec(sr.Context, q, status, ids, requireEmailVerified)
+	if err != nil {
+		sr.Logger.Error(fmt.Errorf("ScheduleRepository.ApplyScheduledStatus Exec ERROR %v MSG %s", err, err.Error()))
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
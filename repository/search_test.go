@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wiormiw/GrowBaks/model"
+)
+
+// injectionPayloads is filter value which must only ever be matched literally
+var injectionPayloads = []string{`' OR 1=1--`, `%`, `_`, `'; DROP TABLE users; --`}
+
+func TestListFilterMatchesInjectionLiterally(t *testing.T) {
+	conn := testConnect(t, testDSN(t))
+	ctx, cfg, logger := testRepositoryDeps()
+	fixture := newTestFixture(t, conn)
+
+	var (
+		location = fixture.location()
+		seller   = fixture.user(0)
+		lapak    = fixture.lapak(seller, 0)
+		_        = fixture.product(lapak, 1, 0)
+	)
+
+	if _, err := conn.Exec(ctx, `UPDATE lapak SET location_id = $1 WHERE id = $2`, location, lapak); err != nil {
+		t.Fatalf("move fixture lapak: %v", err)
+	}
+
+	var (
+		userRepo     = &UserRepository{Context: ctx, Config: cfg, Logger: logger, DB: conn}
+		lapakRepo    = &LapakRepository{Context: ctx, Config: cfg, Logger: logger, DB: conn}
+		locationRepo = &LocationRepository{Context: ctx, Config: cfg, Logger: logger, DB: conn}
+		productRepo  = &ProductRepository{Context: ctx, Config: cfg, Logger: logger, DB: conn}
+	)
+
+	page := func(sort string) model.PageRequest {
+		return model.PageRequest{Page: 1, Size: 10, Sort: sort, Order: "asc"}
+	}
+
+	// every filter returns number of row matched by given value
+	filters := []struct {
+		name  string
+		count func(value string) (int, error)
+	}{
+		{"user search", func(value string) (int, error) {
+			_, total, err := userRepo.GetAll(value, true, page("created_at"))
+			return total, err
+		}},
+		{"lapak search", func(value string) (int, error) {
+			_, total, err := lapakRepo.GetAllLapak(value, true, page("name"))
+			return total, err
+		}},
+		{"lapak by location search", func(value string) (int, error) {
+			_, total, err := lapakRepo.GetAllLapakByLocation(value, "Fixture", true, page("name"))
+			return total, err
+		}},
+		{"lapak by location daerah", func(value string) (int, error) {
+			_, total, err := lapakRepo.GetAllLapakByLocation("Fixture", value, true, page("name"))
+			return total, err
+		}},
+		{"location provinsi", func(value string) (int, error) {
+			_, total, err := locationRepo.GetAllLocation(model.LocationFilter{Provinsi: value}, page("daerah"))
+			return total, err
+		}},
+		{"location kota", func(value string) (int, error) {
+			_, total, err := locationRepo.GetAllLocation(model.LocationFilter{Kota: value}, page("daerah"))
+			return total, err
+		}},
+		{"location search", func(value string) (int, error) {
+			_, total, err := locationRepo.GetAllLocation(model.LocationFilter{Search: value}, page("daerah"))
+			return total, err
+		}},
+		{"kota of provinsi", func(value string) (int, error) {
+			data, err := locationRepo.GetAllKota(value)
+			return len(data), err
+		}},
+		{"product search", func(value string) (int, error) {
+			_, total, err := productRepo.GetAllProduct(model.ProductFilter{Search: value, IncludeDeleted: true}, page("created_at"))
+			return total, err
+		}},
+		{"product search by relevance", func(value string) (int, error) {
+			data, _, err := productRepo.GetAllProduct(model.ProductFilter{Search: value, IncludeDeleted: true}, page("relevance"))
+			return len(data), err
+		}},
+		{"product daerah", func(value string) (int, error) {
+			_, total, err := productRepo.GetAllProduct(model.ProductFilter{Daerah: value, IncludeDeleted: true}, page("created_at"))
+			return total, err
+		}},
+		{"product feed search", func(value string) (int, error) {
+			_, total, err := productRepo.GetProductFeed(model.ProductFilter{Search: value}, 10, nil)
+			return total, err
+		}},
+		{"product facets search", func(value string) (int, error) {
+			facets, err := productRepo.GetProductFacets(model.ProductFilter{Search: value})
+
+			total := 0
+			for _, facet := range facets["kategori"] {
+				total += facet.Count
+			}
+
+			return total, err
+		}},
+		{"product suggestion", func(value string) (int, error) {
+			data, err := productRepo.SuggestProduct(value, 10)
+			return len(data), err
+		}},
+	}
+
+	for _, filter := range filters {
+		t.Run(filter.name, func(t *testing.T) {
+			// fixture is reachable through this filter, so an empty result below comes from the payload itself
+			if total, err := filter.count("Fixture"); err != nil || total == 0 {
+				t.Fatalf("Fixture matched %d rows with error %v, want at least one", total, err)
+			}
+
+			for _, payload := range injectionPayloads {
+				total, err := filter.count(payload)
+				if err != nil {
+					t.Errorf("%q: unexpected error %v", payload, err)
+					continue
+				}
+
+				if total != 0 {
+					t.Errorf("%q matched %d rows, want 0", payload, total)
+				}
+			}
+		})
+	}
+
+	var usersExists bool
+	if err := conn.QueryRow(ctx, `SELECT to_regclass('users') IS NOT NULL`).Scan(&usersExists); err != nil || !usersExists {
+		t.Fatalf("users table is gone after filtering, error %v", err)
+	}
+}
+
+func TestProductSearchUsesIndex(t *testing.T) {
+	conn := testConnect(t, testDSN(t))
+	ctx, cfg, logger := testRepositoryDeps()
+
+	// fixture sized table is always cheaper to scan, so seq scan is priced out to see whether an index can serve the search at all
+	if _, err := conn.Exec(ctx, `SET enable_seqscan = off`); err != nil {
+		t.Fatalf("disable seq scan: %v", err)
+	}
+
+	productRepo := &ProductRepository{Context: ctx, Config: cfg, Logger: logger, DB: conn}
+	q, args := productRepo.productListQuery(model.ProductFilter{Search: "baso malang"}).Build()
+
+	rows, err := conn.Query(ctx, "EXPLAIN "+q, args...)
+	if err != nil {
+		t.Fatalf("explain product search: %v", err)
+	}
+	defer rows.Close()
+
+	var plan strings.Builder
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			t.Fatalf("read plan: %v", err)
+		}
+
+		plan.WriteString(line + "\n")
+	}
+
+	if err := rows.Err(); err != nil {
+		t.Fatalf("read plan: %v", err)
+	}
+
+	for _, index := range []string{"products_search_vector_idx", "products_name_trgm_idx", "products_lapak_id_idx", "lapak_name_trgm_idx"} {
+		if !strings.Contains(plan.String(), index) {
+			t.Errorf("search does not use %s, plan\n%s", index, plan.String())
+		}
+	}
+}
@@ -9,19 +9,21 @@ import (
 	"github.com/jackc/pgx/v4"
 	"github.com/sirupsen/logrus"
 	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/helper"
 	"github.com/wiormiw/GrowBaks/model"
 )
 
 type (
 	// IAuthRepository is an interface that has all the function to be implemented inside auth repository
 	IUserRepository interface {
-		GetAll(search string) ([]model.ViewUserResponse, error)
+		GetAll(search string, includeDeleted bool, page model.PageRequest) ([]model.ViewUserResponse, int, error)
 		GetByEmail(email string) (*model.ViewUserResponse, error)
 		GetByID(id uuid.UUID) (*model.ViewUserResponse, error)
 		GetProfileByID(id uuid.UUID) (*model.ViewUserProfileResponse, error)
 		UpdateByID(id uuid.UUID, full_name string, email string, password string) error
-		UpdateProfileByID(id uuid.UUID, email string, full_name string, tanggal_lahir string, gender string, telepon string) error
+		UpdateProfileByID(id uuid.UUID, email string, full_name string, tanggal_lahir string, gender string, telepon string, locationID *uuid.UUID, latitude *float64, longitude *float64) error
 		DeleteByID(id uuid.UUID) error
+		RestoreByID(id uuid.UUID) error
 	}
 
 	// AuthRepository is an app auth struct that consists of all the dependencies needed for auth repository
@@ -33,54 +35,70 @@ type (
 	}
 )
 
+// userSortColumns whitelist sortable column of user list
+var userSortColumns = map[string]string{
+	"full_name":  "full_name",
+	"email":      "email",
+	"created_at": "created_at",
+}
+
 // GetAll repository layer for querying command getting all user
-func (ur *UserRepository) GetAll(search string) ([]model.ViewUserResponse, error) {
+func (ur *UserRepository) GetAll(search string, includeDeleted bool, page model.PageRequest) ([]model.ViewUserResponse, int, error) {
 	q := `
 		SELECT
 			id,
 			full_name,
 			email,
 			created_at,
-			updated_at
+			updated_at,
+			email_verified_at,
+			deleted_at
 		FROM "users"
 	`
-	criteria := ""
-	criteria = ""
+	qb := helper.NewQuerySelectBuilder(q)
+
+	if !includeDeleted {
+		qb.Where("deleted_at IS NULL")
+	}
 
 	if len(search) > 0 {
-		criteria += " full_name LIKE '%" + search + "%'"
+		qb.Where("full_name LIKE ?", helper.LikeContains(search))
 	}
 
-	cr := ""
-	if len(criteria) > 0 {
-		cr = " WHERE " + criteria
+	total, err := countRows(ur.Context, ur.DB, qb)
+	if err != nil {
+		ur.Logger.Error(fmt.Errorf("UserRepository.GetAll Count ERROR %v MSG %s", err, err.Error()))
+		return nil, 0, err
 	}
 
-	if cr != "" {
-		q += cr
+	err = paginate(qb, page, userSortColumns, "id")
+	if err != nil {
+		return nil, 0, err
 	}
 
+	q, args := qb.Build()
+
 	ur.Logger.Info(fmt.Sprintf("Query : %s", q))
 
-	rows, err := ur.DB.Query(ur.Context, q)
+	rows, err := ur.DB.Query(ur.Context, q, args...)
 	if err != nil {
 		ur.Logger.Error(fmt.Errorf("UserRepository.GetAll Query ERROR %v MSG %s", err, err.Error()))
-		return nil, err
+		return nil, 0, err
 	}
 
 	var listData []model.ViewUserResponse
 	for rows.Next() {
 		data := &model.ViewUserResponse{}
-		err := rows.Scan(&data.ID, &data.FullName, &data.Email, &data.CreatedAt, &data.UpdatedAt)
+		err := rows.Scan(&data.ID, &data.FullName, &data.Email, &data.CreatedAt, &data.UpdatedAt, &data.EmailVerifiedAt, &data.DeletedAt)
 		if err != nil {
 			ur.Logger.Error(fmt.Errorf("UserRepository.GetAll rows.Next Scan ERROR %v MSG %s", err, err.Error()))
-			return nil, err
+			return nil, 0, err
 		}
 
 		listData = append(listData, *data)
 	}
 
-	return listData, nil
+	return listData, total, nil
 }
 
 // GetByEmail repository layer for querying command get a user by email
@@ -93,13 +111,14 @@ func (ur *UserRepository) GetByEmail(email string) (*model.ViewUserResponse, err
 			full_name,
 			email,
 			created_at,
-			updated_at
+			updated_at,
+			email_verified_at
 		FROM "users"
-		WHERE email = $1
+		WHERE email = $1 AND deleted_at IS NULL
 	`
 
 	row := ur.DB.QueryRow(ur.Context, q, email)
-	err := row.Scan(&user.ID, &user.FullName, &user.Email, &user.CreatedAt, &user.UpdatedAt)
+	err := row.Scan(&user.ID, &user.FullName, &user.Email, &user.CreatedAt, &user.UpdatedAt, &user.EmailVerifiedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			ur.Logger.Info(fmt.Errorf("UserRepository.GetByEmail Scan INFO %v MSG %s", err, err.Error()))
@@ -123,13 +142,14 @@ func (ur *UserRepository) GetByID(id uuid.UUID) (*model.ViewUserResponse, error)
 			full_name,
 			email,
 			created_at,
-			updated_at
+			updated_at,
+			email_verified_at
 		FROM "users"
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	row := ur.DB.QueryRow(ur.Context, q, id)
-	err := row.Scan(&user.ID, &user.FullName, &user.Email, &user.CreatedAt, &user.UpdatedAt)
+	err := row.Scan(&user.ID, &user.FullName, &user.Email, &user.CreatedAt, &user.UpdatedAt, &user.EmailVerifiedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			ur.Logger.Info(fmt.Errorf("UserRepository.GetByID Scan INFO %v MSG %s", err, err.Error()))
@@ -155,11 +175,13 @@ func (ur *UserRepository) GetProfileByID(id uuid.UUID) (*model.ViewUserProfileRe
 		up.gender,
 		up.tanggal_lahir,
 		loc.id as location_id,
-		loc.daerah
+		loc.daerah,
+		up.latitude,
+		up.longitude
 		FROM "users_profile" up
 		LEFT JOIN users u ON up.user_id = u.id
 		LEFT JOIN "lokasi" loc ON loc.id = up.location_id
-		WHERE user_id = $1
+		WHERE user_id = $1 AND u.deleted_at IS NULL
 	`
 
 	row := ur.DB.QueryRow(ur.Context, q, id)
@@ -172,7 +194,9 @@ func (ur *UserRepository) GetProfileByID(id uuid.UUID) (*model.ViewUserProfileRe
 		&userProfile.Gender,
 		&userProfile.TanggalLahir,
 		&userProfile.LocationID,
-		&userProfile.Daerah)
+		&userProfile.Daerah,
+		&userProfile.Latitude,
+		&userProfile.Longitude)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			ur.Logger.Info(fmt.Errorf("UserRepository.GetProfileByID Scan INFO %v MSG %s", err, err.Error()))
@@ -189,10 +213,12 @@ func (ur *UserRepository) GetProfileByID(id uuid.UUID) (*model.ViewUserProfileRe
 // UpdateByID repository layer for executing command update a user by id
 func (ur *UserRepository) UpdateByID(id uuid.UUID, full_name string, email string, password string) error {
 
+	// changed email is not verified yet, so verification is reset along with it
 	q := ` UPDATE users
 		SET full_name = $1,
 		email = $2,
 		password = $3,
+		email_verified_at = CASE WHEN email IS DISTINCT FROM $2 THEN NULL ELSE email_verified_at END,
 		updated_at = $4
 	    WHERE id = $5
 	`
@@ -208,10 +234,12 @@ func (ur *UserRepository) UpdateByID(id uuid.UUID, full_name string, email strin
 	return nil
 }
 
-func (ur *UserRepository) UpdateProfileByID(id uuid.UUID, email string, full_name string, tanggal_lahir string, gender string, telepon string) error {
+func (ur *UserRepository) UpdateProfileByID(id uuid.UUID, email string, full_name string, tanggal_lahir string, gender string, telepon string, locationID *uuid.UUID, latitude *float64, longitude *float64) error {
+	// changed email is not verified yet, so verification is reset along with it
 	q1 := ` UPDATE users
 		SET full_name = $1,
 		email = $2,
+		email_verified_at = CASE WHEN email IS DISTINCT FROM $2 THEN NULL ELSE email_verified_at END,
 		updated_at = $3
 	    WHERE id = $4
 	`
@@ -222,14 +250,18 @@ func (ur *UserRepository) UpdateProfileByID(id uuid.UUID, email string, full_nam
 		return err
 	}
 
+	// coordinate pinned inside previous location is dropped on relocation unless a new one is sent along
 	q2 := ` UPDATE users_profile
 		SET telepon = $1,
 		gender = $2,
 		tanggal_lahir = $3,
-		updated_at = $4
-	    WHERE user_id = $5
+		location_id = COALESCE($4, location_id),
+		latitude = CASE WHEN $4::uuid IS NOT NULL AND $4::uuid IS DISTINCT FROM location_id THEN $5 ELSE COALESCE($5, latitude) END,
+		longitude = CASE WHEN $4::uuid IS NOT NULL AND $4::uuid IS DISTINCT FROM location_id THEN $6 ELSE COALESCE($6, longitude) END,
+		updated_at = $7
+	    WHERE user_id = $8
 	`
-	_, err = ur.DB.Exec(ur.Context, q2, telepon, gender, tanggal_lahir, time.Now(), id)
+	_, err = ur.DB.Exec(ur.Context, q2, telepon, gender, tanggal_lahir, locationID, latitude, longitude, time.Now(), id)
 	if err != nil {
 		ur.Logger.Error(fmt.Errorf("UserRepository.UpdateProfileByID User Profile Data ERROR : %v MSG : %s", err, err.Error()))
 		return err
@@ -238,29 +270,112 @@ func (ur *UserRepository) UpdateProfileByID(id uuid.UUID, email string, full_nam
 	return nil
 }
 
-// UpdateByID repository layer for executing command delete a user by id
+// DeleteByID repository layer for executing command soft deleting a user along with their lapak & products
 func (ur *UserRepository) DeleteByID(id uuid.UUID) error {
-	q := `DELETE FROM "users" WHERE id = $1`
+	tx, err := ur.DB.Begin(ur.Context)
+	if err != nil {
+		ur.Logger.Error(fmt.Errorf("UserRepository.DeleteByID Begin ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
+
+	// now() is fixed for the whole transaction, restore matches cascaded rows by this exact deleted_at
+	queries := []struct {
+		name  string
+		query string
+	}{
+		{"Users", `UPDATE "users" SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL`},
+		{"Products", `UPDATE products SET deleted_at = now() WHERE deleted_at IS NULL AND lapak_id IN (SELECT id FROM lapak WHERE user_id = $1 AND deleted_at IS NULL)`},
+		{"Lapak", `UPDATE lapak SET deleted_at = now() WHERE user_id = $1 AND deleted_at IS NULL`},
+		{"Cart", `DELETE FROM cart_items WHERE user_id = $1 OR product_id IN (SELECT p.id FROM products p JOIN lapak l ON l.id = p.lapak_id WHERE l.user_id = $1)`},
+		{"Refresh Token", `UPDATE refresh_tokens SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL`},
+	}
+
+	for i, q := range queries {
+		tag, err := tx.Exec(ur.Context, q.query, id)
+		if err == nil && i == 0 && tag.RowsAffected() == 0 {
+			err = model.ErrUserNotFound
+		}
+
+		if err != nil {
+			ur.Logger.Error(fmt.Errorf("UserRepository.DeleteByID Exec %s ERROR %v MSG %s", q.name, err, err.Error()))
+			if errRollback := tx.Rollback(ur.Context); errRollback != nil {
+				ur.Logger.Error(fmt.Errorf("UserRepository.DeleteByID Exec %s Rollback ERROR %v MSG %s", q.name, errRollback, errRollback.Error()))
+			}
+
+			return err
+		}
+	}
+
+	err = tx.Commit(ur.Context)
+	if err != nil {
+		ur.Logger.Error(fmt.Errorf("UserRepository.DeleteByID Commit ERROR %v MSG %s", err, err.Error()))
+		return err
+	}
 
-	_, err := ur.DB.Exec(ur.Context, q, id)
+	return nil
+}
+
+// RestoreByID repository layer for executing command restoring a soft deleted user along with lapak & products deleted together with them
+func (ur *UserRepository) RestoreByID(id uuid.UUID) error {
+	tx, err := ur.DB.Begin(ur.Context)
 	if err != nil {
-		ur.Logger.Error(fmt.Errorf("UserRepository.DeleteByID Exec Users ERROR %v MSG %s", err, err.Error()))
+		ur.Logger.Error(fmt.Errorf("UserRepository.RestoreByID Begin ERROR %v MSG %s", err, err.Error()))
 		return err
 	}
 
-	q2 := `DELETE FROM "users_profile" WHERE user_id = $1`
+	var deletedAt time.Time
 
-	_, err = ur.DB.Exec(ur.Context, q2, id)
+	q := `SELECT deleted_at FROM "users" WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE`
+	err = tx.QueryRow(ur.Context, q, id).Scan(&deletedAt)
 	if err != nil {
-		ur.Logger.Error(fmt.Errorf("UserRepository.DeleteByID Exec Users Profile ERROR %v MSG %s", err, err.Error()))
+		if err == pgx.ErrNoRows {
+			err = model.ErrUserNotFound
+		}
+
+		ur.Logger.Error(fmt.Errorf("UserRepository.RestoreByID QueryRow Users ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(ur.Context); errRollback != nil {
+			ur.Logger.Error(fmt.Errorf("UserRepository.RestoreByID QueryRow Users Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
 		return err
 	}
 
-	q3 := `DELETE FROM "lapak" WHERE user_id = $1`
+	q1 := `UPDATE "users" SET deleted_at = NULL WHERE id = $1`
+	_, err = tx.Exec(ur.Context, q1, id)
+	if err != nil {
+		ur.Logger.Error(fmt.Errorf("UserRepository.RestoreByID Exec Users ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(ur.Context); errRollback != nil {
+			ur.Logger.Error(fmt.Errorf("UserRepository.RestoreByID Exec Users Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	q2 := `UPDATE products SET deleted_at = NULL WHERE deleted_at = $2 AND lapak_id IN (SELECT id FROM lapak WHERE user_id = $1 AND deleted_at = $2)`
+	_, err = tx.Exec(ur.Context, q2, id, deletedAt)
+	if err != nil {
+		ur.Logger.Error(fmt.Errorf("UserRepository.RestoreByID Exec Products ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(ur.Context); errRollback != nil {
+			ur.Logger.Error(fmt.Errorf("UserRepository.RestoreByID Exec Products Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
+
+	q3 := `UPDATE lapak SET deleted_at = NULL WHERE user_id = $1 AND deleted_at = $2`
+	_, err = tx.Exec(ur.Context, q3, id, deletedAt)
+	if err != nil {
+		ur.Logger.Error(fmt.Errorf("UserRepository.RestoreByID Exec Lapak ERROR %v MSG %s", err, err.Error()))
+		if errRollback := tx.Rollback(ur.Context); errRollback != nil {
+			ur.Logger.Error(fmt.Errorf("UserRepository.RestoreByID Exec Lapak Rollback ERROR %v MSG %s", errRollback, errRollback.Error()))
+		}
+
+		return err
+	}
 
-	_, err = ur.DB.Exec(ur.Context, q3, id)
+	err = tx.Commit(ur.Context)
 	if err != nil {
-		ur.Logger.Error(fmt.Errorf("UserRepository.DeleteByID Exec Lapak ERROR %v MSG %s", err, err.Error()))
+		ur.Logger.Error(fmt.Errorf("UserRepository.RestoreByID Commit ERROR %v MSG %s", err, err.Error()))
 		return err
 	}
 
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestUpdateProfileByIDDropsCoordinateOnRelocation(t *testing.T) {
+	conn := testConnect(t, testDSN(t))
+	ctx, cfg, logger := testRepositoryDeps()
+	fixture := newTestFixture(t, conn)
+
+	var (
+		latitude, longitude = -6.9, 107.6
+		previous            = fixture.location()
+		next                = fixture.location()
+		user                = fixture.user(0)
+		_                   = fixture.profile(user, previous, &latitude, &longitude)
+		userRepo            = &UserRepository{Context: ctx, Config: cfg, Logger: logger, DB: conn}
+	)
+
+	relocate := func(locationID uuid.UUID) (*float64, *float64) {
+		t.Helper()
+
+		err := userRepo.UpdateProfileByID(user, uuid.NewString()+"@fixture.test", "Fixture", "2000-01-01", "perempuan", "081234567890", &locationID, nil, nil)
+		if err != nil {
+			t.Fatalf("update profile: %v", err)
+		}
+
+		var lat, lng *float64
+		if err := conn.QueryRow(ctx, `SELECT latitude, longitude FROM users_profile WHERE user_id = $1`, user).Scan(&lat, &lng); err != nil {
+			t.Fatalf("read coordinate: %v", err)
+		}
+
+		return lat, lng
+	}
+
+	if lat, lng := relocate(previous); lat == nil || lng == nil {
+		t.Errorf("coordinate dropped while location is unchanged")
+	}
+
+	if lat, lng := relocate(next); lat != nil || lng != nil {
+		t.Errorf("coordinate %v, %v kept after moving into another location", *lat, *lng)
+	}
+}
+
+func TestUpdateByIDResetsVerificationOfChangedEmail(t *testing.T) {
+	conn := testConnect(t, testDSN(t))
+	ctx, cfg, logger := testRepositoryDeps()
+	fixture := newTestFixture(t, conn)
+
+	var (
+		user     = fixture.user(0)
+		_        = fixture.profile(user, fixture.location(), nil, nil)
+		userRepo = &UserRepository{Context: ctx, Config: cfg, Logger: logger, DB: conn}
+		email    string
+	)
+
+	if err := conn.QueryRow(ctx, `SELECT email FROM users WHERE id = $1`, user).Scan(&email); err != nil {
+		t.Fatalf("read fixture email: %v", err)
+	}
+
+	updates := []struct {
+		name   string
+		update func(email string) error
+	}{
+		{"account", func(email string) error {
+			return userRepo.UpdateByID(user, "Fixture", email, "-")
+		}},
+		{"profile", func(email string) error {
+			return userRepo.UpdateProfileByID(user, email, "Fixture", "2000-01-01", "perempuan", "081234567890", nil, nil, nil)
+		}},
+	}
+
+	for _, tc := range updates {
+		t.Run(tc.name, func(t *testing.T) {
+			verified := func() bool {
+				t.Helper()
+
+				var verified bool
+				if err := conn.QueryRow(ctx, `SELECT email_verified_at IS NOT NULL FROM users WHERE id = $1`, user).Scan(&verified); err != nil {
+					t.Fatalf("read verification: %v", err)
+				}
+
+				return verified
+			}
+
+			if _, err := conn.Exec(ctx, `UPDATE users SET email_verified_at = now() WHERE id = $1`, user); err != nil {
+				t.Fatalf("verify fixture user: %v", err)
+			}
+
+			if err := tc.update(email); err != nil {
+				t.Fatalf("resubmit own email: %v", err)
+			}
+
+			if !verified() {
+				t.Errorf("unchanged email lost its verification")
+			}
+
+			email = uuid.NewString() + "@fixture.test"
+			if err := tc.update(email); err != nil {
+				t.Fatalf("change email: %v", err)
+			}
+
+			if verified() {
+				t.Errorf("changed email is still verified")
+			}
+		})
+	}
+}
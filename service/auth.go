@@ -20,21 +20,42 @@ type (
 	// IAuthService is an interface that has all the function to be implemented inside auth service
 	IAuthService interface {
 		Create(user model.CreateUserRequest) error
-		LoginSvc(user model.LoginRequest) (*model.SuccessLoginResponse, error)
+		LoginSvc(user model.LoginRequest) (*model.SuccessLoginResponse, *model.MFAChallengeResponse, error)
+		LoginMFASvc(req model.MFALoginRequest) (*model.SuccessLoginResponse, error)
+		RefreshSvc(req model.RefreshTokenRequest) (*model.SuccessLoginResponse, error)
+		LogoutSvc(payload util.DecodePayloadData, req model.LogoutRequest) error
+		ForgotPasswordSvc(req model.ForgotPasswordRequest) error
+		ResetPasswordSvc(req model.ResetPasswordRequest) error
+		VerifyEmailSvc(token string) error
+		ResendVerificationSvc(req model.ResendVerificationRequest) error
+		GoogleLoginSvc(req model.OAuthLoginRequest) (*model.OAuthLoginResponse, error)
+		GoogleCallbackSvc(req model.OAuthCallbackRequest) (*model.SuccessLoginResponse, *model.MFAChallengeResponse, error)
 	}
 
 	// AuthService is an app auth struct that consists of all the dependencies needed for auth service
 	AuthService struct {
-		Context      context.Context
-		Config       *config.Configuration
-		Logger       *logrus.Logger
-		AuthRepo     repository.IAuthRepository
-		RoleRepo     repository.IRoleRepository
-		LocationRepo repository.ILocationRepository
-		LapakRepo    repository.ILapakRepository
+		Context       context.Context
+		Config        *config.Configuration
+		Logger        *logrus.Logger
+		AuthRepo      repository.IAuthRepository
+		RoleRepo      repository.IRoleRepository
+		LocationRepo  repository.ILocationRepository
+		LapakRepo     repository.ILapakRepository
+		BlacklistRepo repository.IBlacklistRepository
+		MFARepo       repository.IMFARepository
+		Mailer        util.Mailer
+		GoogleOAuth   *util.OAuthProvider
 	}
 )
 
+const (
+	passwordResetExpire     time.Duration = time.Duration(30) * time.Minute
+	emailVerificationExpire time.Duration = time.Duration(24) * time.Hour
+	oauthStateExpire        time.Duration = time.Duration(10) * time.Minute
+	mfaChallengeExpire      time.Duration = time.Duration(5) * time.Minute
+	mfaChallengeMaxAttempts int           = 5
+)
+
 // Create service layer for handling create a user
 func (as *AuthService) Create(user model.CreateUserRequest) error {
 	err := validateRegisterAuthorRequest(&user)
@@ -81,12 +102,20 @@ func (as *AuthService) Create(user model.CreateUserRequest) error {
 				return err
 			}
 
-			err = as.AuthRepo.CreateUser(user, user.IsSeller)
+			user.LoginProvider = model.LoginProviderPassword
+
+			userID, err := as.AuthRepo.CreateUser(user, user.IsSeller)
 			if err != nil {
 				as.Logger.Error(fmt.Sprintf("Error 11: %s", err))
 				return err
 			}
 
+			// account is already created, failed delivery can be retried through resend endpoint
+			err = sendEmailVerification(as.Config, as.AuthRepo, as.Mailer, userID, user.FullName, user.Email)
+			if err != nil {
+				as.Logger.Error(fmt.Errorf("AuthService.Create sendEmailVerification ERROR : %v MSG : %s", err, err.Error()))
+			}
+
 			return nil
 		}
 		as.Logger.Error(fmt.Sprintf("Error 12: %s", err))
@@ -97,13 +126,57 @@ func (as *AuthService) Create(user model.CreateUserRequest) error {
 }
 
 // LoginSvc service layer for handling user login (author,superadmin)
-func (as *AuthService) LoginSvc(user model.LoginRequest) (*model.SuccessLoginResponse, error) {
+func (as *AuthService) LoginSvc(user model.LoginRequest) (*model.SuccessLoginResponse, *model.MFAChallengeResponse, error) {
 	err := validateLoginRequest(&user)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	getUser, err := as.AuthRepo.GetUserByEmail(user.Email)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil, model.ErrUserNotFound
+		}
+
+		return nil, nil, err
+	}
+
+	if getUser.LoginProvider != model.LoginProviderPassword {
+		return nil, nil, model.ErrMismatchLogin
+	}
+
+	if !helper.CheckPasswordHash(getUser.Password, user.Password) {
+		return nil, nil, model.ErrInvalidPassword
+	}
+
+	err = checkUserBlacklisted(as.BlacklistRepo, getUser.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return as.startLoginSession(getUser)
+}
+
+// LoginMFASvc service layer for finishing two-step login with TOTP or recovery code
+func (as *AuthService) LoginMFASvc(req model.MFALoginRequest) (*model.SuccessLoginResponse, error) {
+	if req.MFAToken == "" || req.Code == "" {
+		return nil, model.ErrInvalidRequest
+	}
+
+	challenge, err := as.MFARepo.GetMFAChallengeByHash(helper.HashToken(req.MFAToken))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, model.ErrInvalidMFAToken
+		}
+
+		return nil, err
+	}
+
+	if challenge.UsedAt != nil || time.Now().After(challenge.ExpiredAt) || challenge.Attempts >= mfaChallengeMaxAttempts {
+		return nil, model.ErrInvalidMFAToken
+	}
+
+	userMFA, err := as.MFARepo.GetUserMFA(challenge.UserID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, model.ErrUserNotFound
@@ -112,20 +185,453 @@ func (as *AuthService) LoginSvc(user model.LoginRequest) (*model.SuccessLoginRes
 		return nil, err
 	}
 
-	if !helper.CheckPasswordHash(getUser.Password, user.Password) {
-		return nil, model.ErrInvalidPassword
+	valid, err := verifyMFACode(as.MFARepo, userMFA, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !valid {
+		err = as.MFARepo.IncrementMFAChallengeAttempt(challenge.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, model.ErrInvalidMFACode
+	}
+
+	err = as.MFARepo.ConsumeMFAChallenge(challenge.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	getUser, err := as.AuthRepo.GetUserByID(challenge.UserID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, model.ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	err = checkUserBlacklisted(as.BlacklistRepo, getUser.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return as.createSession(getUser)
+}
+
+// RefreshSvc service layer for rotating a refresh token into a new access & refresh token
+func (as *AuthService) RefreshSvc(req model.RefreshTokenRequest) (*model.SuccessLoginResponse, error) {
+	if req.RefreshToken == "" {
+		return nil, model.ErrInvalidRequest
+	}
+
+	stored, err := as.AuthRepo.GetRefreshTokenByHash(helper.HashToken(req.RefreshToken))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, model.ErrInvalidRefreshToken
+		}
+
+		return nil, err
+	}
+
+	// a revoked token being replayed means it was leaked, kill every session of the owner
+	if stored.RevokedAt != nil {
+		as.Logger.Warn(fmt.Sprintf("AuthService.RefreshSvc reuse of revoked refresh token detected for user %s", stored.UserID))
+		err = as.AuthRepo.RevokeAllRefreshTokenByUser(stored.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, model.ErrInvalidRefreshToken
+	}
+
+	if time.Now().After(stored.ExpiredAt) {
+		return nil, model.ErrInvalidRefreshToken
+	}
+
+	getUser, err := as.AuthRepo.GetUserByID(stored.UserID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, model.ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	err = checkUserBlacklisted(as.BlacklistRepo, getUser.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken := helper.GenerateRandomToken(64)
+	refreshExpiredAt := time.Now().Add(util.RefreshTokenExpire)
+
+	err = as.AuthRepo.RotateRefreshToken(stored.ID, stored.UserID, helper.HashToken(refreshToken), refreshExpiredAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return as.buildLoginResponse(getUser, refreshToken, refreshExpiredAt)
+}
+
+// LogoutSvc service layer for revoking current access token and the refresh token(s) of a user
+func (as *AuthService) LogoutSvc(payload util.DecodePayloadData, req model.LogoutRequest) error {
+	userID, err := uuid.Parse(payload.UserID)
+	if err != nil {
+		return model.ErrInvalidToken
+	}
+
+	tokenID, err := uuid.Parse(payload.TokenID)
+	if err != nil {
+		return model.ErrInvalidToken
+	}
+
+	err = as.BlacklistRepo.RevokeToken(tokenID, userID, model.ReasonLogout, payload.ExpiredAt)
+	if err != nil {
+		return err
+	}
+
+	if req.All {
+		return as.AuthRepo.RevokeAllRefreshTokenByUser(userID)
+	}
+
+	if req.RefreshToken != "" {
+		return as.AuthRepo.RevokeRefreshTokenByHash(userID, helper.HashToken(req.RefreshToken))
+	}
+
+	return nil
+}
+
+// ForgotPasswordSvc service layer for sending single-use reset password token into user email
+func (as *AuthService) ForgotPasswordSvc(req model.ForgotPasswordRequest) error {
+	if !model.IsAllowedEmailInput.MatchString(req.Email) {
+		return model.ErrInvalidRequest
+	}
+
+	getUser, err := as.AuthRepo.GetUserByEmail(req.Email)
+	if err != nil {
+		// never tell the client whether the email is registered or not
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+
+		return err
+	}
+
+	// oauth-only account has no password to reset
+	if getUser.LoginProvider != model.LoginProviderPassword {
+		return nil
+	}
+
+	token := helper.GenerateRandomToken(64)
+
+	err = as.AuthRepo.CreatePasswordReset(getUser.UserID, helper.HashToken(token), time.Now().Add(passwordResetExpire))
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Halo %s,\r\n\r\nKami menerima permintaan reset password untuk akun GrowBaks kamu.\r\n"+
+		"Buka link berikut untuk membuat password baru (berlaku %d menit):\r\n%s/reset-password?token=%s\r\n\r\n"+
+		"Token: %s\r\n\r\nAbaikan email ini jika kamu tidak merasa meminta reset password.\r\n",
+		getUser.FullName, int(passwordResetExpire.Minutes()), as.Config.Const.FrontendURL, token, token)
+
+	err = as.Mailer.Send(getUser.Email, "Reset Password GrowBaks", body)
+	if err != nil {
+		as.Logger.Error(fmt.Errorf("AuthService.ForgotPasswordSvc Mailer.Send ERROR : %v MSG : %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// ResetPasswordSvc service layer for changing user password with reset token
+func (as *AuthService) ResetPasswordSvc(req model.ResetPasswordRequest) error {
+	if req.Token == "" || len(req.Password) < 8 {
+		return model.ErrInvalidRequest
+	}
+
+	passwordReset, err := as.AuthRepo.GetPasswordResetByHash(helper.HashToken(req.Token))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return model.ErrInvalidResetToken
+		}
+
+		return err
 	}
 
-	jwt, err := util.BuildJWT(as.Config, getUser)
+	if passwordReset.UsedAt != nil || time.Now().After(passwordReset.ExpiredAt) {
+		return model.ErrInvalidResetToken
+	}
+
+	password, err := helper.HashPassword(req.Password)
+	if err != nil {
+		return err
+	}
+
+	return as.AuthRepo.ResetPassword(passwordReset.ID, passwordReset.UserID, password)
+}
+
+// VerifyEmailSvc service layer for marking user email as verified with verification token
+func (as *AuthService) VerifyEmailSvc(token string) error {
+	if token == "" {
+		return model.ErrInvalidRequest
+	}
+
+	emailVerification, err := as.AuthRepo.GetEmailVerificationByHash(helper.HashToken(token))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return model.ErrInvalidVerificationToken
+		}
+
+		return err
+	}
+
+	if emailVerification.UsedAt != nil || time.Now().After(emailVerification.ExpiredAt) {
+		return model.ErrInvalidVerificationToken
+	}
+
+	return as.AuthRepo.VerifyEmail(emailVerification.ID, emailVerification.UserID)
+}
+
+// ResendVerificationSvc service layer for resending email verification token
+func (as *AuthService) ResendVerificationSvc(req model.ResendVerificationRequest) error {
+	if !model.IsAllowedEmailInput.MatchString(req.Email) {
+		return model.ErrInvalidRequest
+	}
+
+	getUser, err := as.AuthRepo.GetUserByEmail(req.Email)
+	if err != nil {
+		// never tell the client whether the email is registered or not
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+
+		return err
+	}
+
+	if getUser.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	return sendEmailVerification(as.Config, as.AuthRepo, as.Mailer, getUser.UserID, getUser.FullName, getUser.Email)
+}
+
+// GoogleLoginSvc service layer for starting google oauth login, storing state & pkce verifier
+func (as *AuthService) GoogleLoginSvc(req model.OAuthLoginRequest) (*model.OAuthLoginResponse, error) {
+	oauthState := model.OAuthState{
+		State:        helper.GenerateRandomToken(32),
+		CodeVerifier: helper.GenerateRandomToken(64),
+		IsSeller:     req.IsSeller,
+		ExpiredAt:    time.Now().Add(oauthStateExpire),
+	}
+
+	// registration data is optional, only used when the google account is not registered yet
+	if req.LocationID != "" {
+		locationID, err := uuid.Parse(req.LocationID)
+		if err != nil {
+			return nil, model.ErrInvalidRequest
+		}
+
+		_, err = as.LocationRepo.GetLocationByID(locationID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return nil, model.ErrInvalidRequest
+			}
+
+			return nil, err
+		}
+
+		oauthState.LocationID = &locationID
+	}
+
+	if req.Telepon != "" {
+		if !model.IsAllowedTeleponInput.MatchString(req.Telepon) {
+			return nil, model.ErrInvalidRequest
+		}
+
+		oauthState.Telepon = req.Telepon
+	}
+
+	err := as.AuthRepo.CreateOAuthState(oauthState)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.OAuthLoginResponse{
+		AuthURL: as.GoogleOAuth.AuthCodeURL(oauthState.State, oauthState.CodeVerifier),
+		State:   oauthState.State,
+	}, nil
+}
+
+// GoogleCallbackSvc service layer for finishing google oauth login, linking or creating user then issuing tokens
+func (as *AuthService) GoogleCallbackSvc(req model.OAuthCallbackRequest) (*model.SuccessLoginResponse, *model.MFAChallengeResponse, error) {
+	if req.State == "" || req.Code == "" {
+		return nil, nil, model.ErrInvalidExchange
+	}
+
+	oauthState, err := as.AuthRepo.ConsumeOAuthState(req.State)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil, model.ErrInvalidExchange
+		}
+
+		return nil, nil, err
+	}
+
+	accessToken, err := as.GoogleOAuth.Exchange(req.Code, oauthState.CodeVerifier)
+	if err != nil {
+		as.Logger.Error(fmt.Errorf("AuthService.GoogleCallbackSvc Exchange ERROR : %v MSG : %s", err, err.Error()))
+		return nil, nil, model.ErrInvalidExchange
+	}
+
+	userInfo, err := as.GoogleOAuth.UserInfo(accessToken)
+	if err != nil {
+		as.Logger.Error(fmt.Errorf("AuthService.GoogleCallbackSvc UserInfo ERROR : %v MSG : %s", err, err.Error()))
+		return nil, nil, model.ErrInvalidExchange
+	}
+
+	getUser, err := as.findOrCreateGoogleUser(userInfo, oauthState)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = checkUserBlacklisted(as.BlacklistRepo, getUser.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return as.startLoginSession(getUser)
+}
+
+// findOrCreateGoogleUser responsible to resolving google account into user by subject, then by verified email, otherwise registering it
+func (as *AuthService) findOrCreateGoogleUser(userInfo *model.OAuthUserInfo, oauthState *model.OAuthState) (*model.AuthUserDetails, error) {
+	getUser, err := as.AuthRepo.GetUserByProviderSubject(userInfo.Subject)
+	if err == nil {
+		return getUser, nil
+	}
+
+	if err != pgx.ErrNoRows {
+		return nil, err
+	}
+
+	getUser, err = as.AuthRepo.GetUserByEmail(userInfo.Email)
+	if err == nil {
+		// linking by unverified email would let anyone take over the account
+		if !userInfo.EmailVerified {
+			return nil, model.ErrInvalidExchange
+		}
+
+		err = as.AuthRepo.LinkProviderSubject(getUser.UserID, userInfo.Subject)
+		if err != nil {
+			return nil, err
+		}
+
+		return getUser, nil
+	}
+
+	if err != pgx.ErrNoRows {
+		return nil, err
+	}
+
+	if oauthState.LocationID == nil || oauthState.Telepon == "" {
+		return nil, model.ErrOAuthRegistrationRequired
+	}
+
+	roleName := model.RoleCustomer
+	if oauthState.IsSeller {
+		roleName = model.RoleSeller
+	}
+
+	role, err := as.RoleRepo.GetRoleByName(roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := as.AuthRepo.CreateUser(model.CreateUserRequest{
+		FullName:        userInfo.Name,
+		Email:           userInfo.Email,
+		Telepon:         oauthState.Telepon,
+		LocationID:      oauthState.LocationID.String(),
+		IsSeller:        oauthState.IsSeller,
+		RoleID:          role.ID,
+		LoginProvider:   model.LoginProviderGoogle,
+		ProviderSubject: userInfo.Subject,
+		EmailVerified:   userInfo.EmailVerified,
+	}, oauthState.IsSeller)
+	if err != nil {
+		return nil, err
+	}
+
+	return as.AuthRepo.GetUserByID(userID)
+}
+
+// sendEmailVerification responsible to storing a new verification token of user and mailing it to given email
+func sendEmailVerification(cfg *config.Configuration, authRepo repository.IAuthRepository, mailer util.Mailer, userID uuid.UUID, fullName string, email string) error {
+	token := helper.GenerateRandomToken(64)
+
+	err := authRepo.CreateEmailVerification(userID, helper.HashToken(token), time.Now().Add(emailVerificationExpire))
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Halo %s,\r\n\r\nTerima kasih sudah mendaftar di GrowBaks.\r\n"+
+		"Buka link berikut untuk memverifikasi email kamu (berlaku %d jam):\r\n%s/verify-email?token=%s\r\n\r\n"+
+		"Token: %s\r\n",
+		fullName, int(emailVerificationExpire.Hours()), cfg.Const.FrontendURL, token, token)
+
+	return mailer.Send(email, "Verifikasi Email GrowBaks", body)
+}
+
+// startLoginSession responsible to issuing mfa challenge when user enabled TOTP, otherwise issuing a new session
+func (as *AuthService) startLoginSession(user *model.AuthUserDetails) (*model.SuccessLoginResponse, *model.MFAChallengeResponse, error) {
+	if user.TOTPEnabledAt == nil {
+		session, err := as.createSession(user)
+		return session, nil, err
+	}
+
+	mfaToken := helper.GenerateRandomToken(64)
+	expiredAt := time.Now().Add(mfaChallengeExpire)
+
+	err := as.MFARepo.CreateMFAChallenge(user.UserID, helper.HashToken(mfaToken), expiredAt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return nil, &model.MFAChallengeResponse{
+		MFAToken:  mfaToken,
+		ExpiredAt: expiredAt,
+	}, nil
+}
+
+// createSession responsible to storing a new refresh token and building login response
+func (as *AuthService) createSession(user *model.AuthUserDetails) (*model.SuccessLoginResponse, error) {
+	refreshToken := helper.GenerateRandomToken(64)
+	refreshExpiredAt := time.Now().Add(util.RefreshTokenExpire)
+
+	err := as.AuthRepo.CreateRefreshToken(user.UserID, helper.HashToken(refreshToken), refreshExpiredAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return as.buildLoginResponse(user, refreshToken, refreshExpiredAt)
+}
+
+// buildLoginResponse responsible to signing access token and wrapping it with refresh token data
+func (as *AuthService) buildLoginResponse(user *model.AuthUserDetails, refreshToken string, refreshExpiredAt time.Time) (*model.SuccessLoginResponse, error) {
+	jwt, err := util.BuildJWT(as.Config, user)
 	if err != nil {
 		as.Logger.Error(fmt.Errorf("AuthService.BuildJWT ERROR : %v MSG : %s", err, err.Error()))
 		return nil, err
 	}
 
 	return &model.SuccessLoginResponse{
-		AccessToken: jwt,
-		ExpiredAt:   time.Now().Add(util.JWTExpire),
-		Role:        getUser.RoleName,
+		AccessToken:           jwt,
+		ExpiredAt:             time.Now().Add(util.JWTExpire),
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiredAt: refreshExpiredAt,
+		Role:                  user.RoleName,
 	}, nil
 }
 
@@ -154,3 +660,25 @@ func validateLoginRequest(user *model.LoginRequest) error {
 
 	return nil
 }
+
+// checkEmailVerified responsible to returning ErrEmailNotVerified when verification is required and user email is not verified yet
+func checkEmailVerified(cfg *config.Configuration, userRepo repository.IUserRepository, userID uuid.UUID) error {
+	if !cfg.Const.RequireEmailVerification {
+		return nil
+	}
+
+	user, err := userRepo.GetByID(userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return model.ErrUserNotFound
+		}
+
+		return err
+	}
+
+	if user.EmailVerifiedAt == nil {
+		return model.ErrEmailNotVerified
+	}
+
+	return nil
+}
@@ -1 +1,137 @@
 package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/model"
+	"github.com/wiormiw/GrowBaks/repository"
+)
+
+type (
+	// IBlacklistService is an interface that has all the function to be implemented inside blacklist service
+	IBlacklistService interface {
+		ValidateAccessSvc(tokenID string, userID string) error
+		BlacklistUserSvc(adminID uuid.UUID, userID uuid.UUID, req model.BlacklistUserRequest) error
+		UnblacklistUserSvc(userID uuid.UUID) error
+		GetAllBlacklistSvc() ([]model.Blacklist, error)
+	}
+
+	// BlacklistService is an app blacklist struct that consists of all the dependencies needed for blacklist service
+	BlacklistService struct {
+		Context       context.Context
+		Config        *config.Configuration
+		Logger        *logrus.Logger
+		BlacklistRepo repository.IBlacklistRepository
+		UserRepo      repository.IUserRepository
+	}
+)
+
+// ValidateAccessSvc service layer for checking a jwt token id is not revoked and its owner is not blacklisted
+func (bs *BlacklistService) ValidateAccessSvc(tokenID string, userID string) error {
+	tID, err := uuid.Parse(tokenID)
+	if err != nil {
+		return model.ErrInvalidToken
+	}
+
+	uID, err := uuid.Parse(userID)
+	if err != nil {
+		return model.ErrInvalidToken
+	}
+
+	revoked, err := bs.BlacklistRepo.IsTokenRevoked(tID)
+	if err != nil {
+		return err
+	}
+
+	if revoked {
+		return model.ErrTokenRevoked
+	}
+
+	return checkUserBlacklisted(bs.BlacklistRepo, uID)
+}
+
+// BlacklistUserSvc service layer for banning a user
+func (bs *BlacklistService) BlacklistUserSvc(adminID uuid.UUID, userID uuid.UUID, req model.BlacklistUserRequest) error {
+	if adminID == userID {
+		return model.ErrForbiddenBlacklistSelf
+	}
+
+	err := validateBlacklistUserRequest(&req)
+	if err != nil {
+		return err
+	}
+
+	_, err = bs.UserRepo.GetByID(userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return model.ErrUserNotFound
+		}
+
+		return err
+	}
+
+	err = bs.BlacklistRepo.BlacklistUser(userID, req.Reason, req.ExpiredAt, adminID)
+	if err != nil {
+		bs.Logger.Error(fmt.Errorf("BlacklistService.BlacklistUserSvc ERROR : %v MSG : %s", err, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// UnblacklistUserSvc service layer for lifting ban of a user
+func (bs *BlacklistService) UnblacklistUserSvc(userID uuid.UUID) error {
+	_, err := bs.BlacklistRepo.GetActiveBlacklistByUser(userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return model.ErrBlacklistNotFound
+		}
+
+		return err
+	}
+
+	return bs.BlacklistRepo.UnblacklistUser(userID)
+}
+
+// GetAllBlacklistSvc service layer for getting all active ban
+func (bs *BlacklistService) GetAllBlacklistSvc() ([]model.Blacklist, error) {
+	data, err := bs.BlacklistRepo.GetAllBlacklist()
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// checkUserBlacklisted responsible to returning ErrUserBlacklisted when user has an active ban
+func checkUserBlacklisted(blacklistRepo repository.IBlacklistRepository, userID uuid.UUID) error {
+	blacklist, err := blacklistRepo.GetActiveBlacklistByUser(userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+
+		return err
+	}
+
+	return fmt.Errorf("%w, reason : %s", model.ErrUserBlacklisted, blacklist.Reason)
+}
+
+// validateBlacklistUserRequest responsible to validating blacklist user request
+func validateBlacklistUserRequest(req *model.BlacklistUserRequest) error {
+	if len(req.Reason) < 5 || len(req.Reason) > 50 {
+		return model.ErrInvalidRequest
+	}
+
+	if req.ExpiredAt != nil && req.ExpiredAt.Before(time.Now()) {
+		return model.ErrInvalidRequest
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/model"
+	"github.com/wiormiw/GrowBaks/repository"
+)
+
+type (
+	// ICartService is an interface that has all the function to be implemented inside cart service
+	ICartService interface {
+		GetCartSvc(userID uuid.UUID) ([]model.CartItem, error)
+		AddCartItemSvc(userID uuid.UUID, req model.AddCartItemRequest) error
+		UpdateCartItemSvc(userID uuid.UUID, productID uuid.UUID, req model.UpdateCartItemRequest) error
+		DeleteCartItemSvc(userID uuid.UUID, productID uuid.UUID) error
+		CheckoutSvc(userID uuid.UUID) (*model.CheckoutResponse, error)
+	}
+
+	// CartService is an app cart struct that consists of all the dependencies needed for cart service
+	CartService struct {
+		Context       context.Context
+		Config        *config.Configuration
+		Logger        *logrus.Logger
+		CartRepo      repository.ICartRepository
+		ProductRepo   repository.IProductRepository
+		PemesananRepo repository.IPemesananRepository
+		UserRepo      repository.IUserRepository
+	}
+)
+
+// GetCartSvc service layer for getting every item inside cart of a user
+func (cs *CartService) GetCartSvc(userID uuid.UUID) ([]model.CartItem, error) {
+	data, err := cs.CartRepo.GetCartByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// AddCartItemSvc service layer for adding a product into cart of a user
+func (cs *CartService) AddCartItemSvc(userID uuid.UUID, req model.AddCartItemRequest) error {
+	if req.ProductID == uuid.Nil || req.QTY < 1 {
+		return model.ErrInvalidRequest
+	}
+
+	product, err := cs.ProductRepo.GetProductByID(req.ProductID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return model.ErrProductNotFound
+		}
+
+		return err
+	}
+
+	qty := req.QTY
+
+	item, err := cs.CartRepo.GetCartItem(userID, req.ProductID)
+	if err != nil && err != pgx.ErrNoRows {
+		return err
+	}
+
+	if err == nil {
+		qty += item.QTY
+	}
+
+	// early feedback only, stock is reserved for real on checkout
+	if product.Price <= 0 {
+		return model.ErrProductNotPriced
+	}
+
+	if qty > product.Stock {
+		return model.ErrInsufficientStock
+	}
+
+	return cs.CartRepo.AddCartItem(userID, req.ProductID, req.QTY)
+}
+
+// UpdateCartItemSvc service layer for replacing qty of a product inside cart of a user
+func (cs *CartService) UpdateCartItemSvc(userID uuid.UUID, productID uuid.UUID, req model.UpdateCartItemRequest) error {
+	if req.QTY < 1 {
+		return model.ErrInvalidRequest
+	}
+
+	item, err := cs.CartRepo.GetCartItem(userID, productID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return model.ErrCartItemNotFound
+		}
+
+		return err
+	}
+
+	if req.QTY > item.Stock {
+		return model.ErrInsufficientStock
+	}
+
+	return cs.CartRepo.UpdateCartItem(userID, productID, req.QTY)
+}
+
+// DeleteCartItemSvc service layer for removing a product from cart of a user
+func (cs *CartService) DeleteCartItemSvc(userID uuid.UUID, productID uuid.UUID) error {
+	return cs.CartRepo.DeleteCartItem(userID, productID)
+}
+
+// CheckoutSvc service layer for turning cart of a user into pemesanan, one per lapak
+func (cs *CartService) CheckoutSvc(userID uuid.UUID) (*model.CheckoutResponse, error) {
+	err := checkEmailVerified(cs.Config, cs.UserRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pemesananIDs, err := cs.PemesananRepo.CheckoutCart(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CheckoutResponse{PemesananIDs: pemesananIDs}, nil
+}
@@ -2,6 +2,10 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4"
@@ -9,53 +13,74 @@ import (
 	"github.com/wiormiw/GrowBaks/config"
 	"github.com/wiormiw/GrowBaks/model"
 	"github.com/wiormiw/GrowBaks/repository"
+	"github.com/wiormiw/GrowBaks/util"
+)
+
+const (
+	// maxNearbyRadiusKM is upper bound of nearby search radius
+	maxNearbyRadiusKM = 50
+	// maxLapakDescriptionLength is upper bound of lapak description length
+	maxLapakDescriptionLength = 1000
+	// maxLapakAddressLength is upper bound of lapak address & landmark length
+	maxLapakAddressLength = 255
 )
 
 type (
 	ILapakService interface {
-		GetAllLapakSvc(search string) ([]model.Lapak, error)
-		GetAllLapakByLocationSvc(id uuid.UUID, search string) ([]model.Lapak, error)
+		GetAllLapakSvc(search string, includeDeleted bool, page model.PageRequest) ([]model.Lapak, int, error)
+		GetAllLapakByLocationSvc(id uuid.UUID, search string, includeDeleted bool, page model.PageRequest) ([]model.Lapak, int, error)
 		GetLapakByIDSvc(id uuid.UUID) (*model.Lapak, error)
-		UpdateLapakSvc(id uuid.UUID, req model.LapakUpdate) error
-		UpdateLapakStatusSvc(id uuid.UUID, req model.LapakUpdateStatus) error
-		DeleteLapakSvc(id uuid.UUID) error
+		GetLapakDetailSvc(id uuid.UUID) (*model.LapakDetail, error)
+		GetLapakByUserSvc(userID uuid.UUID) ([]model.Lapak, error)
+		CreateLapakSvc(principal model.Principal, req model.LapakCreateRequest) (*model.Lapak, error)
+		UpdateLapakSvc(principal model.Principal, id uuid.UUID, req model.LapakUpdate) error
+		UpdateLapakStatusSvc(principal model.Principal, id uuid.UUID, req model.LapakUpdateStatus) error
+		GetNearbyLapakSvc(near model.GeoRadius, page model.PageRequest) ([]model.Lapak, int, error)
+		DeleteLapakSvc(principal model.Principal, id uuid.UUID) error
+		RestoreLapakSvc(id uuid.UUID) error
+		RelocateLapakSvc(principal model.Principal, id uuid.UUID, req model.LapakRelocateRequest) error
+		GetLapakLocationHistorySvc(principal model.Principal, id uuid.UUID) ([]model.LapakLocationHistory, error)
 	}
 
 	// LapakService is an app tag struct that consists of all the dependencies needed for lapak service
 	LapakService struct {
-		Context   context.Context
-		Config    *config.Configuration
-		Logger    *logrus.Logger
-		LapakRepo repository.ILapakRepository
-		UserRepo  repository.IUserRepository
+		Context       context.Context
+		Config        *config.Configuration
+		Logger        *logrus.Logger
+		LapakRepo     repository.ILapakRepository
+		UserRepo      repository.IUserRepository
+		BlacklistRepo repository.IBlacklistRepository
+		LocationRepo  repository.ILocationRepository
+		RoleRepo      repository.IRoleRepository
+		Mailer        util.Mailer
 	}
 )
 
 // GetAllLapakSvc service layer for getting all lapak
-func (laps *LapakService) GetAllLapakSvc(search string) ([]model.Lapak, error) {
-	data, err := laps.LapakRepo.GetAllLapak(search)
+func (laps *LapakService) GetAllLapakSvc(search string, includeDeleted bool, page model.PageRequest) ([]model.Lapak, int, error) {
+	data, total, err := laps.LapakRepo.GetAllLapak(search, includeDeleted, page)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return data, nil
+	return data, total, nil
 }
 
 // GetAllLapakSvc service layer for getting all lapak
-func (laps *LapakService) GetAllLapakByLocationSvc(id uuid.UUID, search string) ([]model.Lapak, error) {
+func (laps *LapakService) GetAllLapakByLocationSvc(id uuid.UUID, search string, includeDeleted bool, page model.PageRequest) ([]model.Lapak, int, error) {
 	userProfile, err := laps.UserRepo.GetProfileByID(id)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	userDaerah := userProfile.Daerah
 
-	data, err := laps.LapakRepo.GetAllLapakByLocation(search, userDaerah)
+	data, total, err := laps.LapakRepo.GetAllLapakByLocation(search, userDaerah, includeDeleted, page)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return data, nil
+	return data, total, nil
 }
 
 // GetAllLapakByIDSvc service layer for getting all lapak By Id
@@ -68,9 +93,78 @@ func (laps *LapakService) GetLapakByIDSvc(id uuid.UUID) (*model.Lapak, error) {
 	return data, nil
 }
 
+// GetLapakDetailSvc service layer for getting a lapak profile along with its product counts
+func (laps *LapakService) GetLapakDetailSvc(id uuid.UUID) (*model.LapakDetail, error) {
+	lapak, err := laps.LapakRepo.GetLapakByID(id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, model.ErrLapakNotFound
+		}
+
+		return nil, err
+	}
+
+	total, inStock, err := laps.LapakRepo.GetProductCounts(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.LapakDetail{Lapak: *lapak, ProductCount: total, InStockCount: inStock}, nil
+}
+
+// GetLapakByUserSvc service layer for getting every lapak owned by a user
+func (laps *LapakService) GetLapakByUserSvc(userID uuid.UUID) ([]model.Lapak, error) {
+	return laps.LapakRepo.GetLapakByUser(userID)
+}
+
+// CreateLapakSvc service layer for creating another lapak owned by the caller, limited by max_lapak of the caller role
+func (laps *LapakService) CreateLapakSvc(principal model.Principal, req model.LapakCreateRequest) (*model.Lapak, error) {
+	err := validateCreateLapakRequest(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	locationID, err := resolveLocationID(laps.LocationRepo, req.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if locationID == nil {
+		return nil, model.ErrInvalidRequest
+	}
+
+	if req.Status == "open" {
+		err = checkUserBlacklisted(laps.BlacklistRepo, principal.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		err = checkEmailVerified(laps.Config, laps.UserRepo, principal.UserID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	role, err := laps.RoleRepo.GetRoleByName(principal.RoleName)
+	if err != nil {
+		return nil, err
+	}
+
+	lapakID, err := laps.LapakRepo.CreateLapak(principal.UserID, req, *locationID, role.MaxLapak)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, model.ErrLapakLimitReached
+		}
+
+		return nil, err
+	}
+
+	return laps.GetLapakByIDSvc(lapakID)
+}
+
 // UpdateTagSvc service layer for updating a tag by id
-func (laps *LapakService) UpdateLapakSvc(id uuid.UUID, req model.LapakUpdate) error {
-	_, err := laps.LapakRepo.GetLapakByID(id)
+func (laps *LapakService) UpdateLapakSvc(principal model.Principal, id uuid.UUID, req model.LapakUpdate) error {
+	lapak, err := laps.LapakRepo.GetLapakByID(id)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -80,12 +174,29 @@ func (laps *LapakService) UpdateLapakSvc(id uuid.UUID, req model.LapakUpdate) er
 		return err
 	}
 
-	err = validateUpdateLapakRequest(&req)
+	err = authorizeOwner(principal, lapak.UserID, model.PermissionLapakManageAny)
+	if err != nil {
+		return err
+	}
+
+	fields, err := validateUpdateLapakRequest(&req)
 	if err != nil {
 		return err
 	}
 
-	err = laps.LapakRepo.UpdateByID(id, req.Name, req.Status)
+	if req.Status != nil && *req.Status == "open" {
+		err = checkUserBlacklisted(laps.BlacklistRepo, lapak.UserID)
+		if err != nil {
+			return err
+		}
+
+		err = checkEmailVerified(laps.Config, laps.UserRepo, lapak.UserID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = laps.LapakRepo.UpdateByID(id, fields)
 	if err != nil {
 		return err
 	}
@@ -93,8 +204,8 @@ func (laps *LapakService) UpdateLapakSvc(id uuid.UUID, req model.LapakUpdate) er
 	return nil
 }
 
-func (laps *LapakService) UpdateLapakStatusSvc(id uuid.UUID, req model.LapakUpdateStatus) error {
-	_, err := laps.LapakRepo.GetLapakByID(id)
+func (laps *LapakService) UpdateLapakStatusSvc(principal model.Principal, id uuid.UUID, req model.LapakUpdateStatus) error {
+	lapak, err := laps.LapakRepo.GetLapakByID(id)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -104,11 +215,29 @@ func (laps *LapakService) UpdateLapakStatusSvc(id uuid.UUID, req model.LapakUpda
 		return err
 	}
 
+	err = authorizeOwner(principal, lapak.UserID, model.PermissionLapakManageAny)
+	if err != nil {
+		return err
+	}
+
 	err = validateUpdateLapakStatusRequest(&req)
 	if err != nil {
 		return err
 	}
 
+	// lapak of blacklisted seller stays closed until the ban is lifted
+	if req.Status == "open" {
+		err = checkUserBlacklisted(laps.BlacklistRepo, lapak.UserID)
+		if err != nil {
+			return err
+		}
+
+		err = checkEmailVerified(laps.Config, laps.UserRepo, lapak.UserID)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = laps.LapakRepo.UpdateStatusByID(id, req.Status)
 	if err != nil {
 		return err
@@ -118,8 +247,8 @@ func (laps *LapakService) UpdateLapakStatusSvc(id uuid.UUID, req model.LapakUpda
 }
 
 // DeleteLapakSvc service layer for deleting a lapak by id
-func (laps *LapakService) DeleteLapakSvc(id uuid.UUID) error {
-	_, err := laps.LapakRepo.GetLapakByID(id)
+func (laps *LapakService) DeleteLapakSvc(principal model.Principal, id uuid.UUID) error {
+	lapak, err := laps.LapakRepo.GetLapakByID(id)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return model.ErrLapakNotFound
@@ -128,6 +257,11 @@ func (laps *LapakService) DeleteLapakSvc(id uuid.UUID) error {
 		return err
 	}
 
+	err = authorizeOwner(principal, lapak.UserID, model.PermissionLapakManageAny)
+	if err != nil {
+		return err
+	}
+
 	err = laps.LapakRepo.DeleteByID(id)
 	if err != nil {
 		return err
@@ -136,27 +270,271 @@ func (laps *LapakService) DeleteLapakSvc(id uuid.UUID) error {
 	return nil
 }
 
-// validateUpdate
-func validateUpdateLapakRequest(lapak *model.LapakUpdate) error {
-	if lapak.Name == "" || lapak.Status == "" {
+// GetNearbyLapakSvc service layer for getting lapak inside radius of a point, nearest first
+func (laps *LapakService) GetNearbyLapakSvc(near model.GeoRadius, page model.PageRequest) ([]model.Lapak, int, error) {
+	err := validateGeoRadius(near)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return laps.LapakRepo.GetNearbyLapak(near, page)
+}
+
+// RestoreLapakSvc service layer for restoring a soft deleted lapak by id
+func (laps *LapakService) RestoreLapakSvc(id uuid.UUID) error {
+	return laps.LapakRepo.RestoreByID(id)
+}
+
+// RelocateLapakSvc service layer for moving a lapak into other location, buyers having open pemesanan are notified by email
+func (laps *LapakService) RelocateLapakSvc(principal model.Principal, id uuid.UUID, req model.LapakRelocateRequest) error {
+	lapak, err := laps.LapakRepo.GetLapakByID(id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return model.ErrLapakNotFound
+		}
+
+		return err
+	}
+
+	err = authorizeOwner(principal, lapak.UserID, model.PermissionLapakManageAny)
+	if err != nil {
+		return err
+	}
+
+	err = validateCoordinate(req.Latitude, req.Longitude)
+	if err != nil {
+		return err
+	}
+
+	locationID, err := resolveLocationID(laps.LocationRepo, req.LocationID)
+	if err != nil {
+		return err
+	}
+
+	if locationID == nil || *locationID == lapak.LocationID {
 		return model.ErrInvalidRequest
 	}
 
-	if lapak.Name != "" {
-		if len(lapak.Name) < 5 {
-			return model.ErrInvalidRequest
+	err = laps.LapakRepo.RelocateByID(id, *locationID, req.Latitude, req.Longitude, principal.UserID, principal.RoleName)
+	if err != nil {
+		return err
+	}
+
+	buyers, err := laps.LapakRepo.GetOpenOrderBuyers(id)
+	if err != nil {
+		// lapak is already moved, failing to notify must not fail the request
+		laps.Logger.Error(fmt.Errorf("LapakService.RelocateLapakSvc GetOpenOrderBuyers ERROR : %v MSG : %s", err, err.Error()))
+		return nil
+	}
+
+	location, err := laps.LocationRepo.GetLocationByID(*locationID)
+	if err != nil {
+		laps.Logger.Error(fmt.Errorf("LapakService.RelocateLapakSvc GetLocationByID ERROR : %v MSG : %s", err, err.Error()))
+		return nil
+	}
+
+	// mail is sent in background, buyers are already fetched so the shared db connection is not touched
+	go laps.notifyLapakRelocated(buyers, lapak.LapakName, lapak.Daerah, *location)
+
+	return nil
+}
+
+// GetLapakLocationHistorySvc service layer for getting every relocation of a lapak
+func (laps *LapakService) GetLapakLocationHistorySvc(principal model.Principal, id uuid.UUID) ([]model.LapakLocationHistory, error) {
+	lapak, err := laps.LapakRepo.GetLapakByID(id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, model.ErrLapakNotFound
 		}
+
+		return nil, err
 	}
 
-	if lapak.Status != "" {
-		if lapak.Status != "open" && lapak.Status != "closed" {
-			return model.ErrInvalidRequest
+	err = authorizeOwner(principal, lapak.UserID, model.PermissionLapakManageAny)
+	if err != nil {
+		return nil, err
+	}
+
+	return laps.LapakRepo.GetLocationHistory(id)
+}
+
+// notifyLapakRelocated responsible to emailing new location of a lapak into its buyers
+func (laps *LapakService) notifyLapakRelocated(buyers []model.LapakBuyer, lapakName string, fromDaerah string, to model.Location) {
+	for _, buyer := range buyers {
+		body := fmt.Sprintf("Halo %s,\r\n\r\nLapak %s tempat kamu memiliki pesanan aktif telah pindah lokasi.\r\n"+
+			"Lokasi lama: %s\r\nLokasi baru: %s, %s, %s\r\n\r\n"+
+			"Silakan cek kembali pesanan kamu di %s/pemesanan.\r\n",
+			buyer.FullName, lapakName, fromDaerah, to.Daerah, to.Kota, to.Provinsi, laps.Config.Const.FrontendURL)
+
+		err := laps.Mailer.Send(buyer.Email, "Lapak Pindah Lokasi GrowBaks", body)
+		if err != nil {
+			laps.Logger.Error(fmt.Errorf("LapakService.notifyLapakRelocated Mailer.Send ERROR : %v MSG : %s", err, err.Error()))
 		}
 	}
+}
+
+// validateCreateLapakRequest responsible to trimming & validating create lapak request, status is closed when not given
+func validateCreateLapakRequest(lapak *model.LapakCreateRequest) error {
+	lapak.Name = strings.TrimSpace(lapak.Name)
+	lapak.Description = strings.TrimSpace(lapak.Description)
+
+	if len(lapak.Name) < 5 || len(lapak.Name) > 100 || len(lapak.Description) > maxLapakDescriptionLength {
+		return model.ErrInvalidRequest
+	}
+
+	if lapak.Status == "" {
+		lapak.Status = "closed"
+	}
+
+	if lapak.Status != "open" && lapak.Status != "closed" {
+		return model.ErrInvalidRequest
+	}
+
+	return validateCoordinate(lapak.Latitude, lapak.Longitude)
+}
+
+// validateUpdateLapakRequest responsible to validating given fields of update lapak request, returning them as column map
+func validateUpdateLapakRequest(lapak *model.LapakUpdate) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+
+	if lapak.Name != nil {
+		name := strings.TrimSpace(*lapak.Name)
+		if len(name) < 5 || len(name) > 100 {
+			return nil, model.ErrInvalidRequest
+		}
+
+		fields["name"] = name
+	}
+
+	if lapak.Status != nil {
+		if *lapak.Status != "open" && *lapak.Status != "closed" {
+			return nil, model.ErrInvalidRequest
+		}
+
+		fields["status"] = *lapak.Status
+	}
+
+	if lapak.Description != nil {
+		description := strings.TrimSpace(*lapak.Description)
+		if len(description) > maxLapakDescriptionLength {
+			return nil, model.ErrInvalidRequest
+		}
+
+		fields["description"] = nullableString(description)
+	}
+
+	for column, value := range map[string]*string{"banner_url": lapak.BannerURL, "logo_url": lapak.LogoURL} {
+		if value == nil {
+			continue
+		}
+
+		imageURL := strings.TrimSpace(*value)
+		if imageURL != "" && !isHTTPURL(imageURL) {
+			return nil, model.ErrInvalidRequest
+		}
+
+		fields[column] = nullableString(imageURL)
+	}
+
+	if lapak.WhatsApp != nil {
+		whatsapp := strings.TrimSpace(*lapak.WhatsApp)
+		if whatsapp != "" && !model.IsAllowedTeleponInput.MatchString(whatsapp) {
+			return nil, model.ErrInvalidRequest
+		}
+
+		fields["whatsapp"] = nullableString(whatsapp)
+	}
+
+	for column, value := range map[string]*string{"address": lapak.Address, "landmark": lapak.Landmark} {
+		if value == nil {
+			continue
+		}
+
+		text := strings.TrimSpace(*value)
+		if len(text) > maxLapakAddressLength {
+			return nil, model.ErrInvalidRequest
+		}
+
+		fields[column] = nullableString(text)
+	}
+
+	err := validateCoordinate(lapak.Latitude, lapak.Longitude)
+	if err != nil {
+		return nil, err
+	}
+
+	if lapak.Latitude != nil {
+		fields["latitude"] = *lapak.Latitude
+		fields["longitude"] = *lapak.Longitude
+	}
+
+	if len(fields) == 0 {
+		return nil, model.ErrInvalidRequest
+	}
+
+	return fields, nil
+}
+
+// nullableString responsible to storing empty string as NULL
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+// isHTTPURL responsible to checking value is an absolute http or https url
+func isHTTPURL(value string) bool {
+	parsed, err := url.ParseRequestURI(value)
+	if err != nil {
+		return false
+	}
+
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// validateCoordinate responsible to validating optional coordinate, latitude & longitude must be given together
+func validateCoordinate(latitude *float64, longitude *float64) error {
+	if latitude == nil && longitude == nil {
+		return nil
+	}
+
+	if latitude == nil || longitude == nil {
+		return model.ErrInvalidRequest
+	}
+
+	// NaN fails every range comparison, so it is rejected before the bound check
+	if !isFiniteFloat(*latitude) || !isFiniteFloat(*longitude) {
+		return model.ErrInvalidRequest
+	}
+
+	if *latitude < -90 || *latitude > 90 || *longitude < -180 || *longitude > 180 {
+		return model.ErrInvalidRequest
+	}
 
 	return nil
 }
 
+// validateGeoRadius responsible to validating nearby search circle
+func validateGeoRadius(near model.GeoRadius) error {
+	err := validateCoordinate(&near.Latitude, &near.Longitude)
+	if err != nil {
+		return err
+	}
+
+	if !isFiniteFloat(near.RadiusKM) || near.RadiusKM <= 0 || near.RadiusKM > maxNearbyRadiusKM {
+		return model.ErrInvalidRequest
+	}
+
+	return nil
+}
+
+// isFiniteFloat responsible to rejecting NaN & Inf which strconv.ParseFloat accepts from query string
+func isFiniteFloat(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
+
 func validateUpdateLapakStatusRequest(lapak *model.LapakUpdateStatus) error {
 	if lapak.Status == "" {
 		return model.ErrInvalidRequest
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/wiormiw/GrowBaks/model"
+)
+
+func floatPtr(value float64) *float64 {
+	return &value
+}
+
+func TestValidateCoordinate(t *testing.T) {
+	cases := []struct {
+		name      string
+		latitude  *float64
+		longitude *float64
+		valid     bool
+	}{
+		{"both empty", nil, nil, true},
+		{"inside range", floatPtr(-6.2), floatPtr(106.8), true},
+		{"on the bound", floatPtr(90), floatPtr(-180), true},
+		{"latitude only", floatPtr(-6.2), nil, false},
+		{"longitude only", nil, floatPtr(106.8), false},
+		{"latitude out of range", floatPtr(90.1), floatPtr(106.8), false},
+		{"longitude out of range", floatPtr(-6.2), floatPtr(180.1), false},
+		{"latitude NaN", floatPtr(math.NaN()), floatPtr(106.8), false},
+		{"longitude NaN", floatPtr(-6.2), floatPtr(math.NaN()), false},
+		{"latitude Inf", floatPtr(math.Inf(1)), floatPtr(106.8), false},
+		{"longitude -Inf", floatPtr(-6.2), floatPtr(math.Inf(-1)), false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateCoordinate(tc.latitude, tc.longitude)
+			if tc.valid && err != nil {
+				t.Fatalf("want valid, got %v", err)
+			}
+
+			if !tc.valid && !errors.Is(err, model.ErrInvalidRequest) {
+				t.Fatalf("want ErrInvalidRequest, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateGeoRadius(t *testing.T) {
+	cases := []struct {
+		name  string
+		near  model.GeoRadius
+		valid bool
+	}{
+		{"default radius", model.GeoRadius{Latitude: -6.2, Longitude: 106.8, RadiusKM: 5}, true},
+		{"max radius", model.GeoRadius{Latitude: -6.2, Longitude: 106.8, RadiusKM: maxNearbyRadiusKM}, true},
+		{"zero radius", model.GeoRadius{Latitude: -6.2, Longitude: 106.8, RadiusKM: 0}, false},
+		{"radius too large", model.GeoRadius{Latitude: -6.2, Longitude: 106.8, RadiusKM: maxNearbyRadiusKM + 1}, false},
+		{"radius NaN", model.GeoRadius{Latitude: -6.2, Longitude: 106.8, RadiusKM: math.NaN()}, false},
+		{"radius Inf", model.GeoRadius{Latitude: -6.2, Longitude: 106.8, RadiusKM: math.Inf(1)}, false},
+		{"center NaN", model.GeoRadius{Latitude: math.NaN(), Longitude: 106.8, RadiusKM: 5}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateGeoRadius(tc.near)
+			if tc.valid && err != nil {
+				t.Fatalf("want valid, got %v", err)
+			}
+
+			if !tc.valid && !errors.Is(err, model.ErrInvalidRequest) {
+				t.Fatalf("want ErrInvalidRequest, got %v", err)
+			}
+		})
+	}
+}
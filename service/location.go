@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/model"
+	"github.com/wiormiw/GrowBaks/repository"
+)
+
+type (
+	// ILocationService is an interface that has all the function to be implemented inside location service
+	ILocationService interface {
+		GetAllLocationSvc(filter model.LocationFilter, page model.PageRequest) ([]model.Location, int, error)
+		GetAllProvinsiSvc() ([]string, error)
+		GetAllKotaSvc(provinsi string) ([]string, error)
+		GetLocationByIDSvc(id uuid.UUID) (*model.Location, error)
+		CreateLocationSvc(req model.LocationRequest) (*model.Location, error)
+		UpdateLocationSvc(id uuid.UUID, req model.LocationRequest) (*model.Location, error)
+		DeleteLocationSvc(id uuid.UUID) error
+		ImportLocationSvc(file io.Reader) (*model.LocationImportResult, error)
+	}
+
+	// LocationService is an app location struct that consists of all the dependencies needed for location service
+	LocationService struct {
+		Context      context.Context
+		Config       *config.Configuration
+		Logger       *logrus.Logger
+		LocationRepo repository.ILocationRepository
+	}
+)
+
+const (
+	// maxLocationNameLength is upper bound of provinsi, kota & daerah length
+	maxLocationNameLength = 100
+	// maxLocationImportRows is upper bound of row inside a single import file
+	maxLocationImportRows = 5000
+)
+
+// GetAllLocationSvc service layer for getting all location filtered by hierarchy & search
+func (los *LocationService) GetAllLocationSvc(filter model.LocationFilter, page model.PageRequest) ([]model.Location, int, error) {
+	data, total, err := los.LocationRepo.GetAllLocation(filter, page)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return data, total, nil
+}
+
+// GetAllProvinsiSvc service layer for getting every provinsi, the top level of location hierarchy
+func (los *LocationService) GetAllProvinsiSvc() ([]string, error) {
+	return los.LocationRepo.GetAllProvinsi()
+}
+
+// GetAllKotaSvc service layer for getting every kota inside a provinsi
+func (los *LocationService) GetAllKotaSvc(provinsi string) ([]string, error) {
+	provinsi = strings.TrimSpace(provinsi)
+	if provinsi == "" {
+		return nil, model.ErrInvalidRequest
+	}
+
+	return los.LocationRepo.GetAllKota(provinsi)
+}
+
+// GetLocationByIDSvc service layer for getting a location by id
+func (los *LocationService) GetLocationByIDSvc(id uuid.UUID) (*model.Location, error) {
+	data, err := los.LocationRepo.GetLocationByID(id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, model.ErrLocationNotFound
+		}
+
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// CreateLocationSvc service layer for creating a location
+func (los *LocationService) CreateLocationSvc(req model.LocationRequest) (*model.Location, error) {
+	req, err := validateLocationRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	locationID, err := los.LocationRepo.CreateLocation(req)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, model.ErrLocationExisted
+		}
+
+		return nil, err
+	}
+
+	return &model.Location{ID: locationID, Provinsi: req.Provinsi, Kota: req.Kota, Daerah: req.Daerah}, nil
+}
+
+// UpdateLocationSvc service layer for renaming a location, users & lapak keep pointing into the same location
+func (los *LocationService) UpdateLocationSvc(id uuid.UUID, req model.LocationRequest) (*model.Location, error) {
+	req, err := validateLocationRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := los.GetLocationByIDSvc(id); err != nil {
+		return nil, err
+	}
+
+	err = los.LocationRepo.UpdateLocation(id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Location{ID: id, Provinsi: req.Provinsi, Kota: req.Kota, Daerah: req.Daerah}, nil
+}
+
+// DeleteLocationSvc service layer for deleting a location which is not used by users or lapak
+func (los *LocationService) DeleteLocationSvc(id uuid.UUID) error {
+	if _, err := los.GetLocationByIDSvc(id); err != nil {
+		return err
+	}
+
+	return los.LocationRepo.DeleteLocation(id)
+}
+
+// ImportLocationSvc service layer for creating many location from csv file with provinsi, kota & daerah header,
+// the whole file is rejected when any row is invalid so the import can be fixed & retried safely
+func (los *LocationService) ImportLocationSvc(file io.Reader) (*model.LocationImportResult, error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w, csv header is missing", model.ErrInvalidRequest)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		// excel prepends byte order mark into the first column
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range []string{"provinsi", "kota", "daerah"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w, csv header must contain provinsi, kota & daerah", model.ErrInvalidRequest)
+		}
+	}
+
+	var rows []model.LocationRequest
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("%w, csv line %d %s", model.ErrInvalidRequest, parseErr.Line, parseErr.Err.Error())
+			}
+
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		row, err := validateLocationRequest(model.LocationRequest{
+			Provinsi: record[columns["provinsi"]],
+			Kota:     record[columns["kota"]],
+			Daerah:   record[columns["daerah"]],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w, csv line %d must have provinsi, kota & daerah of 1-%d characters", model.ErrInvalidRequest, line, maxLocationNameLength)
+		}
+
+		rows = append(rows, row)
+		if len(rows) > maxLocationImportRows {
+			return nil, fmt.Errorf("%w, csv must not contain more than %d rows", model.ErrInvalidRequest, maxLocationImportRows)
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w, csv has no location row", model.ErrInvalidRequest)
+	}
+
+	created, err := los.LocationRepo.ImportLocation(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.LocationImportResult{Total: len(rows), Created: created, Skipped: len(rows) - created}, nil
+}
+
+// validateLocationRequest responsible to trimming & validating provinsi, kota & daerah of location request
+func validateLocationRequest(req model.LocationRequest) (model.LocationRequest, error) {
+	req.Provinsi = strings.TrimSpace(req.Provinsi)
+	req.Kota = strings.TrimSpace(req.Kota)
+	req.Daerah = strings.TrimSpace(req.Daerah)
+
+	for _, value := range []string{req.Provinsi, req.Kota, req.Daerah} {
+		if value == "" || len(value) > maxLocationNameLength {
+			return req, model.ErrInvalidRequest
+		}
+	}
+
+	return req, nil
+}
+
+// resolveLocationID responsible to parsing location id input & making sure the location exists, empty input means not given
+func resolveLocationID(locationRepo repository.ILocationRepository, input string) (*uuid.UUID, error) {
+	if input == "" {
+		return nil, nil
+	}
+
+	locationID, err := uuid.Parse(input)
+	if err != nil {
+		return nil, model.ErrInvalidRequest
+	}
+
+	_, err = locationRepo.GetLocationByID(locationID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, model.ErrInvalidRequest
+		}
+
+		return nil, err
+	}
+
+	return &locationID, nil
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/helper"
+	"github.com/wiormiw/GrowBaks/model"
+	"github.com/wiormiw/GrowBaks/repository"
+	"github.com/wiormiw/GrowBaks/util"
+)
+
+type (
+	// IMFAService is an interface that has all the function to be implemented inside mfa service
+	IMFAService interface {
+		EnrollSvc(userID uuid.UUID) (*model.MFAEnrollResponse, error)
+		VerifySvc(userID uuid.UUID, req model.MFACodeRequest) (*model.MFARecoveryCodesResponse, error)
+		DisableSvc(userID uuid.UUID, req model.MFACodeRequest) error
+		RegenerateRecoveryCodesSvc(userID uuid.UUID, req model.MFACodeRequest) (*model.MFARecoveryCodesResponse, error)
+	}
+
+	// MFAService is an app mfa struct that consists of all the dependencies needed for mfa service
+	MFAService struct {
+		Context context.Context
+		Config  *config.Configuration
+		Logger  *logrus.Logger
+		MFARepo repository.IMFARepository
+	}
+)
+
+const (
+	totpIssuer         string = "GrowBaks"
+	recoveryCodeCount  int    = 10
+	recoveryCodeLength int    = 10
+)
+
+// EnrollSvc service layer for generating pending TOTP secret of a user
+func (ms *MFAService) EnrollSvc(userID uuid.UUID) (*model.MFAEnrollResponse, error) {
+	userMFA, err := ms.getUserMFA(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userMFA.TOTPEnabledAt != nil {
+		return nil, model.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := util.GenerateTOTPSecret()
+	if err != nil {
+		ms.Logger.Error(fmt.Errorf("MFAService.EnrollSvc GenerateTOTPSecret ERROR : %v MSG : %s", err, err.Error()))
+		return nil, err
+	}
+
+	err = ms.MFARepo.SetTOTPSecret(userID, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.MFAEnrollResponse{
+		Secret:          secret,
+		ProvisioningURI: util.TOTPProvisioningURI(totpIssuer, userMFA.Email, secret),
+	}, nil
+}
+
+// VerifySvc service layer for confirming pending TOTP secret with first code, then enabling it
+func (ms *MFAService) VerifySvc(userID uuid.UUID, req model.MFACodeRequest) (*model.MFARecoveryCodesResponse, error) {
+	userMFA, err := ms.getUserMFA(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userMFA.TOTPEnabledAt != nil {
+		return nil, model.ErrMFAAlreadyEnabled
+	}
+
+	if userMFA.TOTPSecret == nil {
+		return nil, model.ErrMFANotEnrolled
+	}
+
+	step, valid := util.ValidateTOTP(*userMFA.TOTPSecret, req.Code, time.Now())
+	if !valid {
+		return nil, model.ErrInvalidMFACode
+	}
+
+	codes, codeHashes := generateRecoveryCodes()
+
+	err = ms.MFARepo.EnableTOTP(userID, step, codeHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableSvc service layer for disabling TOTP of a user after confirming a valid code
+func (ms *MFAService) DisableSvc(userID uuid.UUID, req model.MFACodeRequest) error {
+	userMFA, err := ms.getUserMFA(userID)
+	if err != nil {
+		return err
+	}
+
+	if userMFA.TOTPEnabledAt == nil {
+		return model.ErrMFANotEnabled
+	}
+
+	if userMFA.RequireMFA {
+		return model.ErrForbiddenDisableMFA
+	}
+
+	valid, err := verifyMFACode(ms.MFARepo, userMFA, req.Code)
+	if err != nil {
+		return err
+	}
+
+	if !valid {
+		return model.ErrInvalidMFACode
+	}
+
+	return ms.MFARepo.DisableTOTP(userID)
+}
+
+// RegenerateRecoveryCodesSvc service layer for replacing recovery codes of a user after confirming a valid code
+func (ms *MFAService) RegenerateRecoveryCodesSvc(userID uuid.UUID, req model.MFACodeRequest) (*model.MFARecoveryCodesResponse, error) {
+	userMFA, err := ms.getUserMFA(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userMFA.TOTPEnabledAt == nil {
+		return nil, model.ErrMFANotEnabled
+	}
+
+	valid, err := verifyMFACode(ms.MFARepo, userMFA, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !valid {
+		return nil, model.ErrInvalidMFACode
+	}
+
+	codes, codeHashes := generateRecoveryCodes()
+
+	err = ms.MFARepo.ReplaceRecoveryCodes(userID, codeHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// getUserMFA responsible to getting two-factor state of a user, mapping missing user into ErrUserNotFound
+func (ms *MFAService) getUserMFA(userID uuid.UUID) (*model.UserMFA, error) {
+	userMFA, err := ms.MFARepo.GetUserMFA(userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, model.ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	return userMFA, nil
+}
+
+// verifyMFACode responsible to checking a TOTP code or consuming a recovery code of a user with enabled TOTP
+func verifyMFACode(mfaRepo repository.IMFARepository, userMFA *model.UserMFA, code string) (bool, error) {
+	if code == "" || userMFA.TOTPSecret == nil {
+		return false, nil
+	}
+
+	if step, valid := util.ValidateTOTP(*userMFA.TOTPSecret, code, time.Now()); valid {
+		// a code is only accepted once, replaying it inside its 30 second window is rejected
+		return mfaRepo.UseTOTPStep(userMFA.UserID, step)
+	}
+
+	return mfaRepo.UseRecoveryCode(userMFA.UserID, helper.HashToken(code))
+}
+
+// generateRecoveryCodes responsible to generating plain recovery codes along with their hash to be stored
+func generateRecoveryCodes() ([]string, []string) {
+	codes := make([]string, 0, recoveryCodeCount)
+	codeHashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		code := helper.GenerateRandomToken(recoveryCodeLength)
+		codes = append(codes, code)
+		codeHashes = append(codeHashes, helper.HashToken(code))
+	}
+
+	return codes, codeHashes
+}
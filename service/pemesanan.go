@@ -15,11 +15,13 @@ import (
 type (
 	IPemesananService interface {
 		CreatePemesananSvc(id uuid.UUID, product_id uuid.UUID, req model.CreatePemesananRequest) error
-		GetAllPemesananSvc() ([]model.PemesananResponse, error)
+		GetAllPemesananSvc(page model.PageRequest) ([]model.PemesananResponse, int, error)
 		GetAllPemesananPribadiSvc(id uuid.UUID) ([]model.PemesananResponse, error)
-		GetPemesananByIDSvc(id uuid.UUID) (*model.PemesananResponse, error)
-		UpdatePemesananStatusSvc(id uuid.UUID, req model.UpdatePemesananRequest) error
-		DeletePemesananSvc(id uuid.UUID) error
+		GetAllPemesananByLapakSvc(principal model.Principal, lapakID uuid.UUID, filter model.PemesananLapakFilter) ([]model.PemesananResponse, error)
+		GetPemesananByIDSvc(principal model.Principal, id uuid.UUID) (*model.PemesananResponse, error)
+		UpdatePemesananStatusSvc(principal model.Principal, id uuid.UUID, req model.UpdatePemesananRequest) error
+		UpdatePemesananLapakStatusSvc(principal model.Principal, lapakID uuid.UUID, id uuid.UUID, req model.UpdatePemesananRequest) error
+		DeletePemesananSvc(principal model.Principal, id uuid.UUID) error
 	}
 
 	// PemesananService is an app tag struct that consists of all the dependencies needed for pemesanan service
@@ -30,44 +32,53 @@ type (
 		PemesananRepo repository.IPemesananRepository
 		UserRepo      repository.IUserRepository
 		ProductRepo   repository.IProductRepository
+		LapakRepo     repository.ILapakRepository
 	}
 )
 
 // Create Product Service
 func (pems *PemesananService) CreatePemesananSvc(id uuid.UUID, product_id uuid.UUID, req model.CreatePemesananRequest) error {
+	err := checkEmailVerified(pems.Config, pems.UserRepo, id)
+	if err != nil {
+		return err
+	}
 
 	product, err := pems.ProductRepo.GetProductByID(product_id)
 	if err != nil {
+		if err == pgx.ErrNoRows {
+			return model.ErrProductNotFound
+		}
+
 		pems.Logger.Error(fmt.Errorf("ERROR : %v MSG : %s", err, err.Error()))
 		return err
 	}
 
-	if product.Stock >= req.QTY {
-		err = validateCreatePemesananRequest(&req)
-		if err != nil {
-			pems.Logger.Error(fmt.Errorf("ERROR : %v MSG : %s", err, err.Error()))
-			return err
-		}
+	// every pemesanan starts as reserve, the rest follows pemesananTransitions
+	req.Status = model.PemesananStatusReserve
 
-		err = pems.PemesananRepo.CreatePemesanan(id, product_id, product.Stock, req, product)
-		if err != nil {
-			pems.Logger.Error(fmt.Errorf("ERROR : %v MSG : %s", err, err.Error()))
-			return err
-		}
-	} else {
-		return model.ErrInvalidRequest
+	err = validateCreatePemesananRequest(&req)
+	if err != nil {
+		pems.Logger.Error(fmt.Errorf("ERROR : %v MSG : %s", err, err.Error()))
+		return err
+	}
+
+	// stock is checked & decremented atomically inside repository, product.Stock may already be stale here
+	err = pems.PemesananRepo.CreatePemesanan(id, product_id, req, product)
+	if err != nil {
+		pems.Logger.Error(fmt.Errorf("ERROR : %v MSG : %s", err, err.Error()))
+		return err
 	}
 
 	return nil
 }
 
-func (pems *PemesananService) GetAllPemesananSvc() ([]model.PemesananResponse, error) {
-	data, err := pems.PemesananRepo.GetAllPemesanan()
+func (pems *PemesananService) GetAllPemesananSvc(page model.PageRequest) ([]model.PemesananResponse, int, error) {
+	data, total, err := pems.PemesananRepo.GetAllPemesanan(page)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return data, nil
+	return data, total, nil
 }
 
 func (pems *PemesananService) GetAllPemesananPribadiSvc(id uuid.UUID) ([]model.PemesananResponse, error) {
@@ -79,8 +90,50 @@ func (pems *PemesananService) GetAllPemesananPribadiSvc(id uuid.UUID) ([]model.P
 	return data, nil
 }
 
-func (pems *PemesananService) GetPemesananByIDSvc(id uuid.UUID) (*model.PemesananResponse, error) {
+// GetAllPemesananByLapakSvc service layer for getting all pemesanan placed on a lapak, only for its seller
+func (pems *PemesananService) GetAllPemesananByLapakSvc(principal model.Principal, lapakID uuid.UUID, filter model.PemesananLapakFilter) ([]model.PemesananResponse, error) {
+	err := pems.authorizeLapak(principal, lapakID)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Status != "" && !model.IsValidPemesananStatus(filter.Status) {
+		return nil, model.ErrInvalidRequest
+	}
+
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, model.ErrInvalidRequest
+	}
+
+	data, err := pems.PemesananRepo.GetAllPemesananByLapak(lapakID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (pems *PemesananService) GetPemesananByIDSvc(principal model.Principal, id uuid.UUID) (*model.PemesananResponse, error) {
 	data, err := pems.PemesananRepo.GetPemesananByID(id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, model.ErrPemesananNotFound
+		}
+
+		return nil, err
+	}
+
+	err = pems.authorizePemesanan(principal, data)
+	if err != nil {
+		return nil, err
+	}
+
+	data.Items, err = pems.PemesananRepo.GetPemesananItems(id)
+	if err != nil {
+		return nil, err
+	}
+
+	data.History, err = pems.PemesananRepo.GetPemesananStatusHistory(id)
 	if err != nil {
 		return nil, err
 	}
@@ -88,32 +141,56 @@ func (pems *PemesananService) GetPemesananByIDSvc(id uuid.UUID) (*model.Pemesana
 	return data, nil
 }
 
-func (pems *PemesananService) UpdatePemesananStatusSvc(id uuid.UUID, req model.UpdatePemesananRequest) error {
-	_, err := pems.PemesananRepo.GetPemesananByID(id)
+func (pems *PemesananService) UpdatePemesananStatusSvc(principal model.Principal, id uuid.UUID, req model.UpdatePemesananRequest) error {
+	pemesanan, err := pems.PemesananRepo.GetPemesananByID(id)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return model.ErrLapakNotFound
+			return model.ErrPemesananNotFound
 		}
 
 		return err
 	}
 
-	err = validateUpdatePemesananStatus(&req)
+	actors, err := pems.resolvePemesananActors(principal, pemesanan)
 	if err != nil {
 		return err
 	}
 
-	err = pems.PemesananRepo.UpdatePemesanan(id, req.Status)
+	return pems.transitionPemesanan(principal, pemesanan, actors, req)
+}
+
+// UpdatePemesananLapakStatusSvc service layer for seller processing a pemesanan placed on their lapak
+func (pems *PemesananService) UpdatePemesananLapakStatusSvc(principal model.Principal, lapakID uuid.UUID, id uuid.UUID, req model.UpdatePemesananRequest) error {
+	err := pems.authorizeLapak(principal, lapakID)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	pemesanan, err := pems.PemesananRepo.GetPemesananByID(id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return model.ErrPemesananNotFound
+		}
+
+		return err
+	}
+
+	// pemesanan of another lapak is hidden from this inbox
+	if pemesanan.LapakID != lapakID.String() {
+		return model.ErrPemesananNotFound
+	}
+
+	actors, err := pems.resolvePemesananActors(principal, pemesanan)
+	if err != nil {
+		return err
+	}
+
+	return pems.transitionPemesanan(principal, pemesanan, actors, req)
 }
 
-// Delete Pemesanan
-func (pems *PemesananService) DeletePemesananSvc(id uuid.UUID) error {
+// DeletePemesananSvc service layer for deleting a cancelled pemesanan, admin may delete pemesanan of any status
+func (pems *PemesananService) DeletePemesananSvc(principal model.Principal, id uuid.UUID) error {
 	pemesanan, err := pems.PemesananRepo.GetPemesananByID(id)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -123,15 +200,17 @@ func (pems *PemesananService) DeletePemesananSvc(id uuid.UUID) error {
 		return err
 	}
 
-	productID, err := uuid.Parse(pemesanan.ProductID)
+	err = pems.authorizePemesanan(principal, pemesanan)
 	if err != nil {
-		pems.Logger.Error(fmt.Sprintf("Error 9: %s", err))
 		return err
 	}
 
-	product, err := pems.ProductRepo.GetProductByID(productID)
+	// buyer & seller go through the cancel transition first, only admin removes pemesanan of any status
+	if pemesanan.Status != model.PemesananStatusCancel && !hasPermission(principal, model.PermissionOrderManageAny) {
+		return model.ErrPemesananNotCancelled
+	}
 
-	err = pems.PemesananRepo.DeleteByID(pemesanan, product)
+	err = pems.PemesananRepo.DeleteByID(pemesanan)
 	if err != nil {
 		return err
 	}
@@ -139,6 +218,104 @@ func (pems *PemesananService) DeletePemesananSvc(id uuid.UUID) error {
 	return nil
 }
 
+// authorizePemesanan responsible to allowing only buyer of the pemesanan, seller owning the lapak it was placed on or caller granted order:manage:any
+func (pems *PemesananService) authorizePemesanan(principal model.Principal, pemesanan *model.PemesananResponse) error {
+	_, err := pems.resolvePemesananActors(principal, pemesanan)
+	return err
+}
+
+// resolvePemesananActors responsible to getting every relation of caller into a pemesanan, returning ErrForbiddenAccess when there is none
+func (pems *PemesananService) resolvePemesananActors(principal model.Principal, pemesanan *model.PemesananResponse) ([]string, error) {
+	var actors []string
+
+	if pemesanan.UserID == principal.UserID.String() {
+		actors = append(actors, model.PemesananActorBuyer)
+	}
+
+	if hasPermission(principal, model.PermissionOrderManageAny) {
+		actors = append(actors, model.PemesananActorAdmin)
+	}
+
+	lapakID, err := uuid.Parse(pemesanan.LapakID)
+	if err == nil {
+		lapak, err := pems.LapakRepo.GetLapakByID(lapakID)
+		if err != nil && err != pgx.ErrNoRows {
+			return nil, err
+		}
+
+		if err == nil && lapak.UserID == principal.UserID {
+			actors = append(actors, model.PemesananActorSeller)
+		}
+	}
+
+	if len(actors) == 0 {
+		return nil, model.ErrForbiddenAccess
+	}
+
+	return actors, nil
+}
+
+// transitionPemesanan responsible to moving a pemesanan into requested status when one of caller actors is allowed to
+func (pems *PemesananService) transitionPemesanan(principal model.Principal, pemesanan *model.PemesananResponse, actors []string, req model.UpdatePemesananRequest) error {
+	err := validateUpdatePemesananStatus(&req)
+	if err != nil {
+		return err
+	}
+
+	for _, actor := range actors {
+		if isAllowedPemesananTransition(pemesanan.Status, req.Status, actor) {
+			return pems.PemesananRepo.UpdatePemesananStatus(pemesanan, req.Status, principal.UserID, actor)
+		}
+	}
+
+	return model.ErrInvalidStatusTransition
+}
+
+// authorizeLapak responsible to allowing only seller owning the lapak or caller granted order:manage:any
+func (pems *PemesananService) authorizeLapak(principal model.Principal, lapakID uuid.UUID) error {
+	lapak, err := pems.LapakRepo.GetLapakByID(lapakID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return model.ErrLapakNotFound
+		}
+
+		return err
+	}
+
+	return authorizeOwner(principal, lapak.UserID, model.PermissionOrderManageAny)
+}
+
+// pemesananTransitions maps current status into next status along with actors allowed to trigger it, admin may trigger any of them
+var pemesananTransitions = map[string]map[string][]string{
+	model.PemesananStatusReserve: {
+		model.PemesananStatusPending: {model.PemesananActorSeller},
+	},
+	model.PemesananStatusPending: {
+		model.PemesananStatusDone:   {model.PemesananActorSeller},
+		model.PemesananStatusCancel: {model.PemesananActorBuyer},
+	},
+}
+
+// isAllowedPemesananTransition responsible to checking a status transition against pemesananTransitions
+func isAllowedPemesananTransition(from string, to string, actor string) bool {
+	allowedActors, ok := pemesananTransitions[from][to]
+	if !ok {
+		return false
+	}
+
+	if actor == model.PemesananActorAdmin {
+		return true
+	}
+
+	for _, allowedActor := range allowedActors {
+		if allowedActor == actor {
+			return true
+		}
+	}
+
+	return false
+}
+
 // validateCreateProductRequest responsible to validating create product request
 func validateCreatePemesananRequest(req *model.CreatePemesananRequest) error {
 	if req.Status == "" || req.QTY < 1 {
@@ -149,7 +326,7 @@ func validateCreatePemesananRequest(req *model.CreatePemesananRequest) error {
 }
 
 func validateUpdatePemesananStatus(req *model.UpdatePemesananRequest) error {
-	if req.Status == "" {
+	if !model.IsValidPemesananStatus(req.Status) {
 		return model.ErrInvalidRequest
 	}
 
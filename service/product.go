@@ -2,9 +2,13 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/sirupsen/logrus"
 	"github.com/wiormiw/GrowBaks/config"
 	"github.com/wiormiw/GrowBaks/model"
@@ -13,31 +17,57 @@ import (
 
 type (
 	IProductService interface {
-		CreateProductSvc(id uuid.UUID, req model.CreateProductRequest) error
-		GetAllProductSvc(id uuid.UUID, search string) ([]model.GetAllProductRequest, error)
-		GetAllProductByLapakSvc(lapak_id uuid.UUID) ([]model.GetAllProductRequest, error)
+		CreateProductSvc(principal model.Principal, id uuid.UUID, req model.CreateProductRequest) error
+		GetAllProductSvc(id uuid.UUID, filter model.ProductFilter, page model.PageRequest) ([]model.GetAllProductRequest, int, error)
+		GetProductFeedSvc(id uuid.UUID, filter model.ProductFilter, size int, cursor string) ([]model.GetAllProductRequest, int, string, error)
+		GetProductFacetsSvc(id uuid.UUID, filter model.ProductFilter) (map[string][]model.FacetCount, error)
+		SuggestProductSvc(query string) ([]string, error)
+		GetAllProductByLapakSvc(lapak_id uuid.UUID, includeDeleted bool) ([]model.GetAllProductRequest, error)
 		GetProductByIDSvc(id uuid.UUID) (*model.GetAllProductRequest, error)
-		// GetLapakByIDSvc(id uuid.UUID) (*model.Lapak, error)
-		// UpdateLapakSvc(id uuid.UUID, req model.LapakUpdate) error
-		// UpdateLapakStatusSvc(id uuid.UUID, req model.LapakUpdateStatus) error
-		// DeleteLapakSvc(id uuid.UUID) error
+		UpdateProductSvc(principal model.Principal, id uuid.UUID, req model.UpdateProductRequest) error
+		PatchProductSvc(principal model.Principal, id uuid.UUID, req model.PatchProductRequest) error
+		DeleteProductSvc(principal model.Principal, id uuid.UUID) error
+		RestoreProductSvc(id uuid.UUID) error
 	}
 
 	// ProductService is an app tag struct that consists of all the dependencies needed for lapak service
 	ProductService struct {
-		Context     context.Context
-		Config      *config.Configuration
-		Logger      *logrus.Logger
-		ProductRepo repository.IProductRepository
-		UserRepo    repository.IUserRepository
+		Context      context.Context
+		Config       *config.Configuration
+		Logger       *logrus.Logger
+		ProductRepo  repository.IProductRepository
+		UserRepo     repository.IUserRepository
+		LapakRepo    repository.ILapakRepository
+		ScheduleRepo repository.IScheduleRepository
 	}
 )
 
+const (
+	// maxProductPrice is upper bound of product price in rupiah, guarding against typo
+	maxProductPrice int64 = 100000000
+	// productSuggestionLimit is number of autocompletion returned by suggest
+	productSuggestionLimit = 10
+)
+
 // Create Product Service
-func (ps *ProductService) CreateProductSvc(id uuid.UUID, req model.CreateProductRequest) error {
+func (ps *ProductService) CreateProductSvc(principal model.Principal, id uuid.UUID, req model.CreateProductRequest) error {
+	lapak, err := ps.LapakRepo.GetLapakByID(id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return model.ErrLapakNotFound
+		}
+
+		return err
+	}
+
+	err = authorizeOwner(principal, lapak.UserID, model.PermissionProductManageAny)
+	if err != nil {
+		return err
+	}
+
 	req.LapakID = id
 
-	err := validateCreateProductRequest(&req)
+	err = validateCreateProductRequest(&req)
 	if err != nil {
 		ps.Logger.Error(fmt.Errorf("ERROR : %v MSG : %s", err, err.Error()))
 		return err
@@ -53,28 +83,112 @@ func (ps *ProductService) CreateProductSvc(id uuid.UUID, req model.CreateProduct
 }
 
 // GetAllProductSvc service layer for getting all lapak
-func (ps *ProductService) GetAllProductSvc(id uuid.UUID, search string) ([]model.GetAllProductRequest, error) {
+func (ps *ProductService) GetAllProductSvc(id uuid.UUID, filter model.ProductFilter, page model.PageRequest) ([]model.GetAllProductRequest, int, error) {
+	err := ps.resolveProductFilter(id, &filter)
+	if err != nil {
+		return nil, 0, err
+	}
 
-	userProfile, err := ps.UserRepo.GetProfileByID(id)
+	data, total, err := ps.ProductRepo.GetAllProduct(filter, page)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	userDaerah := userProfile.Daerah
+	fillProductOpensAt(ps.Logger, ps.ScheduleRepo, data)
 
-	ps.Logger.Info(fmt.Sprintf("Value Test : %#+v UProfile", userProfile))
+	return data, total, nil
+}
+
+// GetProductFeedSvc service layer for getting newest product page by page using opaque cursor, returning cursor of next page
+func (ps *ProductService) GetProductFeedSvc(id uuid.UUID, filter model.ProductFilter, size int, cursor string) ([]model.GetAllProductRequest, int, string, error) {
+	var after *model.ProductCursor
+
+	if cursor != "" {
+		decoded, err := decodeProductCursor(cursor)
+		if err != nil {
+			return nil, 0, "", err
+		}
+
+		after = decoded
+	}
 
-	data, err := ps.ProductRepo.GetAllProduct(search, userDaerah)
+	err := ps.resolveProductFilter(id, &filter)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	// fetching one more row to know whether next page exists
+	data, total, err := ps.ProductRepo.GetProductFeed(filter, size+1, after)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	if len(data) <= size {
+		fillProductOpensAt(ps.Logger, ps.ScheduleRepo, data)
+		return data, total, "", nil
+	}
+
+	data = data[:size]
+	fillProductOpensAt(ps.Logger, ps.ScheduleRepo, data)
+	last := data[size-1]
+	if last.CreatedAt == nil {
+		return data, total, "", nil
+	}
+
+	return data, total, encodeProductCursor(model.ProductCursor{CreatedAt: *last.CreatedAt, ID: last.ID}), nil
+}
+
+// GetProductFacetsSvc service layer for counting filtered product per category & daerah
+func (ps *ProductService) GetProductFacetsSvc(id uuid.UUID, filter model.ProductFilter) (map[string][]model.FacetCount, error) {
+	err := ps.resolveProductFilter(id, &filter)
 	if err != nil {
 		return nil, err
 	}
 
-	return data, nil
+	return ps.ProductRepo.GetProductFacets(filter)
+}
+
+// resolveProductFilter responsible to validating product filter, falling back to caller daerah when no location is given
+func (ps *ProductService) resolveProductFilter(id uuid.UUID, filter *model.ProductFilter) error {
+	for _, category := range filter.Categories {
+		if category != "makanan" && category != "minuman" {
+			return model.ErrInvalidRequest
+		}
+	}
+
+	if (filter.PriceMin != nil && *filter.PriceMin < 0) || (filter.PriceMax != nil && *filter.PriceMax < 0) {
+		return model.ErrInvalidRequest
+	}
+
+	if filter.PriceMin != nil && filter.PriceMax != nil && *filter.PriceMin > *filter.PriceMax {
+		return model.ErrInvalidRequest
+	}
+
+	if filter.Near != nil {
+		err := validateGeoRadius(*filter.Near)
+		if err != nil {
+			return err
+		}
+	}
+
+	// distance filter replaces daerah, buyer near a border still sees lapak next door
+	if filter.LapakID != nil || filter.LocationID != nil || filter.Daerah != "" || filter.Near != nil {
+		return nil
+	}
+
+	userProfile, err := ps.UserRepo.GetProfileByID(id)
+	if err != nil {
+		return err
+	}
+
+	filter.Daerah = userProfile.Daerah
+
+	return nil
 }
 
-func (ps *ProductService) GetAllProductByLapakSvc(lapak_id uuid.UUID) ([]model.GetAllProductRequest, error) {
+func (ps *ProductService) GetAllProductByLapakSvc(lapak_id uuid.UUID, includeDeleted bool) ([]model.GetAllProductRequest, error) {
 
-	data, err := ps.ProductRepo.GetAllProductByLapak(lapak_id)
+	data, err := ps.ProductRepo.GetAllProductByLapak(lapak_id, includeDeleted)
 	if err != nil {
 		return nil, err
 	}
@@ -82,16 +196,103 @@ func (ps *ProductService) GetAllProductByLapakSvc(lapak_id uuid.UUID) ([]model.G
 	return data, nil
 }
 
+// SuggestProductSvc service layer for getting product name autocompletion, query shorter than 2 characters is rejected
+func (ps *ProductService) SuggestProductSvc(query string) ([]string, error) {
+	if len([]rune(query)) < 2 {
+		return nil, model.ErrInvalidRequest
+	}
+
+	return ps.ProductRepo.SuggestProduct(query, productSuggestionLimit)
+}
+
 // GetAllLapakByIDSvc service layer for getting all lapak By Id
 func (ps *ProductService) GetProductByIDSvc(id uuid.UUID) (*model.GetAllProductRequest, error) {
 	data, err := ps.ProductRepo.GetProductByID(id)
 	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, model.ErrProductNotFound
+		}
+
 		return nil, err
 	}
 
 	return data, nil
 }
 
+// UpdateProductSvc service layer for replacing every field of a product by id
+func (ps *ProductService) UpdateProductSvc(principal model.Principal, id uuid.UUID, req model.UpdateProductRequest) error {
+	err := ps.authorizeProduct(principal, id)
+	if err != nil {
+		return err
+	}
+
+	err = validateUpdateProductRequest(&req)
+	if err != nil {
+		return err
+	}
+
+	return ps.ProductRepo.UpdateByID(id, map[string]interface{}{
+		"name":             req.ProductName,
+		"stok":             req.Stock,
+		"price":            req.Price,
+		"product_kategori": req.ProductCategory,
+		"product_img":      req.ProductImg,
+	})
+}
+
+// PatchProductSvc service layer for updating only given fields of a product by id
+func (ps *ProductService) PatchProductSvc(principal model.Principal, id uuid.UUID, req model.PatchProductRequest) error {
+	err := ps.authorizeProduct(principal, id)
+	if err != nil {
+		return err
+	}
+
+	fields, err := validatePatchProductRequest(&req)
+	if err != nil {
+		return err
+	}
+
+	return ps.ProductRepo.UpdateByID(id, fields)
+}
+
+// DeleteProductSvc service layer for soft deleting a product by id
+func (ps *ProductService) DeleteProductSvc(principal model.Principal, id uuid.UUID) error {
+	err := ps.authorizeProduct(principal, id)
+	if err != nil {
+		return err
+	}
+
+	return ps.ProductRepo.DeleteByID(id)
+}
+
+// RestoreProductSvc service layer for restoring a soft deleted product by id
+func (ps *ProductService) RestoreProductSvc(id uuid.UUID) error {
+	return ps.ProductRepo.RestoreByID(id)
+}
+
+// authorizeProduct responsible to allowing only seller owning the product lapak or caller granted product:manage:any
+func (ps *ProductService) authorizeProduct(principal model.Principal, id uuid.UUID) error {
+	product, err := ps.ProductRepo.GetProductByID(id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return model.ErrProductNotFound
+		}
+
+		return err
+	}
+
+	lapak, err := ps.LapakRepo.GetLapakByID(product.LapakID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return authorizeOwner(principal, uuid.Nil, model.PermissionProductManageAny)
+		}
+
+		return err
+	}
+
+	return authorizeOwner(principal, lapak.UserID, model.PermissionProductManageAny)
+}
+
 // validateCreateProductRequest responsible to validating create product request
 func validateCreateProductRequest(req *model.CreateProductRequest) error {
 	if req.ProductName == "" || req.ProductCategory == "" || req.ProductImg == "" || req.Stock < 1 {
@@ -106,5 +307,116 @@ func validateCreateProductRequest(req *model.CreateProductRequest) error {
 		return model.ErrInvalidRequest
 	}
 
+	// price is whole rupiah
+	if req.Price < 1 || req.Price > maxProductPrice {
+		return model.ErrInvalidRequest
+	}
+
+	return nil
+}
+
+// validateUpdateProductRequest responsible to validating update product request, sold-out product may be kept at zero stock
+func validateUpdateProductRequest(req *model.UpdateProductRequest) error {
+	if req.ProductName == "" || req.ProductCategory == "" || req.ProductImg == "" || req.Stock < 0 {
+		return model.ErrInvalidRequest
+	}
+
+	if len(req.ProductName) < 5 {
+		return model.ErrInvalidRequest
+	}
+
+	if req.ProductCategory != "makanan" && req.ProductCategory != "minuman" {
+		return model.ErrInvalidRequest
+	}
+
+	if req.Price < 1 || req.Price > maxProductPrice {
+		return model.ErrInvalidRequest
+	}
+
 	return nil
 }
+
+// validatePatchProductRequest responsible to validating given fields of patch product request, returning them as column map
+func validatePatchProductRequest(req *model.PatchProductRequest) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+
+	if req.ProductName != nil {
+		if len(*req.ProductName) < 5 {
+			return nil, model.ErrInvalidRequest
+		}
+
+		fields["name"] = *req.ProductName
+	}
+
+	if req.Stock != nil {
+		if *req.Stock < 0 {
+			return nil, model.ErrInvalidRequest
+		}
+
+		fields["stok"] = *req.Stock
+	}
+
+	if req.Price != nil {
+		if *req.Price < 1 || *req.Price > maxProductPrice {
+			return nil, model.ErrInvalidRequest
+		}
+
+		fields["price"] = *req.Price
+	}
+
+	if req.ProductCategory != nil {
+		if *req.ProductCategory != "makanan" && *req.ProductCategory != "minuman" {
+			return nil, model.ErrInvalidRequest
+		}
+
+		fields["product_kategori"] = *req.ProductCategory
+	}
+
+	if req.ProductImg != nil {
+		if *req.ProductImg == "" {
+			return nil, model.ErrInvalidRequest
+		}
+
+		fields["product_img"] = *req.ProductImg
+	}
+
+	if len(fields) == 0 {
+		return nil, model.ErrInvalidRequest
+	}
+
+	return fields, nil
+}
+
+// encodeProductCursor responsible to encoding product cursor as opaque url safe string
+func encodeProductCursor(cursor model.ProductCursor) string {
+	raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + cursor.ID.String()
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeProductCursor responsible to decoding product cursor given by previous feed page
+func decodeProductCursor(value string) (*model.ProductCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, model.ErrInvalidRequest
+	}
+
+	createdAt, id, found := strings.Cut(string(raw), "|")
+	if !found {
+		return nil, model.ErrInvalidRequest
+	}
+
+	cursor := &model.ProductCursor{}
+
+	cursor.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, model.ErrInvalidRequest
+	}
+
+	cursor.ID, err = uuid.Parse(id)
+	if err != nil {
+		return nil, model.ErrInvalidRequest
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/model"
+	"github.com/wiormiw/GrowBaks/repository"
+)
+
+type (
+	// IPurgeService is an interface that has all the function to be implemented inside purge service
+	IPurgeService interface {
+		PurgeSvc() (model.PurgeResult, error)
+		PurgeInterval() time.Duration
+	}
+
+	// PurgeService is an app purge struct that consists of all the dependencies needed for purge service
+	PurgeService struct {
+		Context   context.Context
+		Config    *config.Configuration
+		Logger    *logrus.Logger
+		PurgeRepo repository.IPurgeRepository
+	}
+)
+
+const (
+	// defaultSoftDeleteRetentionDays is used when SOFT_DELETE_RETENTION_DAYS is not set
+	defaultSoftDeleteRetentionDays = 30
+	// defaultPurgeIntervalMinutes is used when PURGE_INTERVAL_MINUTES is not set
+	defaultPurgeIntervalMinutes = 60
+)
+
+// PurgeSvc service layer for permanently deleting record soft deleted longer than retention period
+func (pus *PurgeService) PurgeSvc() (model.PurgeResult, error) {
+	retention := pus.Config.Const.SoftDeleteRetentionDays
+	if retention <= 0 {
+		retention = defaultSoftDeleteRetentionDays
+	}
+
+	before := time.Now().AddDate(0, 0, -retention)
+
+	result, err := pus.PurgeRepo.PurgeDeletedBefore(before)
+	if err != nil {
+		return model.PurgeResult{}, err
+	}
+
+	pus.Logger.Info(fmt.Sprintf("Purged record deleted before %s : %d products, %d lapak, %d users", before.Format(time.RFC3339), result.Products, result.Lapak, result.Users))
+
+	return result, nil
+}
+
+// PurgeInterval responsible to returning how often purge job is run
+func (pus *PurgeService) PurgeInterval() time.Duration {
+	interval := pus.Config.Const.PurgeIntervalMinutes
+	if interval <= 0 {
+		interval = defaultPurgeIntervalMinutes
+	}
+
+	return time.Duration(interval) * time.Minute
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/model"
+	"github.com/wiormiw/GrowBaks/repository"
+)
+
+type (
+	// IRoleService is an interface that has all the function to be implemented inside role service
+	IRoleService interface {
+		GetAllRoleSvc() ([]model.Role, error)
+		CreateRoleSvc(req model.CreateRoleRequest) (*model.Role, error)
+		DeleteRoleSvc(adminRoleName string, id uuid.UUID) error
+		UpdateRoleMFASvc(id uuid.UUID, req model.UpdateRoleMFARequest) error
+		UpdateRoleLapakLimitSvc(id uuid.UUID, req model.UpdateRoleLapakLimitRequest) error
+		UpdateRolePermissionsSvc(adminRoleName string, id uuid.UUID, req model.UpdateRolePermissionsRequest) (*model.Role, error)
+		AssignUserRoleSvc(adminID uuid.UUID, userID uuid.UUID, req model.AssignUserRoleRequest) (*model.Role, error)
+		GetAllPermissionSvc() ([]model.Permission, error)
+		CreatePermissionSvc(req model.CreatePermissionRequest) (*model.Permission, error)
+		GetPermissionsByRoleNameSvc(roleName string) ([]string, error)
+	}
+
+	// RoleService is an app role struct that consists of all the dependencies needed for role service
+	RoleService struct {
+		Context        context.Context
+		Config         *config.Configuration
+		Logger         *logrus.Logger
+		RoleRepo       repository.IRoleRepository
+		PermissionRepo repository.IPermissionRepository
+	}
+)
+
+// GetAllRoleSvc service layer for getting all role
+func (rs *RoleService) GetAllRoleSvc() ([]model.Role, error) {
+	data, err := rs.RoleRepo.GetAllRole()
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// CreateRoleSvc service layer for creating a role without any permission
+func (rs *RoleService) CreateRoleSvc(req model.CreateRoleRequest) (*model.Role, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if len(req.Name) < 3 || len(req.Name) > 30 {
+		return nil, model.ErrInvalidRequest
+	}
+
+	roleID, err := rs.RoleRepo.CreateRole(req.Name)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, model.ErrRoleExisted
+		}
+
+		return nil, err
+	}
+
+	return &model.Role{ID: roleID, Name: req.Name, Permissions: []string{}}, nil
+}
+
+// DeleteRoleSvc service layer for deleting a custom role which has no user
+func (rs *RoleService) DeleteRoleSvc(adminRoleName string, id uuid.UUID) error {
+	role, err := rs.getRole(id)
+	if err != nil {
+		return err
+	}
+
+	// registration & login depend on built-in role name
+	if role.Name == adminRoleName || role.Name == model.RoleAdmin || role.Name == model.RoleSeller || role.Name == model.RoleCustomer {
+		return model.ErrForbiddenDeleteRole
+	}
+
+	return rs.RoleRepo.DeleteRole(id)
+}
+
+// UpdateRoleMFASvc service layer for requiring or releasing mfa of every user in a role
+func (rs *RoleService) UpdateRoleMFASvc(id uuid.UUID, req model.UpdateRoleMFARequest) error {
+	_, err := rs.getRole(id)
+	if err != nil {
+		return err
+	}
+
+	return rs.RoleRepo.UpdateRoleRequireMFA(id, req.RequireMFA)
+}
+
+// UpdateRoleLapakLimitSvc service layer for capping lapak owned by each user of a role, existing lapak above the cap are kept
+func (rs *RoleService) UpdateRoleLapakLimitSvc(id uuid.UUID, req model.UpdateRoleLapakLimitRequest) error {
+	if req.MaxLapak != nil && *req.MaxLapak < 0 {
+		return model.ErrInvalidRequest
+	}
+
+	_, err := rs.getRole(id)
+	if err != nil {
+		return err
+	}
+
+	return rs.RoleRepo.UpdateRoleMaxLapak(id, req.MaxLapak)
+}
+
+// UpdateRolePermissionsSvc service layer for replacing every permission of a role
+func (rs *RoleService) UpdateRolePermissionsSvc(adminRoleName string, id uuid.UUID, req model.UpdateRolePermissionsRequest) (*model.Role, error) {
+	role, err := rs.getRole(id)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(req.Permissions))
+	permissions := make([]string, 0, len(req.Permissions))
+	for _, permission := range req.Permissions {
+		if !model.IsAllowedPermissionInput.MatchString(permission) {
+			return nil, model.ErrInvalidRequest
+		}
+
+		if !seen[permission] {
+			seen[permission] = true
+			permissions = append(permissions, permission)
+		}
+	}
+
+	// admin must not lock themselves out of role management
+	if role.Name == adminRoleName && !seen[model.PermissionRoleManage] {
+		return nil, model.ErrForbiddenRevokeRoleManage
+	}
+
+	err = rs.PermissionRepo.ReplaceRolePermissions(id, permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	return rs.getRole(id)
+}
+
+// AssignUserRoleSvc service layer for moving a user into another role, taking effect on their next session
+func (rs *RoleService) AssignUserRoleSvc(adminID uuid.UUID, userID uuid.UUID, req model.AssignUserRoleRequest) (*model.Role, error) {
+	// admin must not lock themselves out of role management
+	if adminID == userID {
+		return nil, model.ErrForbiddenAssignSelf
+	}
+
+	roleID, err := uuid.Parse(req.RoleID)
+	if err != nil {
+		return nil, model.ErrInvalidRequest
+	}
+
+	role, err := rs.getRole(roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = rs.RoleRepo.AssignUserRole(userID, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// GetAllPermissionSvc service layer for getting all permission
+func (rs *RoleService) GetAllPermissionSvc() ([]model.Permission, error) {
+	data, err := rs.PermissionRepo.GetAllPermission()
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// CreatePermissionSvc service layer for creating a permission
+func (rs *RoleService) CreatePermissionSvc(req model.CreatePermissionRequest) (*model.Permission, error) {
+	if !model.IsAllowedPermissionInput.MatchString(req.Name) || len(req.Description) > 100 {
+		return nil, model.ErrInvalidRequest
+	}
+
+	permissionID, err := rs.PermissionRepo.CreatePermission(req)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, model.ErrPermissionExisted
+		}
+
+		return nil, err
+	}
+
+	return &model.Permission{ID: permissionID, Name: req.Name, Description: req.Description}, nil
+}
+
+// GetPermissionsByRoleNameSvc service layer for getting every permission name granted into a role
+func (rs *RoleService) GetPermissionsByRoleNameSvc(roleName string) ([]string, error) {
+	data, err := rs.PermissionRepo.GetPermissionNamesByRoleName(roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// getRole responsible to getting a role by id, mapping missing role into ErrRoleNotFound
+func (rs *RoleService) getRole(id uuid.UUID) (*model.Role, error) {
+	role, err := rs.RoleRepo.GetRoleByID(id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, model.ErrRoleNotFound
+		}
+
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// authorizeOwner responsible to returning ErrForbiddenAccess unless principal owns the resource or is granted the bypass permission
+func authorizeOwner(principal model.Principal, ownerID uuid.UUID, anyPermission string) error {
+	if principal.UserID == ownerID {
+		return nil
+	}
+
+	if hasPermission(principal, anyPermission) {
+		return nil
+	}
+
+	return model.ErrForbiddenAccess
+}
+
+// hasPermission responsible to checking whether principal role is granted a permission
+func hasPermission(principal model.Principal, permission string) bool {
+	for _, granted := range principal.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+
+	return false
+}
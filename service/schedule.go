@@ -0,0 +1,396 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/model"
+	"github.com/wiormiw/GrowBaks/repository"
+)
+
+type (
+	// IScheduleService is an interface that has all the function to be implemented inside schedule service
+	IScheduleService interface {
+		GetLapakScheduleSvc(id uuid.UUID) (*model.LapakSchedule, error)
+		UpdateOpeningHoursSvc(principal model.Principal, id uuid.UUID, req model.UpdateOpeningHoursRequest) error
+		CreateHolidaySvc(principal model.Principal, id uuid.UUID, req model.LapakHoliday) error
+		DeleteHolidaySvc(principal model.Principal, id uuid.UUID, date string) error
+		ApplyScheduleSvc() (model.ScheduleResult, error)
+		ScheduleInterval() time.Duration
+	}
+
+	// ScheduleService is an app schedule struct that consists of all the dependencies needed for schedule service
+	ScheduleService struct {
+		Context      context.Context
+		Config       *config.Configuration
+		Logger       *logrus.Logger
+		ScheduleRepo repository.IScheduleRepository
+		LapakRepo    repository.ILapakRepository
+	}
+
+	// openingWindow is a single opening period of a lapak resolved into actual time
+	openingWindow struct {
+		start time.Time
+		end   time.Time
+	}
+)
+
+const (
+	// defaultLapakTimezone is used when lapak opening hours is given without timezone
+	defaultLapakTimezone = "Asia/Jakarta"
+	// defaultScheduleIntervalMinutes is used when SCHEDULE_INTERVAL_MINUTES is not set
+	defaultScheduleIntervalMinutes = 1
+	// maxOpeningHoursEntries is upper bound of opening window per lapak, three window a day
+	maxOpeningHoursEntries = 21
+	// maxHolidayNoteLength is upper bound of holiday note length
+	maxHolidayNoteLength = 255
+	// opensAtLookaheadDays is how far ahead next opening is searched, covering two week of holiday
+	opensAtLookaheadDays = 15
+	// scheduleTimeLayout is layout of opening & closing time
+	scheduleTimeLayout = "15:04"
+	// holidayDateLayout is layout of holiday date
+	holidayDateLayout = "2006-01-02"
+)
+
+// GetLapakScheduleSvc service layer for getting opening hours & upcoming holidays of a lapak along with its next opening when closed
+func (ss *ScheduleService) GetLapakScheduleSvc(id uuid.UUID) (*model.LapakSchedule, error) {
+	schedules, err := ss.ScheduleRepo.GetScheduleByLapakIDs([]uuid.UUID{id})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(schedules) == 0 {
+		return nil, model.ErrLapakNotFound
+	}
+
+	schedule := schedules[0]
+	if schedule.Status != "open" {
+		schedule.OpensAt = nextOpenAt(schedule, time.Now())
+	}
+
+	return &schedule, nil
+}
+
+// UpdateOpeningHoursSvc service layer for replacing weekly opening hours of a lapak, the new hours is applied on next scheduler run
+func (ss *ScheduleService) UpdateOpeningHoursSvc(principal model.Principal, id uuid.UUID, req model.UpdateOpeningHoursRequest) error {
+	err := ss.authorizeLapak(principal, id)
+	if err != nil {
+		return err
+	}
+
+	err = validateUpdateOpeningHoursRequest(&req)
+	if err != nil {
+		return err
+	}
+
+	return ss.ScheduleRepo.ReplaceOpeningHours(id, req.Timezone, req.Hours)
+}
+
+// CreateHolidaySvc service layer for adding a holiday into lapak, lapak stays closed on that date
+func (ss *ScheduleService) CreateHolidaySvc(principal model.Principal, id uuid.UUID, req model.LapakHoliday) error {
+	err := ss.authorizeLapak(principal, id)
+	if err != nil {
+		return err
+	}
+
+	schedules, err := ss.ScheduleRepo.GetScheduleByLapakIDs([]uuid.UUID{id})
+	if err != nil {
+		return err
+	}
+
+	if len(schedules) == 0 {
+		return model.ErrLapakNotFound
+	}
+
+	err = validateHolidayRequest(&req, time.Now().In(scheduleLocation(schedules[0])))
+	if err != nil {
+		return err
+	}
+
+	return ss.ScheduleRepo.UpsertHoliday(id, req)
+}
+
+// DeleteHolidaySvc service layer for removing a holiday of lapak
+func (ss *ScheduleService) DeleteHolidaySvc(principal model.Principal, id uuid.UUID, date string) error {
+	err := ss.authorizeLapak(principal, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := time.Parse(holidayDateLayout, date); err != nil {
+		return fmt.Errorf("%w, date must be formatted as YYYY-MM-DD", model.ErrInvalidRequest)
+	}
+
+	return ss.ScheduleRepo.DeleteHoliday(id, date)
+}
+
+// ApplyScheduleSvc service layer for opening & closing every lapak following its opening hours,
+// lapak is only touched when its scheduled status changes so manual open or close by seller lasts until the next boundary,
+// lapak which could not be opened because its seller is blacklisted or unverified keeps being retried until it can
+func (ss *ScheduleService) ApplyScheduleSvc() (model.ScheduleResult, error) {
+	schedules, err := ss.ScheduleRepo.GetActiveSchedules()
+	if err != nil {
+		return model.ScheduleResult{}, err
+	}
+
+	var (
+		now             = time.Now()
+		toOpen, toClose []uuid.UUID
+	)
+
+	for _, schedule := range schedules {
+		desired := "closed"
+		if isOpenAt(schedule, now) {
+			desired = "open"
+		}
+
+		if schedule.ScheduledStatus != nil && *schedule.ScheduledStatus == desired {
+			continue
+		}
+
+		if desired == "open" {
+			toOpen = append(toOpen, schedule.LapakID)
+		} else {
+			toClose = append(toClose, schedule.LapakID)
+		}
+	}
+
+	var result model.ScheduleResult
+
+	if len(toOpen) > 0 {
+		result.Opened, err = ss.ScheduleRepo.ApplyScheduledStatus(toOpen, "open", ss.Config.Const.RequireEmailVerification)
+		if err != nil {
+			return model.ScheduleResult{}, err
+		}
+	}
+
+	if len(toClose) > 0 {
+		result.Closed, err = ss.ScheduleRepo.ApplyScheduledStatus(toClose, "closed", ss.Config.Const.RequireEmailVerification)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if result.Opened > 0 || result.Closed > 0 {
+		ss.Logger.Info(fmt.Sprintf("Scheduled lapak status at %s : %d opened, %d closed", now.Format(time.RFC3339), result.Opened, result.Closed))
+	}
+
+	return result, nil
+}
+
+// ScheduleInterval responsible to returning how often schedule job is run
+func (ss *ScheduleService) ScheduleInterval() time.Duration {
+	interval := ss.Config.Const.ScheduleIntervalMinutes
+	if interval <= 0 {
+		interval = defaultScheduleIntervalMinutes
+	}
+
+	return time.Duration(interval) * time.Minute
+}
+
+// authorizeLapak responsible to checking lapak exists & principal is allowed to manage it
+func (ss *ScheduleService) authorizeLapak(principal model.Principal, id uuid.UUID) error {
+	lapak, err := ss.LapakRepo.GetLapakByID(id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return model.ErrLapakNotFound
+		}
+
+		return err
+	}
+
+	return authorizeOwner(principal, lapak.UserID, model.PermissionLapakManageAny)
+}
+
+// fillProductOpensAt responsible to setting next opening of every product whose lapak is not open, failing to load schedule only drops the hint
+func fillProductOpensAt(logger *logrus.Logger, scheduleRepo repository.IScheduleRepository, products []model.GetAllProductRequest) {
+	var lapakIDs []uuid.UUID
+
+	seen := map[uuid.UUID]bool{}
+	for _, product := range products {
+		if product.LapakStatus == "open" || seen[product.LapakID] {
+			continue
+		}
+
+		seen[product.LapakID] = true
+		lapakIDs = append(lapakIDs, product.LapakID)
+	}
+
+	if len(lapakIDs) == 0 {
+		return
+	}
+
+	schedules, err := scheduleRepo.GetScheduleByLapakIDs(lapakIDs)
+	if err != nil {
+		logger.Error(fmt.Errorf("fillProductOpensAt GetScheduleByLapakIDs ERROR : %v MSG : %s", err, err.Error()))
+		return
+	}
+
+	now := time.Now()
+	opensAt := map[uuid.UUID]*time.Time{}
+	for _, schedule := range schedules {
+		opensAt[schedule.LapakID] = nextOpenAt(schedule, now)
+	}
+
+	for i := range products {
+		if products[i].LapakStatus != "open" {
+			products[i].OpensAt = opensAt[products[i].LapakID]
+		}
+	}
+}
+
+// validateUpdateOpeningHoursRequest responsible to validating timezone & every opening window, time is normalized into HH:MM
+func validateUpdateOpeningHoursRequest(req *model.UpdateOpeningHoursRequest) error {
+	req.Timezone = strings.TrimSpace(req.Timezone)
+	if req.Timezone == "" {
+		req.Timezone = defaultLapakTimezone
+	}
+
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		return fmt.Errorf("%w, unknown timezone %s", model.ErrInvalidRequest, req.Timezone)
+	}
+
+	if len(req.Hours) > maxOpeningHoursEntries {
+		return fmt.Errorf("%w, at most %d opening hours is allowed", model.ErrInvalidRequest, maxOpeningHoursEntries)
+	}
+
+	for i := range req.Hours {
+		hours := &req.Hours[i]
+
+		if hours.DayOfWeek < 0 || hours.DayOfWeek > 6 {
+			return fmt.Errorf("%w, day_of_week must be between 0 (sunday) & 6 (saturday)", model.ErrInvalidRequest)
+		}
+
+		openTime, err := time.Parse(scheduleTimeLayout, strings.TrimSpace(hours.OpenTime))
+		if err != nil {
+			return fmt.Errorf("%w, open_time must be formatted as HH:MM", model.ErrInvalidRequest)
+		}
+
+		closeTime, err := time.Parse(scheduleTimeLayout, strings.TrimSpace(hours.CloseTime))
+		if err != nil {
+			return fmt.Errorf("%w, close_time must be formatted as HH:MM", model.ErrInvalidRequest)
+		}
+
+		if openTime.Equal(closeTime) {
+			return fmt.Errorf("%w, open_time & close_time must differ", model.ErrInvalidRequest)
+		}
+
+		hours.OpenTime = openTime.Format(scheduleTimeLayout)
+		hours.CloseTime = closeTime.Format(scheduleTimeLayout)
+	}
+
+	return nil
+}
+
+// validateHolidayRequest responsible to validating holiday date & note against current time of the lapak, date is normalized into YYYY-MM-DD
+func validateHolidayRequest(req *model.LapakHoliday, now time.Time) error {
+	date, err := time.Parse(holidayDateLayout, strings.TrimSpace(req.Date))
+	if err != nil {
+		return fmt.Errorf("%w, date must be formatted as YYYY-MM-DD", model.ErrInvalidRequest)
+	}
+
+	req.Date = date.Format(holidayDateLayout)
+
+	// YYYY-MM-DD compares in calendar order, now is already in lapak timezone
+	if req.Date < now.Format(holidayDateLayout) {
+		return fmt.Errorf("%w, date must not be before today in lapak timezone", model.ErrInvalidRequest)
+	}
+
+	req.Note = strings.TrimSpace(req.Note)
+
+	if len(req.Note) > maxHolidayNoteLength {
+		return fmt.Errorf("%w, note must be at most %d characters", model.ErrInvalidRequest, maxHolidayNoteLength)
+	}
+
+	return nil
+}
+
+// scheduleLocation responsible to loading timezone of lapak schedule, falling back into default timezone
+func scheduleLocation(schedule model.LapakSchedule) *time.Location {
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		loc, err = time.LoadLocation(defaultLapakTimezone)
+		if err != nil {
+			return time.UTC
+		}
+	}
+
+	return loc
+}
+
+// openingWindows responsible to resolving opening hours of every day starting from given day into actual time,
+// window starting on a holiday is skipped & window closing not after its opening runs into the next day
+func openingWindows(schedule model.LapakSchedule, from time.Time, days int) []openingWindow {
+	var (
+		loc      = scheduleLocation(schedule)
+		holidays = map[string]bool{}
+		windows  []openingWindow
+	)
+
+	for _, holiday := range schedule.Holidays {
+		holidays[holiday.Date] = true
+	}
+
+	year, month, day := from.In(loc).Date()
+	for i := 0; i < days; i++ {
+		date := time.Date(year, month, day+i, 0, 0, 0, 0, loc)
+		if holidays[date.Format(holidayDateLayout)] {
+			continue
+		}
+
+		for _, hours := range schedule.Hours {
+			if hours.DayOfWeek != int(date.Weekday()) {
+				continue
+			}
+
+			openTime, errOpen := time.Parse(scheduleTimeLayout, hours.OpenTime)
+			closeTime, errClose := time.Parse(scheduleTimeLayout, hours.CloseTime)
+			if errOpen != nil || errClose != nil {
+				continue
+			}
+
+			start := time.Date(year, month, day+i, openTime.Hour(), openTime.Minute(), 0, 0, loc)
+			end := time.Date(year, month, day+i, closeTime.Hour(), closeTime.Minute(), 0, 0, loc)
+			if !closeTime.After(openTime) {
+				end = time.Date(year, month, day+i+1, closeTime.Hour(), closeTime.Minute(), 0, 0, loc)
+			}
+
+			windows = append(windows, openingWindow{start: start, end: end})
+		}
+	}
+
+	return windows
+}
+
+// isOpenAt responsible to checking whether lapak is inside one of its opening window, yesterday is included for overnight window
+func isOpenAt(schedule model.LapakSchedule, now time.Time) bool {
+	for _, window := range openingWindows(schedule, now.AddDate(0, 0, -1), 2) {
+		if !now.Before(window.start) && now.Before(window.end) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nextOpenAt responsible to finding start of the next opening window, nil when lapak has no upcoming opening
+func nextOpenAt(schedule model.LapakSchedule, now time.Time) *time.Time {
+	if len(schedule.Hours) == 0 {
+		return nil
+	}
+
+	for _, window := range openingWindows(schedule, now, opensAtLookaheadDays) {
+		if window.start.After(now) {
+			start := window.start
+			return &start
+		}
+	}
+
+	return nil
+}
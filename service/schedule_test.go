@@ -0,0 +1,86 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wiormiw/GrowBaks/model"
+)
+
+func TestValidateHolidayRequestUsesLapakDate(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 23:30 UTC on 17 oct is already 18 oct in jakarta
+	now := time.Date(2026, 10, 17, 23, 30, 0, 0, time.UTC).In(jakarta)
+
+	cases := []struct {
+		date  string
+		valid bool
+	}{
+		{"2026-10-17", false},
+		{"2026-10-18", true},
+		{"2026-10-19", true},
+		{"18-10-2026", false},
+	}
+
+	for _, tc := range cases {
+		req := model.LapakHoliday{Date: tc.date}
+
+		err := validateHolidayRequest(&req, now)
+		if tc.valid && err != nil {
+			t.Errorf("%s: want valid, got %v", tc.date, err)
+		}
+
+		if !tc.valid && !errors.Is(err, model.ErrInvalidRequest) {
+			t.Errorf("%s: want ErrInvalidRequest, got %v", tc.date, err)
+		}
+	}
+}
+
+func TestScheduleOpeningWindows(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schedule := model.LapakSchedule{
+		Timezone: "Asia/Jakarta",
+		Hours: []model.OpeningHours{
+			{DayOfWeek: int(time.Monday), OpenTime: "08:00", CloseTime: "17:00"},
+			// friday night market runs past midnight
+			{DayOfWeek: int(time.Friday), OpenTime: "22:00", CloseTime: "02:00"},
+		},
+		Holidays: []model.LapakHoliday{{Date: "2026-10-19"}},
+	}
+
+	at := func(day, hour int) time.Time {
+		return time.Date(2026, 10, day, hour, 0, 0, 0, jakarta)
+	}
+
+	cases := []struct {
+		name    string
+		now     time.Time
+		open    bool
+		opensAt time.Time
+	}{
+		{"friday overnight after midnight", at(17, 1), true, at(23, 22)},
+		{"saturday after overnight window", at(17, 3), false, at(23, 22)},
+		{"monday holiday is skipped", at(19, 9), false, at(23, 22)},
+		{"friday before opening", at(16, 20), false, at(16, 22)},
+	}
+
+	for _, tc := range cases {
+		if got := isOpenAt(schedule, tc.now); got != tc.open {
+			t.Errorf("%s: isOpenAt = %v, want %v", tc.name, got, tc.open)
+		}
+
+		got := nextOpenAt(schedule, tc.now)
+		if got == nil || !got.Equal(tc.opensAt) {
+			t.Errorf("%s: nextOpenAt = %v, want %v", tc.name, got, tc.opensAt)
+		}
+	}
+}
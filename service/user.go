@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4"
@@ -10,36 +11,41 @@ import (
 	"github.com/wiormiw/GrowBaks/helper"
 	"github.com/wiormiw/GrowBaks/model"
 	"github.com/wiormiw/GrowBaks/repository"
+	"github.com/wiormiw/GrowBaks/util"
 )
 
 type (
 	// IUserService is an interface that has all the function to be implemented inside user service
 	IUserService interface {
-		GetAllUserSvc(search string) ([]model.ViewUserResponse, error)
+		GetAllUserSvc(search string, includeDeleted bool, page model.PageRequest) ([]model.ViewUserResponse, int, error)
 		GetUserByIDSvc(id uuid.UUID) (*model.ViewUserResponse, error)
-		GetUserProfileByIDSvc(id uuid.UUID) (*model.ViewUserProfileResponse, error)
-		UpdateUserByIDSvc(id uuid.UUID, req model.UpdateUserRequest) error
-		UpdateUserProfileByIDSvc(id uuid.UUID, req model.UpdateUserProfileRequest) error
+		GetUserProfileByIDSvc(principal model.Principal, id uuid.UUID) (*model.ViewUserProfileResponse, error)
+		UpdateUserByIDSvc(principal model.Principal, id uuid.UUID, req model.UpdateUserRequest) error
+		UpdateUserProfileByIDSvc(principal model.Principal, id uuid.UUID, req model.UpdateUserProfileRequest) error
 		DeleteUserByIDSvc(id uuid.UUID) error
+		RestoreUserByIDSvc(id uuid.UUID) error
 	}
 
 	// UserService is an app user check struct that consists of all the dependencies needed for user service
 	UserService struct {
-		Context  context.Context
-		Config   *config.Configuration
-		Logger   *logrus.Logger
-		UserRepo repository.IUserRepository
+		Context      context.Context
+		Config       *config.Configuration
+		Logger       *logrus.Logger
+		UserRepo     repository.IUserRepository
+		LocationRepo repository.ILocationRepository
+		AuthRepo     repository.IAuthRepository
+		Mailer       util.Mailer
 	}
 )
 
 // GetAllUserSvc service layer for getting all user
-func (us *UserService) GetAllUserSvc(search string) ([]model.ViewUserResponse, error) {
-	data, err := us.UserRepo.GetAll(search)
+func (us *UserService) GetAllUserSvc(search string, includeDeleted bool, page model.PageRequest) ([]model.ViewUserResponse, int, error) {
+	data, total, err := us.UserRepo.GetAll(search, includeDeleted, page)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return data, nil
+	return data, total, nil
 }
 
 // GetUserByIDSvc service layer for get a user by id
@@ -56,8 +62,13 @@ func (us *UserService) GetUserByIDSvc(id uuid.UUID) (*model.ViewUserResponse, er
 	return data, nil
 }
 
-// GetUserProfileByIDSvc service layer for get a user profile by id
-func (us *UserService) GetUserProfileByIDSvc(id uuid.UUID) (*model.ViewUserProfileResponse, error) {
+// GetUserProfileByIDSvc service layer for get a user profile by id, only the user itself or caller granted user:read may see it
+func (us *UserService) GetUserProfileByIDSvc(principal model.Principal, id uuid.UUID) (*model.ViewUserProfileResponse, error) {
+	err := authorizeOwner(principal, id, model.PermissionUserRead)
+	if err != nil {
+		return nil, err
+	}
+
 	data, err := us.UserRepo.GetProfileByID(id)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -70,9 +81,14 @@ func (us *UserService) GetUserProfileByIDSvc(id uuid.UUID) (*model.ViewUserProfi
 	return data, nil
 }
 
-// UpdateUserByIDSvc service layer for update user by id
-func (us *UserService) UpdateUserByIDSvc(id uuid.UUID, req model.UpdateUserRequest) error {
-	_, err := us.UserRepo.GetByID(id)
+// UpdateUserByIDSvc service layer for update user by id, only the user itself or caller granted user:manage:any may update it
+func (us *UserService) UpdateUserByIDSvc(principal model.Principal, id uuid.UUID, req model.UpdateUserRequest) error {
+	err := authorizeOwner(principal, id, model.PermissionUserManageAny)
+	if err != nil {
+		return err
+	}
+
+	user, err := us.UserRepo.GetByID(id)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return model.ErrUserNotFound
@@ -81,36 +97,39 @@ func (us *UserService) UpdateUserByIDSvc(id uuid.UUID, req model.UpdateUserReque
 		return err
 	}
 
-	_, err = us.UserRepo.GetByEmail(req.Email)
+	err = checkEmailAvailable(us.UserRepo, req.Email, id)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			err := validateUpdateUserRequest(&req)
-			if err != nil {
-				return err
-			}
-
-			req.Password, err = helper.HashPassword(req.Password)
-			if err != nil {
-				return err
-			}
-
-			err = us.UserRepo.UpdateByID(id, req.FullName, req.Email, req.Password)
-			if err != nil {
-				return err
-			}
+		return err
+	}
 
-			return nil
+	err = validateUpdateUserRequest(&req)
+	if err != nil {
+		return err
+	}
 
-		}
+	req.Password, err = helper.HashPassword(req.Password)
+	if err != nil {
+		return err
+	}
 
+	err = us.UserRepo.UpdateByID(id, req.FullName, req.Email, req.Password)
+	if err != nil {
 		return err
 	}
 
-	return model.ErrEmailExisted
+	us.reverifyChangedEmail(user, req.FullName, req.Email)
+
+	return nil
 }
 
-func (us *UserService) UpdateUserProfileByIDSvc(id uuid.UUID, req model.UpdateUserProfileRequest) error {
-	_, err := us.UserRepo.GetByID(id)
+// UpdateUserProfileByIDSvc service layer for update user profile by id, only the user itself or caller granted user:manage:any may update it
+func (us *UserService) UpdateUserProfileByIDSvc(principal model.Principal, id uuid.UUID, req model.UpdateUserProfileRequest) error {
+	err := authorizeOwner(principal, id, model.PermissionUserManageAny)
+	if err != nil {
+		return err
+	}
+
+	user, err := us.UserRepo.GetByID(id)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return model.ErrUserNotFound
@@ -119,27 +138,30 @@ func (us *UserService) UpdateUserProfileByIDSvc(id uuid.UUID, req model.UpdateUs
 		return err
 	}
 
-	_, err = us.UserRepo.GetByEmail(req.Email)
+	err = checkEmailAvailable(us.UserRepo, req.Email, id)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			err = validateUpdateProfileUserRequest(&req)
-			if err != nil {
-				return err
-			}
-
-			err = us.UserRepo.UpdateProfileByID(id, req.Email, req.FullName, req.TanggalLahir, req.Gender, req.Telepon)
-			if err != nil {
-				return err
-			}
+		return err
+	}
 
-			return nil
+	err = validateUpdateProfileUserRequest(&req)
+	if err != nil {
+		return err
+	}
 
-		}
+	// daerah of new location drives product & lapak listing of the user from now on
+	locationID, err := resolveLocationID(us.LocationRepo, req.LocationID)
+	if err != nil {
+		return err
+	}
 
+	err = us.UserRepo.UpdateProfileByID(id, req.Email, req.FullName, req.TanggalLahir, req.Gender, req.Telepon, locationID, req.Latitude, req.Longitude)
+	if err != nil {
 		return err
 	}
 
-	return model.ErrEmailExisted
+	us.reverifyChangedEmail(user, req.FullName, req.Email)
+
+	return nil
 }
 
 // DeleteUserByIDSvc service layer for delete user by id
@@ -161,6 +183,42 @@ func (us *UserService) DeleteUserByIDSvc(id uuid.UUID) error {
 	return nil
 }
 
+// RestoreUserByIDSvc service layer for restoring a soft deleted user by id
+func (us *UserService) RestoreUserByIDSvc(id uuid.UUID) error {
+	return us.UserRepo.RestoreByID(id)
+}
+
+// reverifyChangedEmail responsible to mailing a new verification token when user changed email, repository already reset its verification
+func (us *UserService) reverifyChangedEmail(user *model.ViewUserResponse, fullName string, email string) {
+	if user.Email == email {
+		return
+	}
+
+	// email is already changed, failed delivery can be retried through resend endpoint
+	err := sendEmailVerification(us.Config, us.AuthRepo, us.Mailer, user.ID, fullName, email)
+	if err != nil {
+		us.Logger.Error(fmt.Errorf("UserService.reverifyChangedEmail sendEmailVerification ERROR : %v MSG : %s", err, err.Error()))
+	}
+}
+
+// checkEmailAvailable responsible to rejecting email already used by another user, resubmitting own email is allowed
+func checkEmailAvailable(userRepo repository.IUserRepository, email string, id uuid.UUID) error {
+	user, err := userRepo.GetByEmail(email)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+
+		return err
+	}
+
+	if user.ID != id {
+		return model.ErrEmailExisted
+	}
+
+	return nil
+}
+
 // validateUpdateUserRequest responsible to validating update user
 func validateUpdateUserRequest(req *model.UpdateUserRequest) error {
 	if len(req.FullName) < 5 {
@@ -199,5 +257,5 @@ func validateUpdateProfileUserRequest(req *model.UpdateUserProfileRequest) error
 		return model.ErrInvalidRequest
 	}
 
-	return nil
+	return validateCoordinate(req.Latitude, req.Longitude)
 }
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+	"github.com/wiormiw/GrowBaks/model"
+	"github.com/wiormiw/GrowBaks/repository"
+)
+
+type (
+	// stubUserRepo holds a single verified user, email_verified_at is reset the way repository does on email change
+	stubUserRepo struct {
+		repository.IUserRepository
+		user *model.ViewUserResponse
+	}
+
+	stubAuthRepo struct {
+		repository.IAuthRepository
+		verifications []uuid.UUID
+	}
+
+	stubMailer struct {
+		sent []string
+	}
+)
+
+func (ur *stubUserRepo) GetByID(uuid.UUID) (*model.ViewUserResponse, error) {
+	user := *ur.user
+	return &user, nil
+}
+
+func (ur *stubUserRepo) GetByEmail(email string) (*model.ViewUserResponse, error) {
+	if email != ur.user.Email {
+		return nil, pgx.ErrNoRows
+	}
+
+	return ur.GetByID(ur.user.ID)
+}
+
+func (ur *stubUserRepo) UpdateByID(_ uuid.UUID, _ string, email string, _ string) error {
+	ur.setEmail(email)
+	return nil
+}
+
+func (ur *stubUserRepo) UpdateProfileByID(_ uuid.UUID, email string, _ string, _ string, _ string, _ string, _ *uuid.UUID, _ *float64, _ *float64) error {
+	ur.setEmail(email)
+	return nil
+}
+
+func (ur *stubUserRepo) setEmail(email string) {
+	if ur.user.Email != email {
+		ur.user.EmailVerifiedAt = nil
+	}
+
+	ur.user.Email = email
+}
+
+func (ar *stubAuthRepo) CreateEmailVerification(userID uuid.UUID, _ string, _ time.Time) error {
+	ar.verifications = append(ar.verifications, userID)
+	return nil
+}
+
+func (m *stubMailer) Send(to string, _ string, _ string) error {
+	m.sent = append(m.sent, to)
+	return nil
+}
+
+func TestUpdateUserReverifiesChangedEmail(t *testing.T) {
+	var (
+		userID    = uuid.New()
+		principal = model.Principal{UserID: userID}
+		account   = func(email string) model.UpdateUserRequest {
+			return model.UpdateUserRequest{FullName: "Pemilik Lapak", Email: email, Password: "rahasia123"}
+		}
+		profile = func(email string) model.UpdateUserProfileRequest {
+			return model.UpdateUserProfileRequest{FullName: "Pemilik Lapak", TanggalLahir: "2000-01-01", Gender: "perempuan", Telepon: "081234567890", Email: email}
+		}
+	)
+
+	updates := []struct {
+		name   string
+		update func(us *UserService, email string) error
+	}{
+		{"account", func(us *UserService, email string) error {
+			return us.UpdateUserByIDSvc(principal, userID, account(email))
+		}},
+		{"profile", func(us *UserService, email string) error {
+			return us.UpdateUserProfileByIDSvc(principal, userID, profile(email))
+		}},
+	}
+
+	for _, tc := range updates {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				verifiedAt = time.Now()
+				logger     = logrus.New()
+				userRepo   = &stubUserRepo{user: &model.ViewUserResponse{ID: userID, Email: "lama@growbaks.test", EmailVerifiedAt: &verifiedAt}}
+				authRepo   = &stubAuthRepo{}
+				mailer     = &stubMailer{}
+			)
+
+			logger.SetOutput(io.Discard)
+
+			us := &UserService{Context: context.Background(), Config: &config.Configuration{Const: &config.Constants{}}, Logger: logger,
+				UserRepo: userRepo, AuthRepo: authRepo, Mailer: mailer}
+
+			if err := tc.update(us, "lama@growbaks.test"); err != nil {
+				t.Fatalf("resubmit own email: %v", err)
+			}
+
+			if userRepo.user.EmailVerifiedAt == nil || len(mailer.sent) != 0 {
+				t.Fatalf("unchanged email lost verification or was mailed %v", mailer.sent)
+			}
+
+			if err := tc.update(us, "baru@growbaks.test"); err != nil {
+				t.Fatalf("change email: %v", err)
+			}
+
+			if userRepo.user.EmailVerifiedAt != nil {
+				t.Errorf("changed email is still verified")
+			}
+
+			if len(authRepo.verifications) != 1 || authRepo.verifications[0] != userID {
+				t.Errorf("verification token stored for %v, want %s", authRepo.verifications, userID)
+			}
+
+			if len(mailer.sent) != 1 || mailer.sent[0] != "baru@growbaks.test" {
+				t.Errorf("verification mailed to %v, want baru@growbaks.test", mailer.sent)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"github.com/wiormiw/GrowBaks/config"
 	"github.com/wiormiw/GrowBaks/helper"
 	"github.com/wiormiw/GrowBaks/model"
@@ -15,10 +16,14 @@ import (
 type (
 	// DecodePayloadData consists decoded payload data
 	DecodePayloadData struct {
-		UserID   string `json:"user_id"`
-		FullName string `json:"full_name"`
-		Email    string `json:"email"`
-		RoleName string `json:"role_name"`
+		UserID    string    `json:"user_id"`
+		FullName  string    `json:"full_name"`
+		Email     string    `json:"email"`
+		RoleName  string    `json:"role_name"`
+		TokenID   string    `json:"jti"`
+		ExpiredAt time.Time `json:"exp"`
+
+		MFAPending bool `json:"mfa_pending"`
 	}
 )
 
@@ -28,8 +33,12 @@ const (
 	payloadEmail    string = "email"
 	payloadRoleName string = "role_name"
 	payloadExpires  string = "exp"
+	payloadTokenID  string = "jti"
 
-	JWTExpire time.Duration = time.Duration(7) * time.Hour
+	payloadMFAPending string = "mfa_pending"
+
+	JWTExpire          time.Duration = time.Duration(15) * time.Minute
+	RefreshTokenExpire time.Duration = time.Duration(7*24) * time.Hour
 )
 
 // BuildJWT return signed claims token JWT with defined expiration times in configuration
@@ -40,6 +49,9 @@ func BuildJWT(cfg *config.Configuration, user *model.AuthUserDetails) (string, e
 	claims[payloadEmail] = user.Email
 	claims[payloadRoleName] = user.RoleName
 	claims[payloadExpires] = time.Now().Add(JWTExpire).Unix()
+	claims[payloadTokenID] = uuid.NewString()
+	// role require mfa but user not enrolled yet, token only usable for enrolling
+	claims[payloadMFAPending] = user.RequireMFA && user.TOTPEnabledAt == nil
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
@@ -72,20 +84,22 @@ func ValidateJWT(ctx *fiber.Ctx) (DecodePayloadData, error) {
 	claims := validToken.Claims.(jwt.MapClaims)
 
 	// Check is token expired or not
-	if expInt, ok := claims[payloadExpires].(float64); ok {
-		now := time.Now().Unix()
-		if now > int64(expInt) {
-			return DecodePayloadData{}, model.ErrTokenExpire
-		}
-	} else {
+	expInt, ok := claims[payloadExpires].(float64)
+	if !ok {
 		return DecodePayloadData{}, model.ErrTypeAssertion
 	}
 
+	if time.Now().Unix() > int64(expInt) {
+		return DecodePayloadData{}, model.ErrTokenExpire
+	}
+
 	decodePayload, err := insertPayloadJWT(claims)
 	if err != nil {
 		return DecodePayloadData{}, err
 	}
 
+	decodePayload.ExpiredAt = time.Unix(int64(expInt), 0)
+
 	return decodePayload, nil
 }
 
@@ -127,5 +141,16 @@ func insertPayloadJWT(claims jwt.MapClaims) (DecodePayloadData, error) {
 		return DecodePayloadData{}, model.ErrTypeAssertion
 	}
 
+	if tokenID, ok := claims[payloadTokenID].(string); ok {
+		decodePayloadData.TokenID = tokenID
+	} else {
+		return DecodePayloadData{}, model.ErrTypeAssertion
+	}
+
+	// optional claim, token issued before mfa existed has no pending flag
+	if mfaPending, ok := claims[payloadMFAPending].(bool); ok {
+		decodePayloadData.MFAPending = mfaPending
+	}
+
 	return decodePayloadData, nil
 }
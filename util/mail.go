@@ -0,0 +1,113 @@
+package util
+
+import (
+	"fmt"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/wiormiw/GrowBaks/config"
+)
+
+const (
+	mailDriverSMTP string = "smtp"
+)
+
+type (
+	// Mailer is an interface that has all the function to be implemented by any mail sender
+	Mailer interface {
+		Send(to string, subject string, body string) error
+	}
+
+	// SMTPMailer is a mailer delivering message through SMTP server with PLAIN auth
+	SMTPMailer struct {
+		Host     string
+		Port     int
+		Username string
+		Password string
+		From     string
+	}
+
+	// FileMailer is a mailer for development & testing, writing message into Dir or console when Dir is empty
+	FileMailer struct {
+		Dir    string
+		From   string
+		Logger *logrus.Logger
+	}
+)
+
+// NewMailer return mailer implementation based on configured driver
+func NewMailer(cfg *config.Configuration, logger *logrus.Logger) Mailer {
+	if cfg.Mail.Driver == mailDriverSMTP {
+		return &SMTPMailer{
+			Host:     cfg.Mail.SMTPHost,
+			Port:     cfg.Mail.SMTPPort,
+			Username: cfg.Mail.SMTPUser,
+			Password: cfg.Mail.SMTPPassword,
+			From:     cfg.Mail.From,
+		}
+	}
+
+	return &FileMailer{
+		Dir:    cfg.Mail.Dir,
+		From:   cfg.Mail.From,
+		Logger: logger,
+	}
+}
+
+// Send will deliver message through SMTP server
+func (sm *SMTPMailer) Send(to string, subject string, body string) error {
+	var auth smtp.Auth
+	if sm.Username != "" {
+		auth = smtp.PlainAuth("", sm.Username, sm.Password, sm.Host)
+	}
+
+	addr := fmt.Sprintf("%s:%d", sm.Host, sm.Port)
+	err := smtp.SendMail(addr, auth, sm.From, []string{to}, buildMessage(sm.From, to, subject, body))
+	if err != nil {
+		return fmt.Errorf("SMTPMailer.Send ERROR : %w", err)
+	}
+
+	return nil
+}
+
+// Send will write message into a .eml file inside Dir, or into logger when Dir is empty
+func (fm *FileMailer) Send(to string, subject string, body string) error {
+	msg := buildMessage(fm.From, to, subject, body)
+
+	if fm.Dir == "" {
+		fm.Logger.Info(fmt.Sprintf("FileMailer.Send\n%s", msg))
+		return nil
+	}
+
+	err := os.MkdirAll(fm.Dir, 0o755)
+	if err != nil {
+		return fmt.Errorf("FileMailer.Send MkdirAll ERROR : %w", err)
+	}
+
+	name := fmt.Sprintf("%d_%s.eml", time.Now().UnixNano(), strings.NewReplacer("@", "_at_", "/", "_").Replace(to))
+	err = os.WriteFile(filepath.Join(fm.Dir, name), msg, 0o644)
+	if err != nil {
+		return fmt.Errorf("FileMailer.Send WriteFile ERROR : %w", err)
+	}
+
+	return nil
+}
+
+// buildMessage will building RFC 822 plain text message
+func buildMessage(from string, to string, subject string, body string) []byte {
+	var sb strings.Builder
+
+	sb.WriteString("From: " + from + "\r\n")
+	sb.WriteString("To: " + to + "\r\n")
+	sb.WriteString("Subject: " + subject + "\r\n")
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	sb.WriteString("\r\n")
+	sb.WriteString(body)
+
+	return []byte(sb.String())
+}
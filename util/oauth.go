@@ -0,0 +1,123 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/wiormiw/GrowBaks/model"
+)
+
+type (
+	// OAuthProvider consists configuration of an oauth2/oidc provider using authorization code flow with PKCE
+	OAuthProvider struct {
+		ClientID     string
+		ClientSecret string
+		RedirectURL  string
+		AuthURL      string
+		TokenURL     string
+		UserInfoURL  string
+		Scopes       []string
+		HTTPClient   *http.Client
+	}
+
+	// oauthTokenResponse consists token endpoint response
+	oauthTokenResponse struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		IDToken     string `json:"id_token"`
+	}
+)
+
+// AuthCodeURL return provider consent page url bound into state & pkce verifier
+func (op *OAuthProvider) AuthCodeURL(state string, codeVerifier string) string {
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", op.ClientID)
+	params.Set("redirect_uri", op.RedirectURL)
+	params.Set("scope", strings.Join(op.Scopes, " "))
+	params.Set("state", state)
+	params.Set("code_challenge", PKCEChallenge(codeVerifier))
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(op.AuthURL, "?") {
+		separator = "&"
+	}
+
+	return op.AuthURL + separator + params.Encode()
+}
+
+// Exchange will trading authorization code & pkce verifier into provider access token
+func (op *OAuthProvider) Exchange(code string, codeVerifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", op.RedirectURL)
+	form.Set("client_id", op.ClientID)
+	form.Set("client_secret", op.ClientSecret)
+	form.Set("code_verifier", codeVerifier)
+
+	resp, err := op.HTTPClient.PostForm(op.TokenURL, form)
+	if err != nil {
+		return "", fmt.Errorf("OAuthProvider.Exchange ERROR : %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w, token endpoint status %d", model.ErrInvalidExchange, resp.StatusCode)
+	}
+
+	var token oauthTokenResponse
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return "", fmt.Errorf("OAuthProvider.Exchange Decode ERROR : %w", err)
+	}
+
+	if token.AccessToken == "" {
+		return "", model.ErrInvalidExchange
+	}
+
+	return token.AccessToken, nil
+}
+
+// UserInfo will fetching oidc userinfo of access token owner
+func (op *OAuthProvider) UserInfo(accessToken string) (*model.OAuthUserInfo, error) {
+	req, err := http.NewRequest(http.MethodGet, op.UserInfoURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("OAuthProvider.UserInfo NewRequest ERROR : %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := op.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("OAuthProvider.UserInfo ERROR : %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w, userinfo endpoint status %d", model.ErrInvalidExchange, resp.StatusCode)
+	}
+
+	var userInfo model.OAuthUserInfo
+	err = json.NewDecoder(resp.Body).Decode(&userInfo)
+	if err != nil {
+		return nil, fmt.Errorf("OAuthProvider.UserInfo Decode ERROR : %w", err)
+	}
+
+	if userInfo.Subject == "" || userInfo.Email == "" {
+		return nil, model.ErrInvalidExchange
+	}
+
+	return &userInfo, nil
+}
+
+// PKCEChallenge return S256 code challenge of a pkce verifier
+func PKCEChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,89 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	totpDigits     int           = 6
+	totpPeriod     time.Duration = time.Duration(30) * time.Second
+	totpSecretSize int           = 20
+	// totpSkew accepting previous & next step to tolerate clock drift between server and authenticator
+	totpSkew int64 = 1
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret return a new random base32 TOTP secret
+func GenerateTOTPSecret() (string, error) {
+	secret := make([]byte, totpSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// TOTPProvisioningURI return otpauth uri which rendered as QR code by authenticator app
+func TOTPProvisioningURI(issuer string, account string, secret string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprintf("%d", totpDigits))
+	params.Set("period", fmt.Sprintf("%d", int(totpPeriod.Seconds())))
+
+	label := url.PathEscape(issuer + ":" + account)
+
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// ValidateTOTP will checking TOTP code against secret at given time, returning matched time step
+func ValidateTOTP(secret string, code string, at time.Time) (int64, bool) {
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false
+	}
+
+	current := at.Unix() / int64(totpPeriod.Seconds())
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(generateTOTP(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// generateTOTP will generating RFC 6238 code of a time step
+func generateTOTP(key []byte, step int64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", totpDigits, value%mod)
+}